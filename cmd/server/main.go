@@ -1,48 +1,100 @@
 package main
 
 import (
+	"bloggingplatformapi/internal/cache"
 	"bloggingplatformapi/internal/config"
+	"bloggingplatformapi/internal/feed"
+	"bloggingplatformapi/internal/grpcapi"
+	"bloggingplatformapi/internal/health"
+	"bloggingplatformapi/internal/ratelimit"
+	"bloggingplatformapi/internal/repository"
 	"bloggingplatformapi/internal/routes"
+	"bloggingplatformapi/internal/services"
+	"bloggingplatformapi/internal/tracing"
 	"bloggingplatformapi/internal/utils"
+	"bloggingplatformapi/migrations"
 	"bloggingplatformapi/pkg/db"
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
 	log "github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"google.golang.org/grpc"
 )
 
 func main() {
-	// Initialize Logrus
-	logger := logrus.New()
-	logger.SetFormatter(&logrus.JSONFormatter{})
+	// "config print" shows the effective configuration instead of starting the server
+	if len(os.Args) > 2 && os.Args[1] == "config" && os.Args[2] == "print" {
+		os.Exit(printConfig(os.Args[3:]))
+	}
+
+	// Initialize Logrus; the standard logger is shared by the middleware and request-scoped loggers
+	logger := log.StandardLogger()
+	logger.SetFormatter(&log.JSONFormatter{})
 	logger.SetOutput(os.Stdout)
 
-	// Load configuration
-	cfg, err := config.LoadConfig()
+	// Load configuration from defaults, config file, environment and command-line flags
+	configManager, err := config.NewManager(os.Args[1:])
+	if errors.Is(err, pflag.ErrHelp) {
+		return
+	}
 	if err != nil {
 		log.Fatalf("Could not load config: %v", err)
 	}
+	cfg := configManager.Current()
+
+	if cfg.ConfigFile != "" {
+		log.Infof("Loaded config file %s", cfg.ConfigFile)
+	} else {
+		log.Info("No config file found, using defaults, environment and flags")
+	}
 
-	log.Info("Loaded config file")
+	level, _ := log.ParseLevel(cfg.LogLevel) // Validated by config.LoadConfig
+	logger.SetLevel(level)
 
-	// Initialize database
-	database, err := db.InitDB(cfg.DatabaseURL)
+	// Initialize tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg)
 	if err != nil {
-		log.Fatalf("Could not connect to the database: %v", err)
+		log.Fatalf("Could not initialize tracing: %v", err)
 	}
-	defer func(database *sql.DB) {
-		err := database.Close()
-		if err != nil {
-			log.Fatalf("Could not close database: %v", err)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Errorf("Could not flush traces: %v", err)
 		}
-	}(database)
+	}()
+
+	log.Infof("Tracing initialized with %s exporter", cfg.TracingExporter)
 
-	log.Info("Database initialized")
+	// Initialize storage; readiness requires the database and an up-to-date schema
+	checker := health.NewChecker(cfg.HealthCheckTimeout)
+	var deps routes.Dependencies
+	if cfg.StorageBackend == config.StorageMemory {
+		log.Warn("Using in-memory storage; blogs are lost when the server stops")
+		blogs := repository.NewMemoryBlogRepository()
+		deps.BlogRepository, deps.UnitOfWork = blogs, blogs.UnitOfWork()
+	} else {
+		dbRouter := openDatabase(cfg)
+		defer func(dbRouter *db.Router) {
+			err := dbRouter.Close()
+			if err != nil {
+				log.Fatalf("Could not close database: %v", err)
+			}
+		}(dbRouter)
+		registerDatabaseChecks(checker, dbRouter)
+		deps.DB, deps.DBRouter = dbRouter.Primary(), dbRouter
+	}
 
 	// Set Gin to release mode if not in development
 	if cfg.Environment != "development" {
@@ -50,27 +102,225 @@ func main() {
 	}
 
 	// Initialize Gin router
-	router := gin.Default()
+	router := gin.New()
+
+	// Only trust X-Forwarded-For from configured proxies so clients cannot spoof their IP
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid trusted proxies: %v", err)
+	}
 
-	// Add Logrus logging middleware
-	router.Use(utils.GinLogrus(logger), gin.Recovery())
+	// Add tracing, request ID and Logrus logging middleware.
+	// Tracing runs first so request IDs and request logs carry the trace ID.
+	router.Use(otelgin.Middleware(cfg.ServiceName), utils.RequestID(logger), utils.GinLogrus(logger), gin.Recovery())
 
 	// Setup routes
-	routes.SetupRoutes(router, database)
+	corsHandler := routes.NewCORSHandler(cfg)
+	rateLimiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.LimitsFromConfig(cfg))
+	deps.CORS, deps.RateLimiter = corsHandler, rateLimiter
+	if cfg.CacheEnabled {
+		deps.Cache, deps.CacheTTL = cache.NewLRU(cfg.CacheMaxEntries), cfg.CacheTTL
+	}
+	deps.Feed = feed.Site{
+		Title:       cfg.FeedTitle,
+		Description: cfg.FeedDescription,
+		URL:         cfg.FeedSiteURL,
+		PostURL:     cfg.FeedPostURL,
+		Author:      cfg.FeedAuthor,
+		Language:    cfg.FeedLanguage,
+	}
+	deps.FeedMaxItems, deps.SitemapMaxURLs = cfg.FeedMaxItems, cfg.SitemapMaxURLs
+	deps.ExcerptLength = cfg.ExcerptLength
+	deps.GraphQLMaxDepth, deps.GraphQLMaxComplexity = cfg.GraphQLMaxDepth, cfg.GraphQLMaxComplexity
+	// The HTTP and gRPC servers share one blog service, and with it its cache
+	deps.BlogService = routes.NewBlogService(deps)
+	routes.SetupHealthRoutes(router, checker)
+	routes.SetupRoutes(router, deps)
+	grpcServer := startGRPCServer(cfg, deps.BlogService, logger)
+
+	// Apply reloaded log level, rate limits and CORS policy without a restart
+	configManager.Subscribe(func(cfg *config.Config) {
+		level, _ := log.ParseLevel(cfg.LogLevel) // Validated before reloads are applied
+		logger.SetLevel(level)
+		rateLimiter.SetLimits(ratelimit.LimitsFromConfig(cfg))
+		corsHandler.Update(cfg)
+	})
+	watchConfig(configManager)
 
 	// Create custom HTTP server with timeouts
 	server := &http.Server{
 		Addr:              ":" + cfg.Port,
 		Handler:           router,
-		ReadTimeout:       5 * time.Second,
-		ReadHeaderTimeout: 5 * time.Second,
-		WriteTimeout:      5 * time.Second,
-		IdleTimeout:       5 * time.Second,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
 	}
 
 	// Start server
-	log.Printf("Server running on port %s", cfg.Port)
-	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-		log.Fatalf("Could not start server: %v", err)
+	go func() {
+		log.Printf("Server running on port %s", cfg.Port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Could not start server: %v", err)
+		}
+	}()
+
+	// Wait for a termination signal, then shut down gracefully
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	shutdown(server, grpcServer, checker, cfg)
+}
+
+// startGRPCServer serves the blog API over gRPC on GRPC_PORT, exiting if that fails.
+// It returns nil if GRPC_PORT is empty.
+func startGRPCServer(cfg *config.Config, blogService services.BlogService, logger *log.Logger) *grpc.Server {
+	if cfg.GRPCPort == "" {
+		return nil
+	}
+	entries := make([]string, len(cfg.GRPCAuthTokens))
+	for i, token := range cfg.GRPCAuthTokens {
+		entries[i] = token.Value()
+	}
+	tokens, err := grpcapi.ParseTokens(entries)
+	if err != nil {
+		log.Fatalf("Invalid gRPC auth tokens: %v", err)
+	}
+	if len(tokens) == 0 {
+		log.Warn("GRPC_ALLOW_ANONYMOUS is set; gRPC calls are not authenticated")
+	}
+
+	grpcServer, err := grpcapi.NewServer(blogService, grpcapi.Options{Logger: logger, Tokens: tokens})
+	if err != nil {
+		log.Fatalf("Could not create gRPC server: %v", err)
+	}
+	listener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		log.Fatalf("Could not listen for gRPC: %v", err)
+	}
+	go func() {
+		log.Printf("gRPC server running on port %s", cfg.GRPCPort)
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Fatalf("Could not start gRPC server: %v", err)
+		}
+	}()
+	return grpcServer
+}
+
+// openDatabase connects to the primary database and opens the read replicas, exiting if that fails.
+// A SQLite database is created and migrated on startup, since it lives next to the server.
+func openDatabase(cfg *config.Config) *db.Router {
+	driver, _ := db.ParseDataSource(cfg.DatabaseURL.Value())
+	dbOptions := db.DefaultOptions()
+	dbOptions.MaxOpenConns = cfg.DBMaxOpenConns
+	dbOptions.MaxIdleConns = cfg.DBMaxIdleConns
+	dbOptions.ConnMaxLifetime = cfg.DBConnMaxLifetime
+	dbOptions.ConnMaxIdleTime = cfg.DBConnMaxIdleTime
+	dbOptions.ConnectTimeout = cfg.DBConnectTimeout
+	database, err := db.InitDB(context.Background(), cfg.DatabaseURL.Value(), dbOptions)
+	if err != nil {
+		log.Fatalf("Could not connect to the database: %v", err)
+	}
+	if driver == db.DriverSQLite {
+		if err := migrations.Apply(context.Background(), database, migrations.SQLiteFiles); err != nil {
+			log.Fatalf("Could not migrate the SQLite database: %v", err)
+		}
+	}
+
+	// Replicas may come up later than the server; the router only reads from them once they pass a health check
+	var replicas []*sql.DB
+	for _, url := range cfg.DatabaseReplicaURLs {
+		replica, err := db.Open(url.Value(), dbOptions)
+		if err != nil {
+			log.Fatalf("Could not open read replica: %v", err)
+		}
+		replicas = append(replicas, replica)
+	}
+	dbRouter := db.NewRouter(database, replicas, db.RouterOptions{
+		Driver:        driver,
+		CheckInterval: cfg.DBReplicaCheckInterval,
+		CheckTimeout:  cfg.HealthCheckTimeout,
+		StickyWindow:  cfg.DBReadYourWritesWindow,
+	})
+	dbRouter.Start(context.Background())
+
+	log.Infof("%s database initialized with %d read replicas", driver, len(replicas))
+	return dbRouter
+}
+
+// registerDatabaseChecks makes readiness depend on the database and an up-to-date schema.
+func registerDatabaseChecks(checker *health.Checker, dbRouter *db.Router) {
+	database := dbRouter.Primary()
+	expectedVersion, err := migrations.LatestVersion(migrations.ForDriver(dbRouter.Driver()))
+	if err != nil {
+		log.Fatalf("Could not determine expected schema version: %v", err)
+	}
+	checker.Register("database", health.DatabaseCheck(database))
+	checker.Register("migrations", health.MigrationCheck(database, expectedVersion))
+}
+
+// printConfig writes the effective configuration, with secrets masked and the source of each value, to stdout.
+// It accepts the same flags as the server and returns the process exit code.
+func printConfig(args []string) int {
+	cfg, err := config.LoadConfig(args)
+	if errors.Is(err, pflag.ErrHelp) {
+		return 0
 	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not load config: %v\n", err)
+		return 1
+	}
+	if err := cfg.Print(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not print config: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// watchConfig reloads the configuration when the config file changes or the process receives SIGHUP.
+func watchConfig(manager *config.Manager) {
+	if err := manager.Watch(context.Background()); err != nil {
+		log.Warnf("Config file changes will not be picked up: %v", err)
+	}
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		for range hangup {
+			log.Info("Received SIGHUP, reloading configuration")
+			_, _ = manager.Reload() // Failures are logged by Reload
+		}
+	}()
+}
+
+// shutdown fails readiness immediately, waits for the configured drain delay so load balancers
+// stop routing new requests, then lets in-flight requests and gRPC calls finish within the shutdown timeout.
+func shutdown(server *http.Server, grpcServer *grpc.Server, checker *health.Checker, cfg *config.Config) {
+	log.Info("Shutting down server")
+	checker.SetShuttingDown()
+	time.Sleep(cfg.ShutdownDrainDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	var grpcStopped chan struct{}
+	if grpcServer != nil {
+		grpcStopped = make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(grpcStopped)
+		}()
+	}
+	if err := server.Shutdown(ctx); err != nil {
+		log.Errorf("Server forced to shut down: %v", err)
+	}
+	if grpcServer != nil {
+		select {
+		case <-grpcStopped:
+		case <-ctx.Done():
+			log.Error("gRPC server forced to shut down")
+			grpcServer.Stop()
+		}
+	}
+
+	log.Info("Server stopped")
 }
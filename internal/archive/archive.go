@@ -0,0 +1,103 @@
+// Package archive reads and writes blogs in the formats used to export and import them:
+// JSON Lines, with one blog per line, and zip archives of Markdown files with YAML front matter.
+package archive
+
+import (
+	"archive/zip"
+	"bloggingplatformapi/internal/models"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Archive formats.
+const (
+	FormatJSONL = "jsonl" // One JSON object per line
+	FormatZip   = "zip"   // One Markdown file per blog in a zip archive
+)
+
+// formats describes each archive format.
+var formats = map[string]struct {
+	contentType string
+	extension   string
+}{
+	FormatJSONL: {contentType: "application/x-ndjson", extension: ".jsonl"},
+	FormatZip:   {contentType: "application/zip", extension: ".zip"},
+}
+
+// ValidFormat reports whether format is a known archive format.
+func ValidFormat(format string) bool {
+	_, ok := formats[format]
+	return ok
+}
+
+// ContentType returns the media type of an archive format.
+func ContentType(format string) string {
+	return formats[format].contentType
+}
+
+// FileName returns the name of an archive called base in the given format, e.g. blogs.zip.
+func FileName(base, format string) string {
+	return base + formats[format].extension
+}
+
+// Writer writes blogs to an archive one at a time.
+type Writer interface {
+	Write(blog *models.Blog) error // Adds a blog to the archive
+	Close() error                  // Completes the archive without closing the underlying writer
+}
+
+// NewWriter returns a Writer producing an archive of the given format on w.
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case FormatJSONL:
+		return &jsonlWriter{encoder: json.NewEncoder(w)}, nil
+	case FormatZip:
+		return &zipWriter{zip: zip.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("unknown archive format %q", format)
+	}
+}
+
+// jsonlWriter writes each blog as a line of JSON.
+type jsonlWriter struct {
+	encoder *json.Encoder
+}
+
+// Write implements Writer.
+func (w *jsonlWriter) Write(blog *models.Blog) error {
+	return w.encoder.Encode(blog) // Encode terminates each value with a newline
+}
+
+// Close implements Writer.
+func (w *jsonlWriter) Close() error {
+	return nil
+}
+
+// zipWriter writes each blog as a Markdown file in a zip archive.
+type zipWriter struct {
+	zip *zip.Writer
+}
+
+// Write implements Writer.
+func (w *zipWriter) Write(blog *models.Blog) error {
+	file, err := w.zip.CreateHeader(&zip.FileHeader{
+		Name:     MarkdownFileName(blog),
+		Method:   zip.Deflate,
+		Modified: blog.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	data, err := MarshalMarkdown(blog)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	return err
+}
+
+// Close implements Writer.
+func (w *zipWriter) Close() error {
+	return w.zip.Close()
+}
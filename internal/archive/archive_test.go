@@ -0,0 +1,183 @@
+package archive
+
+import (
+	"archive/zip"
+	"bloggingplatformapi/internal/models"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBlogs returns blogs covering multi-line content, punctuation in titles and empty tags.
+func testBlogs() []*models.Blog {
+	created := time.Date(2024, 12, 25, 16, 0, 0, 0, time.UTC)
+	return []*models.Blog{
+		{ID: 1, Title: "Hello, World!", Content: "# Heading\n\nSome *Markdown*.\n---\nNot front matter.", Category: "Tech",
+			Tags: []string{"Go", "Testing"}, CreatedAt: created, UpdatedAt: created.Add(time.Hour)},
+		{ID: 2, Title: "Ünïcode: titles", Content: "Short", Category: "News", Tags: []string{}, CreatedAt: created, UpdatedAt: created},
+	}
+}
+
+// roundTrip writes blogs in format and reads them back.
+func roundTrip(t *testing.T, format string, blogs []*models.Blog) []Item {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, format)
+	require.NoError(t, err)
+	for _, blog := range blogs {
+		require.NoError(t, w.Write(blog))
+	}
+	require.NoError(t, w.Close())
+
+	var items []Item
+	collect := func(item Item) error {
+		items = append(items, item)
+		return nil
+	}
+	if format == FormatZip {
+		require.NoError(t, ReadZip(bytes.NewReader(buf.Bytes()), int64(buf.Len()), Limits{}, collect))
+	} else {
+		require.NoError(t, ReadJSONL(&buf, Limits{}, collect))
+	}
+	return items
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{FormatJSONL, FormatZip} {
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			items := roundTrip(t, format, testBlogs())
+			require.Len(t, items, 2)
+			for i, want := range testBlogs() {
+				require.NoError(t, items[i].Err)
+				got := items[i].Blog
+				assert.Equal(t, want.ID, got.ID)
+				assert.Equal(t, want.Title, got.Title)
+				assert.Equal(t, want.Content, got.Content)
+				assert.Equal(t, want.Category, got.Category)
+				assert.ElementsMatch(t, want.Tags, got.Tags)
+				assert.True(t, want.CreatedAt.Equal(got.CreatedAt))
+				assert.True(t, want.UpdatedAt.Equal(got.UpdatedAt))
+			}
+			if format == FormatZip {
+				assert.Equal(t, "blogs/1-hello-world.md", items[0].Source)
+				assert.Equal(t, "blogs/2-ünïcode-titles.md", items[1].Source)
+			}
+		})
+	}
+}
+
+func TestReadJSONL_ReportsInvalidLines(t *testing.T) {
+	t.Parallel()
+
+	input := "{\"title\":\"One\"}\n\nnot json\n{\"title\":\"Four\"}\n"
+	var items []Item
+	require.NoError(t, ReadJSONL(bytes.NewBufferString(input), Limits{}, func(item Item) error {
+		items = append(items, item)
+		return nil
+	}))
+
+	require.Len(t, items, 3)
+	assert.Equal(t, "line 1", items[0].Source)
+	assert.Equal(t, "line 3", items[1].Source)
+	assert.ErrorContains(t, items[1].Err, "invalid JSON")
+	assert.Equal(t, "Four", items[2].Blog.Title)
+}
+
+func TestReadZip_ReportsInvalidFiles(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := map[string]string{
+		"blogs/valid.md":            "---\ntitle: Valid\ncategory: Tech\ntags: [Go]\n---\n\nBody\n",
+		"blogs/no-front-matter.md":  "Just text",
+		"blogs/unterminated.md":     "---\ntitle: Open\n",
+		"notes.txt":                 "Not Markdown",
+		"__MACOSX/blogs/._valid.md": "resource fork",
+		"blogs/.DS_Store":           "",
+	}
+	for _, name := range []string{"blogs/valid.md", "blogs/no-front-matter.md", "blogs/unterminated.md", "notes.txt", "__MACOSX/blogs/._valid.md", "blogs/.DS_Store"} {
+		f, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(files[name]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	var items []Item
+	require.NoError(t, ReadZip(bytes.NewReader(buf.Bytes()), int64(buf.Len()), Limits{}, func(item Item) error {
+		items = append(items, item)
+		return nil
+	}))
+
+	require.Len(t, items, 4)
+	assert.Equal(t, "Valid", items[0].Blog.Title)
+	assert.Equal(t, "Body\n", items[0].Blog.Content)
+	assert.ErrorContains(t, items[1].Err, "missing front matter")
+	assert.ErrorContains(t, items[2].Err, "unterminated front matter")
+	assert.ErrorContains(t, items[3].Err, "not a Markdown file")
+}
+
+func TestReadZip_StopsAtLimits(t *testing.T) {
+	t.Parallel()
+
+	// Each file is a megabyte of zeros, which compresses to about a kilobyte.
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	content := append([]byte("---\ntitle: Bomb\n---\n\n"), make([]byte, MaxItemBytes/2)...)
+	for i := range 8 {
+		f, err := zw.Create(fmt.Sprintf("blogs/%d.md", i))
+		require.NoError(t, err)
+		_, err = f.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.Less(t, buf.Len(), MaxItemBytes/10)
+
+	tests := []struct {
+		name   string
+		limits Limits
+		read   int    // Items passed to fn before reading stops
+		want   string // Error of the read
+	}{
+		{name: "Items", limits: Limits{Items: 3}, read: 3, want: "more than 3 items"},
+		{name: "Bytes", limits: Limits{Bytes: 2 * MaxItemBytes}, read: 3, want: fmt.Sprintf("more than %d bytes", 2*MaxItemBytes)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			read := 0
+			err := ReadZip(bytes.NewReader(buf.Bytes()), int64(buf.Len()), tt.limits, func(item Item) error {
+				require.NoError(t, item.Err)
+				read++
+				return nil
+			})
+			require.ErrorIs(t, err, ErrTooLarge)
+			assert.ErrorContains(t, err, tt.want)
+			assert.Equal(t, tt.read, read)
+		})
+	}
+}
+
+func TestReadJSONL_StopsAtItemLimit(t *testing.T) {
+	t.Parallel()
+
+	input := strings.Repeat("{\"title\":\"Blog\"}\n\n", 5)
+	read := 0
+	err := ReadJSONL(strings.NewReader(input), Limits{Items: 4}, func(Item) error {
+		read++
+		return nil
+	})
+	require.ErrorIs(t, err, ErrTooLarge)
+	assert.Equal(t, 4, read)
+}
@@ -0,0 +1,114 @@
+package archive
+
+import (
+	"bloggingplatformapi/internal/models"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// frontMatterFence opens and closes the YAML front matter of a Markdown file.
+const frontMatterFence = "---\n"
+
+// maxSlugLength bounds the part of a Markdown file name derived from the blog title.
+const maxSlugLength = 50
+
+// frontMatter holds the fields of a blog stored in the front matter of its Markdown file.
+type frontMatter struct {
+	ID        int       `yaml:"id,omitempty"`
+	Title     string    `yaml:"title"`
+	Category  string    `yaml:"category"`
+	Tags      []string  `yaml:"tags"`
+	CreatedAt time.Time `yaml:"createdAt,omitempty"`
+	UpdatedAt time.Time `yaml:"updatedAt,omitempty"`
+}
+
+// MarkdownFileName returns the path of a blog's Markdown file within a zip archive, e.g. blogs/12-hello-world.md.
+func MarkdownFileName(blog *models.Blog) string {
+	if slug := slugify(blog.Title); slug != "" {
+		return fmt.Sprintf("blogs/%d-%s.md", blog.ID, slug)
+	}
+	return fmt.Sprintf("blogs/%d.md", blog.ID)
+}
+
+// MarshalMarkdown returns blog as a Markdown document: YAML front matter with every field but the
+// content, followed by the content itself.
+func MarshalMarkdown(blog *models.Blog) ([]byte, error) {
+	meta, err := yaml.Marshal(frontMatter{
+		ID:        blog.ID,
+		Title:     blog.Title,
+		Category:  blog.Category,
+		Tags:      blog.Tags,
+		CreatedAt: blog.CreatedAt,
+		UpdatedAt: blog.UpdatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(frontMatterFence)
+	buf.Write(meta)
+	buf.WriteString(frontMatterFence)
+	buf.WriteString("\n")
+	buf.WriteString(blog.Content)
+	return buf.Bytes(), nil
+}
+
+// UnmarshalMarkdown parses a Markdown document written by MarshalMarkdown.
+// Windows line endings are accepted.
+func UnmarshalMarkdown(data []byte) (*models.Blog, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	rest, ok := strings.CutPrefix(text, frontMatterFence)
+	if !ok {
+		return nil, errors.New("missing front matter: the file must start with a --- line")
+	}
+	meta, content, ok := strings.Cut(rest, "\n"+frontMatterFence)
+	if !ok {
+		meta, ok = strings.CutSuffix(rest, "\n---") // Front matter without content
+		if !ok {
+			return nil, errors.New("unterminated front matter: missing closing --- line")
+		}
+	}
+
+	var fm frontMatter
+	if err := yaml.Unmarshal([]byte(meta), &fm); err != nil {
+		return nil, fmt.Errorf("invalid front matter: %w", err)
+	}
+	return &models.Blog{
+		ID:        fm.ID,
+		Title:     fm.Title,
+		Content:   strings.TrimPrefix(content, "\n"), // The blank line after the front matter
+		Category:  fm.Category,
+		Tags:      fm.Tags,
+		CreatedAt: fm.CreatedAt,
+		UpdatedAt: fm.UpdatedAt,
+	}, nil
+}
+
+// slugify turns a title into a lowercase, hyphen-separated file name fragment.
+func slugify(title string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+		default:
+			hyphen = true
+		}
+		if b.Len() >= maxSlugLength {
+			break
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,170 @@
+package archive
+
+import (
+	"archive/zip"
+	"bloggingplatformapi/internal/models"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// MaxItemBytes is the largest blog an archive may hold, as a JSON line or a Markdown file.
+const MaxItemBytes = 1 << 20
+
+// ErrTooLarge is returned when an archive holds more items or more data than its Limits allow.
+var ErrTooLarge = errors.New("archive too large")
+
+// Limits bounds what an archive may hold, however well it compresses. A zero field means no limit.
+type Limits struct {
+	Items int   // Most items, including those that cannot be decoded
+	Bytes int64 // Most bytes of all items together, after decompression
+}
+
+// budget tracks what remains of the Limits of an archive as it is read.
+type budget struct {
+	limits Limits
+	items  int   // Items read so far
+	bytes  int64 // Bytes read so far
+}
+
+// item counts another item against the limits.
+func (b *budget) item() error {
+	b.items++
+	if b.limits.Items > 0 && b.items > b.limits.Items {
+		return fmt.Errorf("%w: more than %d items", ErrTooLarge, b.limits.Items)
+	}
+	return nil
+}
+
+// spend counts n more bytes against the limits.
+func (b *budget) spend(n int64) error {
+	b.bytes += n
+	if b.limits.Bytes > 0 && b.bytes > b.limits.Bytes {
+		return fmt.Errorf("%w: more than %d bytes", ErrTooLarge, b.limits.Bytes)
+	}
+	return nil
+}
+
+// remaining returns how many more bytes may be read, or -1 if there is no limit.
+func (b *budget) remaining() int64 {
+	if b.limits.Bytes <= 0 {
+		return -1
+	}
+	return max(b.limits.Bytes-b.bytes, 0)
+}
+
+// Item is a blog read from an archive, or the reason it could not be read.
+type Item struct {
+	Source string       // Where the item was found, e.g. "line 3" or "blogs/3-hello.md"
+	Blog   *models.Blog // Decoded blog, nil if Err is set
+	Err    error        // Why the item could not be decoded
+}
+
+// ReadJSONL calls fn with every non-blank line of r decoded as a blog.
+// Lines that cannot be decoded are passed to fn as items with an error; reading stops only
+// when r fails, a line exceeds MaxItemBytes, the archive exceeds limits or fn returns an error.
+func ReadJSONL(r io.Reader, limits Limits, fn func(Item) error) error {
+	b := budget{limits: limits}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), MaxItemBytes)
+	for line := 1; scanner.Scan(); line++ {
+		if err := b.spend(int64(len(scanner.Bytes())) + 1); err != nil { // Including the newline
+			return err
+		}
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		if err := b.item(); err != nil {
+			return err
+		}
+
+		item := Item{Source: fmt.Sprintf("line %d", line)}
+		var blog models.Blog
+		if err := json.Unmarshal(text, &blog); err != nil {
+			item.Err = fmt.Errorf("invalid JSON: %w", err)
+		} else {
+			item.Blog = &blog
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read JSON lines: %w", err)
+	}
+	return nil
+}
+
+// ReadZip calls fn with every Markdown file of the zip archive in r, decoded as a blog, in archive order.
+// Directories and hidden files, such as macOS resource forks, are skipped; other files that cannot be
+// decoded are passed to fn as items with an error. Reading stops with ErrTooLarge as soon as the files
+// exceed limits, so a small archive that decompresses to a huge one is never read completely.
+func ReadZip(r io.ReaderAt, size int64, limits Limits, fn func(Item) error) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("failed to read zip archive: %w", err)
+	}
+	b := budget{limits: limits}
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() || hidden(file.Name) {
+			continue
+		}
+		if err := b.item(); err != nil {
+			return err
+		}
+		item := Item{Source: file.Name}
+		item.Blog, item.Err = readMarkdownFile(file, &b)
+		if errors.Is(item.Err, ErrTooLarge) {
+			return item.Err
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readMarkdownFile decodes a Markdown file of a zip archive, spending the bytes it decompresses from b.
+func readMarkdownFile(file *zip.File, b *budget) (*models.Blog, error) {
+	if !strings.EqualFold(path.Ext(file.Name), ".md") {
+		return nil, fmt.Errorf("not a Markdown file")
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+
+	// Read one byte past the limits, so oversized files are detected without trusting the declared size
+	limit := int64(MaxItemBytes)
+	if remaining := b.remaining(); remaining >= 0 {
+		limit = min(limit, remaining)
+	}
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if err := b.spend(int64(len(data))); err != nil {
+		return nil, err
+	}
+	if len(data) > MaxItemBytes {
+		return nil, fmt.Errorf("file exceeds %d bytes", MaxItemBytes)
+	}
+	return UnmarshalMarkdown(data)
+}
+
+// hidden reports whether a path in an archive names a hidden file or lies in a hidden directory.
+func hidden(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+// Package cache provides a pluggable key-value cache for API responses.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache stores encoded values for a limited time. Implementations must be safe for concurrent use.
+// The in-process LRU only serves a single replica; a shared implementation (e.g. Redis)
+// can be plugged in so that every replica sees the same entries and invalidations.
+type Cache interface {
+	// Get returns the value stored under key, or false if there is none or it has expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key for ttl; a zero ttl keeps it until it is evicted or deleted.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the entries stored under keys.
+	Delete(ctx context.Context, keys ...string) error
+	// DeletePrefix removes every entry whose key starts with prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLRU returns an LRU whose clock is controlled by the returned pointer.
+func newTestLRU(capacity int) (*LRU, *time.Time) {
+	now := time.Date(2024, 12, 25, 16, 0, 0, 0, time.UTC)
+	lru := NewLRU(capacity)
+	lru.now = func() time.Time { return now }
+	return lru, &now
+}
+
+// get returns the value stored under key as a string, or "" if there is none.
+func get(t *testing.T, c Cache, key string) string {
+	t.Helper()
+	value, ok, err := c.Get(context.Background(), key)
+	require.NoError(t, err)
+	if !ok {
+		return ""
+	}
+	return string(value)
+}
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	lru, _ := newTestLRU(2)
+	require.NoError(t, lru.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, lru.Set(ctx, "b", []byte("2"), 0))
+
+	assert.Equal(t, "1", get(t, lru, "a")) // Reading "a" makes "b" the least recently used
+	require.NoError(t, lru.Set(ctx, "c", []byte("3"), 0))
+
+	assert.Equal(t, 2, lru.Len())
+	assert.Equal(t, "", get(t, lru, "b"))
+	assert.Equal(t, "1", get(t, lru, "a"))
+	assert.Equal(t, "3", get(t, lru, "c"))
+
+	// Replacing a value does not grow the cache.
+	require.NoError(t, lru.Set(ctx, "a", []byte("4"), 0))
+	assert.Equal(t, 2, lru.Len())
+	assert.Equal(t, "4", get(t, lru, "a"))
+}
+
+func TestLRU_ExpiresEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	lru, now := newTestLRU(10)
+	require.NoError(t, lru.Set(ctx, "short", []byte("1"), time.Minute))
+	require.NoError(t, lru.Set(ctx, "forever", []byte("2"), 0))
+
+	*now = now.Add(59 * time.Second)
+	assert.Equal(t, "1", get(t, lru, "short"))
+
+	*now = now.Add(time.Second)
+	assert.Equal(t, "", get(t, lru, "short"))
+	assert.Equal(t, "2", get(t, lru, "forever"))
+	assert.Equal(t, 1, lru.Len())
+}
+
+func TestLRU_Delete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	lru, _ := newTestLRU(10)
+	for _, key := range []string{"blog:1", "blog:2", "blogs:", "blogs:go"} {
+		require.NoError(t, lru.Set(ctx, key, []byte(key), 0))
+	}
+
+	require.NoError(t, lru.Delete(ctx, "blog:1", "missing"))
+	require.NoError(t, lru.DeletePrefix(ctx, "blogs:"))
+
+	assert.Equal(t, "", get(t, lru, "blog:1"))
+	assert.Equal(t, "blog:2", get(t, lru, "blog:2"))
+	assert.Equal(t, "", get(t, lru, "blogs:"))
+	assert.Equal(t, "", get(t, lru, "blogs:go"))
+}
+
+func TestLRU_CopiesValues(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	lru, _ := newTestLRU(10)
+	value := []byte("original")
+	require.NoError(t, lru.Set(ctx, "key", value, 0))
+	value[0] = 'X'
+
+	stored, _, _ := lru.Get(ctx, "key")
+	stored[1] = 'X'
+	assert.Equal(t, "original", get(t, lru, "key"))
+}
@@ -1,63 +1,418 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
 // Config holds the application configuration values.
 type Config struct {
-	Port        string // Port on which the server will run
-	DatabaseURL string // URL for the database connection
-	Environment string // Application environment (e.g., development, production)
+	ConfigFile string // Path of the config file that was read, empty if none was found
+
+	Port           string // Port on which the server will run
+	StorageBackend string // Where blogs are stored: database or memory
+	DatabaseURL    Secret // URL for the database connection, including credentials
+	Environment    string // Application environment (e.g., development, production)
+	LogLevel       string // Minimum log level (e.g., debug, info, warn, error)
+
+	ReadTimeout       time.Duration // Maximum duration for reading an entire request
+	ReadHeaderTimeout time.Duration // Maximum duration for reading request headers
+	WriteTimeout      time.Duration // Maximum duration before timing out writes of a response
+	IdleTimeout       time.Duration // Maximum time to wait for the next request on a keep-alive connection
+
+	DBMaxOpenConns    int           // Maximum number of open database connections (0 means unlimited)
+	DBMaxIdleConns    int           // Maximum number of idle database connections
+	DBConnMaxLifetime time.Duration // Maximum time a connection may be reused (0 means forever)
+	DBConnMaxIdleTime time.Duration // Maximum time a connection may sit idle (0 means forever)
+	DBConnectTimeout  time.Duration // How long to keep retrying the database at startup
+
+	DatabaseReplicaURLs    []Secret      // Optional read replicas that serve GET requests
+	DBReplicaCheckInterval time.Duration // Time between replica health checks
+	DBReadYourWritesWindow time.Duration // How long a client reads from the primary after it writes
+
+	CacheEnabled    bool          // Cache blog lookups and listings in memory
+	CacheTTL        time.Duration // How long a cached lookup or listing is served (0 keeps it until evicted)
+	CacheMaxEntries int           // Maximum number of cached lookups and listings
+
+	FeedTitle       string // Title of the RSS and Atom feeds
+	FeedDescription string // Description of the RSS and Atom feeds
+	FeedSiteURL     string // Absolute URL of the site the feeds belong to
+	FeedPostURL     string // Absolute URL of a post with {id} in place of its ID; empty for the API URL
+	FeedAuthor      string // Author named in the feeds, empty for none
+	FeedLanguage    string // Language of the posts, as a language tag
+	FeedMaxItems    int    // Number of most recent posts in each feed
+	SitemapMaxURLs  int    // Number of posts in each sitemap before it is split under a sitemap index
+	ExcerptLength   int    // Number of characters in the excerpt field of sparse fieldsets
+
+	GraphQLMaxDepth      int // Deepest nesting of fields accepted in a GraphQL query
+	GraphQLMaxComplexity int // Most fields a GraphQL query may resolve, counting list fields once per item
+
+	GRPCPort       string   // Port of the gRPC server, empty to disable it
+	GRPCAuthTokens []Secret // Bearer tokens accepted by the gRPC server as principal:token
+	GRPCAnonymous  bool     // Serve gRPC without tokens, accepting every call unauthenticated
+
+	HealthCheckTimeout time.Duration // Upper bound for each readiness dependency check
+	ShutdownDrainDelay time.Duration // Time to keep serving after readiness starts failing
+	ShutdownTimeout    time.Duration // Time allowed for in-flight requests to finish on shutdown
+
+	ServiceName        string  // Service name reported in traces
+	TracingExporter    string  // Span exporter to use: none, stdout or otlp
+	OTLPEndpoint       string  // OTLP gRPC collector endpoint (host:port), used by the otlp exporter
+	OTLPInsecure       bool    // Disable TLS when talking to the OTLP collector
+	TracingSampleRatio float64 // Fraction of new traces to sample, between 0 and 1
+
+	TrustedProxies   []string  // Proxies whose X-Forwarded-For headers are trusted when resolving client IPs
+	RateLimitEnabled bool      // Enable per-client rate limiting
+	RateLimitRead    RateLimit // Limit for read routes (GET)
+	RateLimitWrite   RateLimit // Limit for write routes (POST, PUT, DELETE)
+
+	CORSAllowedOrigins   []string      // Origins allowed to make cross-origin requests ("*" allows any)
+	CORSAllowedMethods   []string      // Methods allowed in cross-origin requests
+	CORSAllowedHeaders   []string      // Request headers allowed in cross-origin requests
+	CORSExposedHeaders   []string      // Response headers exposed to cross-origin clients
+	CORSAllowCredentials bool          // Allow cookies and Authorization headers in cross-origin requests
+	CORSMaxAge           time.Duration // How long browsers may cache preflight results
+
+	values  map[string]string // Raw value of every key (fingerprinted for secrets), used to detect changes on reload
+	sources map[string]string // Where the value of every key came from
 }
 
-// LoadConfig initializes and loads the configuration from a file or environment variables.
-// It prioritizes environment variables over file values, ensuring flexibility for deployment.
-func LoadConfig() (*Config, error) {
-	// Create a new instance of Viper to avoid using the global instance.
-	v := viper.New()
+// RateLimit configures a token bucket for a route group.
+type RateLimit struct {
+	RequestsPerSecond float64 // Sustained request rate
+	Burst             int     // Maximum burst of requests
+}
+
+// Config file discovery.
+const (
+	configFileFlag = "config"      // Command-line flag naming the config file
+	configFileEnv  = "CONFIG_FILE" // Environment variable naming the config file
+)
+
+// defaultConfigPaths are searched for a .env file when no config file is named explicitly.
+var defaultConfigPaths = []string{".", "../../"}
+
+// LoadConfig builds the configuration from, in increasing order of precedence: built-in defaults,
+// an optional config file, environment variables and command-line flags (args, excluding the program name).
+// The config file is named by --config or CONFIG_FILE and may be in .env, YAML or TOML format; without either,
+// a .env file is looked up in the working directory and its grandparent, and it is not an error if none exists.
+// Any key may instead be read from a file named by its KEY_FILE environment variable.
+// The result is validated and every invalid key is reported in a single error.
+func LoadConfig(args []string) (*Config, error) {
+	_, config, err := load(args)
+	return config, err
+}
+
+// secretFileSuffix marks environment variables that name a file holding a setting's value (e.g. DATABASE_URL_FILE).
+const secretFileSuffix = "_FILE"
+
+// loader holds the sources the configuration was built from, so it can be rebuilt on reload.
+type loader struct {
+	v           *viper.Viper
+	flags       *pflag.FlagSet
+	configFile  string            // Path of the config file that was read, empty if none
+	secretFiles map[string]string // Path of the file named by each key's *_FILE variable
+}
 
-	// Configure Viper to read from the .env file
-	v.AddConfigPath("../../") // Specify the directory to look for the configuration file
-	v.SetConfigName(".env")   // Specify the configuration file name (without extension)
-	v.SetConfigType("env")    // Specify the configuration file format
+// load performs LoadConfig and also returns the loader, so the configuration can be reloaded later.
+func load(args []string) (*loader, *Config, error) {
+	// Create a new instance of Viper to avoid using the global instance.
+	l := &loader{v: viper.New()}
 
 	// Set default values for configuration keys
-	setDefaults(v)
+	setDefaults(l.v)
 
-	// Read the configuration file
-	if err := v.ReadInConfig(); err != nil {
-		// Return an error if the configuration file cannot be read
-		return nil, fmt.Errorf("failed to load config file: %w", err)
+	// Parse command-line flags; only flags that were set override other sources
+	l.flags = newFlagSet("server")
+	if err := l.flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	if err := bindFlags(l.v, l.flags); err != nil {
+		return nil, nil, err
+	}
+
+	// Read the configuration file, if any
+	configFile, err := readConfigFile(l.v, l.flags)
+	if err != nil {
+		return nil, nil, err
 	}
+	l.configFile = configFile
 
 	// Automatically bind environment variables to override file values
-	v.AutomaticEnv()
+	l.v.AutomaticEnv()
+
+	// Read secrets from the files named by *_FILE environment variables
+	if err := l.readSecretFiles(); err != nil {
+		return nil, nil, err
+	}
 
-	// Map the loaded configuration to the Config struct
-	config, err := mapConfig(v)
+	config, err := l.build()
 	if err != nil {
-		// Return an error if the mapping fails
-		return nil, fmt.Errorf("failed to map configuration: %v", err)
+		return nil, nil, err
 	}
+	return l, config, nil
+}
 
+// build maps the loaded configuration to the Config struct and validates it,
+// reporting conversion and validation problems together.
+func (l *loader) build() (*Config, error) {
+	config, conversionErr := mapConfig(l.v)
+	if err := mergeValidationErrors(conversionErr, config.Validate()); err != nil {
+		return nil, err
+	}
+	config.ConfigFile = l.configFile
+	config.sources = l.sources()
 	return config, nil
 }
 
-// setDefaults sets default values for configuration keys.
-// These values will be used if not specified in the configuration file or environment variables.
-func setDefaults(v *viper.Viper) {
-	v.SetDefault("PORT", "8080")               // Default port for the server
-	v.SetDefault("ENVIRONMENT", "development") // Default application environment
+// readSecretFiles sets every key whose KEY_FILE environment variable names a file, as provided by
+// Docker and Kubernetes secrets, to the file's content. A trailing newline is ignored.
+// The file takes the place of the KEY environment variable, so setting both is an error, and flags still win.
+func (l *loader) readSecretFiles() error {
+	l.secretFiles = make(map[string]string)
+	var problems []string
+	for _, s := range settings {
+		fileVar := s.key + secretFileSuffix
+		path, ok := os.LookupEnv(fileVar)
+		if !ok || path == "" {
+			continue
+		}
+		if _, set := os.LookupEnv(s.key); set {
+			problems = append(problems, fmt.Sprintf("%s: set either %s or %s, not both", s.key, s.key, fileVar))
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: cannot read %s: %v", s.key, fileVar, err))
+			continue
+		}
+		l.secretFiles[s.key] = path
+		if !l.flags.Changed(flagName(s.key)) {
+			l.v.Set(s.key, strings.TrimRight(string(content), "\r\n"))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// sources describes where the effective value of every key came from.
+func (l *loader) sources() map[string]string {
+	sources := make(map[string]string, len(settings))
+	for _, s := range settings {
+		switch {
+		case l.flags.Changed(flagName(s.key)):
+			sources[s.key] = "flag --" + flagName(s.key)
+		case l.secretFiles[s.key] != "":
+			sources[s.key] = fmt.Sprintf("file %s (%s%s)", l.secretFiles[s.key], s.key, secretFileSuffix)
+		case os.Getenv(s.key) != "":
+			sources[s.key] = "env " + s.key
+		case l.configFile != "" && l.v.InConfig(s.key):
+			sources[s.key] = "config file " + l.configFile
+		default:
+			sources[s.key] = "default"
+		}
+	}
+	return sources
+}
+
+// readConfigFile loads the config file named by the --config flag or CONFIG_FILE, falling back to
+// an optional .env file in the default locations. It returns the path of the file that was read.
+func readConfigFile(v *viper.Viper, flags *pflag.FlagSet) (string, error) {
+	path, _ := flags.GetString(configFileFlag)
+	if path == "" {
+		path = os.Getenv(configFileEnv)
+	}
+
+	if path != "" {
+		// An explicitly requested file must exist; its format is taken from the extension
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return "", fmt.Errorf("failed to load config file %s: %w", path, err)
+		}
+		return v.ConfigFileUsed(), nil
+	}
+
+	// Configure Viper to look for a .env file in the default locations
+	for _, dir := range defaultConfigPaths {
+		v.AddConfigPath(dir) // Specify a directory to look for the configuration file
+	}
+	v.SetConfigName(".env") // Specify the configuration file name (without extension)
+	v.SetConfigType("env")  // Specify the configuration file format
+
+	if err := v.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if errors.As(err, &notFound) {
+			return "", nil // The .env file is optional; environment variables and flags may provide everything
+		}
+		return "", fmt.Errorf("failed to load config file: %w", err)
+	}
+	return v.ConfigFileUsed(), nil
 }
 
 // mapConfig maps the configuration values from Viper to the Config struct.
-// It ensures type safety and provides a structured representation of the configuration.
+// It ensures type safety: every value that cannot be converted to its field type is reported in a
+// *ValidationError, and the affected fields are left at their zero value.
 func mapConfig(v *viper.Viper) (*Config, error) {
-	return &Config{
-		Port:        v.GetString("PORT"),         // Get the server port
-		DatabaseURL: v.GetString("DATABASE_URL"), // Get the database connection
-		Environment: v.GetString("ENVIRONMENT"),  // Get the application environment
-	}, nil
+	r := &reader{v: v, values: make(map[string]string, len(settings))}
+	config := &Config{
+		Port:           r.string("PORT"),                 // Get the server port
+		StorageBackend: r.string("STORAGE_BACKEND"),      // Get the storage backend
+		DatabaseURL:    Secret(r.string("DATABASE_URL")), // Get the database connection
+		Environment:    r.string("ENVIRONMENT"),          // Get the application environment
+		LogLevel:       r.string("LOG_LEVEL"),            // Get the log level
+
+		ReadTimeout:       r.duration("SERVER_READ_TIMEOUT"),
+		ReadHeaderTimeout: r.duration("SERVER_READ_HEADER_TIMEOUT"),
+		WriteTimeout:      r.duration("SERVER_WRITE_TIMEOUT"),
+		IdleTimeout:       r.duration("SERVER_IDLE_TIMEOUT"),
+
+		DBMaxOpenConns:    r.int("DB_MAX_OPEN_CONNS"),
+		DBMaxIdleConns:    r.int("DB_MAX_IDLE_CONNS"),
+		DBConnMaxLifetime: r.duration("DB_CONN_MAX_LIFETIME"),
+		DBConnMaxIdleTime: r.duration("DB_CONN_MAX_IDLE_TIME"),
+		DBConnectTimeout:  r.duration("DB_CONNECT_TIMEOUT"),
+
+		DatabaseReplicaURLs:    secrets(r.list("DATABASE_REPLICA_URLS")),
+		DBReplicaCheckInterval: r.duration("DB_REPLICA_CHECK_INTERVAL"),
+		DBReadYourWritesWindow: r.duration("DB_READ_YOUR_WRITES_WINDOW"),
+
+		CacheEnabled:    r.bool("CACHE_ENABLED"),
+		CacheTTL:        r.duration("CACHE_TTL"),
+		CacheMaxEntries: r.int("CACHE_MAX_ENTRIES"),
+
+		FeedTitle:       r.string("FEED_TITLE"),
+		FeedDescription: r.string("FEED_DESCRIPTION"),
+		FeedSiteURL:     r.string("FEED_SITE_URL"),
+		FeedPostURL:     r.string("FEED_POST_URL"),
+		FeedAuthor:      r.string("FEED_AUTHOR"),
+		FeedLanguage:    r.string("FEED_LANGUAGE"),
+		FeedMaxItems:    r.int("FEED_MAX_ITEMS"),
+		SitemapMaxURLs:  r.int("SITEMAP_MAX_URLS"),
+		ExcerptLength:   r.int("EXCERPT_LENGTH"),
+
+		GraphQLMaxDepth:      r.int("GRAPHQL_MAX_DEPTH"),
+		GraphQLMaxComplexity: r.int("GRAPHQL_MAX_COMPLEXITY"),
+
+		GRPCPort:       r.string("GRPC_PORT"),
+		GRPCAuthTokens: secrets(r.list("GRPC_AUTH_TOKENS")),
+		GRPCAnonymous:  r.bool("GRPC_ALLOW_ANONYMOUS"),
+
+		HealthCheckTimeout: r.duration("HEALTH_CHECK_TIMEOUT"),
+		ShutdownDrainDelay: r.duration("SHUTDOWN_DRAIN_DELAY"),
+		ShutdownTimeout:    r.duration("SHUTDOWN_TIMEOUT"),
+
+		ServiceName:        r.string("OTEL_SERVICE_NAME"),
+		TracingExporter:    r.string("TRACING_EXPORTER"),
+		OTLPEndpoint:       r.string("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		OTLPInsecure:       r.bool("OTEL_EXPORTER_OTLP_INSECURE"),
+		TracingSampleRatio: r.float64("TRACING_SAMPLE_RATIO"),
+
+		TrustedProxies:   r.list("TRUSTED_PROXIES"),
+		RateLimitEnabled: r.bool("RATE_LIMIT_ENABLED"),
+		RateLimitRead: RateLimit{
+			RequestsPerSecond: r.float64("RATE_LIMIT_READ_RPS"),
+			Burst:             r.int("RATE_LIMIT_READ_BURST"),
+		},
+		RateLimitWrite: RateLimit{
+			RequestsPerSecond: r.float64("RATE_LIMIT_WRITE_RPS"),
+			Burst:             r.int("RATE_LIMIT_WRITE_BURST"),
+		},
+
+		CORSAllowedOrigins:   r.list("CORS_ALLOWED_ORIGINS"),
+		CORSAllowedMethods:   r.list("CORS_ALLOWED_METHODS"),
+		CORSAllowedHeaders:   r.list("CORS_ALLOWED_HEADERS"),
+		CORSExposedHeaders:   r.list("CORS_EXPOSED_HEADERS"),
+		CORSAllowCredentials: r.bool("CORS_ALLOW_CREDENTIALS"),
+		CORSMaxAge:           r.duration("CORS_MAX_AGE"),
+	}
+
+	config.values = r.values
+
+	if len(r.problems) > 0 {
+		return config, &ValidationError{Problems: r.problems}
+	}
+	return config, nil
+}
+
+// reader converts raw Viper values to typed fields, recording every value that fails to convert
+// instead of silently falling back to the zero value.
+type reader struct {
+	v        *viper.Viper
+	values   map[string]string
+	problems []string
+}
+
+// raw returns the value of key as a string and records it.
+func (r *reader) raw(key string) string {
+	value := r.v.GetString(key)
+	r.values[key] = value
+	if isSecret(key) {
+		r.values[key] = fingerprint(value)
+	}
+	return value
+}
+
+func (r *reader) string(key string) string {
+	return r.raw(key)
+}
+
+func (r *reader) list(key string) []string {
+	return splitList(r.raw(key))
+}
+
+func (r *reader) duration(key string) time.Duration {
+	r.raw(key)
+	value, err := cast.ToDurationE(r.v.Get(key))
+	r.check(key, err)
+	return value
+}
+
+func (r *reader) int(key string) int {
+	r.raw(key)
+	value, err := cast.ToIntE(r.v.Get(key))
+	r.check(key, err)
+	return value
+}
+
+func (r *reader) float64(key string) float64 {
+	r.raw(key)
+	value, err := cast.ToFloat64E(r.v.Get(key))
+	r.check(key, err)
+	return value
+}
+
+func (r *reader) bool(key string) bool {
+	r.raw(key)
+	value, err := cast.ToBoolE(r.v.Get(key))
+	r.check(key, err)
+	return value
+}
+
+// check records a conversion error for key.
+func (r *reader) check(key string, err error) {
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s: invalid value %q", key, r.v.GetString(key)))
+	}
+}
+
+// splitList splits a comma-separated configuration value into its trimmed, non-empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFile writes a config file with the given name and content to a temporary directory.
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// chdir switches to an empty directory so the default .env lookup finds nothing.
+func chdir(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+}
+
+func TestLoadConfig_Precedence(t *testing.T) {
+	chdir(t)
+	path := writeConfigFile(t, "config.yaml", `
+port: 9000
+database_url: postgres://file
+log_level: debug
+shutdown_timeout: 30s
+`)
+	t.Setenv(configFileEnv, path)
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("DB_MAX_OPEN_CONNS", "20")
+
+	cfg, err := LoadConfig([]string{"--port", "9100", "--db-max-idle-conns=5"})
+	require.NoError(t, err)
+
+	assert.Equal(t, path, cfg.ConfigFile)
+	assert.Equal(t, "9100", cfg.Port)                           // flag beats file
+	assert.Equal(t, "postgres://file", cfg.DatabaseURL.Value()) // file beats default
+	assert.Equal(t, "warn", cfg.LogLevel)                       // env beats file
+	assert.Equal(t, 30*time.Second, cfg.ShutdownTimeout)        // typed from file
+	assert.Equal(t, 20, cfg.DBMaxOpenConns)                     // typed from env
+	assert.Equal(t, 5, cfg.DBMaxIdleConns)                      // typed from flag
+	assert.Equal(t, "development", cfg.Environment)             // default
+	assert.False(t, cfg.CacheEnabled)                           // default, as the cache is per instance
+}
+
+func TestLoadConfig_TOMLFromFlag(t *testing.T) {
+	chdir(t)
+	path := writeConfigFile(t, "config.toml", `
+database_url = "postgres://toml"
+rate_limit_enabled = false
+`)
+
+	cfg, err := LoadConfig([]string{"--config", path})
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://toml", cfg.DatabaseURL.Value())
+	assert.False(t, cfg.RateLimitEnabled)
+}
+
+func TestLoadConfig_OptionalDefaultFile(t *testing.T) {
+	chdir(t)
+	t.Setenv("DATABASE_URL", "postgres://env")
+
+	cfg, err := LoadConfig(nil)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.ConfigFile)
+	assert.Equal(t, "postgres://env", cfg.DatabaseURL.Value())
+}
+
+func TestLoadConfig_MissingExplicitFile(t *testing.T) {
+	chdir(t)
+
+	_, err := LoadConfig([]string{"--config", filepath.Join(t.TempDir(), "missing.yaml")})
+	assert.ErrorContains(t, err, "failed to load config file")
+}
+
+func TestLoadConfig_ReportsEveryInvalidKey(t *testing.T) {
+	chdir(t)
+	t.Setenv("PORT", "http")
+	t.Setenv("SHUTDOWN_TIMEOUT", "soon")
+	t.Setenv("TRACING_SAMPLE_RATIO", "2")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+
+	_, err := LoadConfig(nil)
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{
+		`CORS_ALLOW_CREDENTIALS: cannot be used with the "*" origin; list the allowed origins explicitly`,
+		`DATABASE_URL: must not be empty`,
+		`PORT: must be a number between 1 and 65535, got "http"`,
+		`SHUTDOWN_TIMEOUT: invalid value "soon"`,
+		`TRACING_SAMPLE_RATIO: must be between 0 and 1, got 2`,
+	}, validationErr.Problems)
+}
+
+func TestLoadConfig_RejectsReplicasWithSQLite(t *testing.T) {
+	chdir(t)
+	t.Setenv("DATABASE_URL", "sqlite://blog.db")
+	t.Setenv("DATABASE_REPLICA_URLS", "sqlite://replica.db")
+
+	_, err := LoadConfig(nil)
+	assert.ErrorContains(t, err, "DATABASE_REPLICA_URLS: read replicas are not supported with SQLite")
+}
+
+func TestLoadConfig_ValidatesFeedURLs(t *testing.T) {
+	chdir(t)
+	t.Setenv("DATABASE_URL", "sqlite://blog.db")
+	t.Setenv("FEED_SITE_URL", "example.com")
+	t.Setenv("FEED_POST_URL", "https://example.com/posts/")
+
+	_, err := LoadConfig(nil)
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{
+		`FEED_POST_URL: must be an absolute http or https URL containing {id}, got "https://example.com/posts/"`,
+		`FEED_SITE_URL: must be an absolute http or https URL, got "example.com"`,
+	}, validationErr.Problems)
+}
+
+func TestLoadConfig_ValidatesGRPC(t *testing.T) {
+	chdir(t)
+	t.Setenv("DATABASE_URL", "sqlite://blog.db")
+	t.Setenv("GRPC_PORT", "8080")
+	t.Setenv("GRPC_AUTH_TOKENS", "billing:abc,tokenonly")
+
+	_, err := LoadConfig(nil)
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{
+		`GRPC_AUTH_TOKENS: token 2 must have the form principal:token`,
+		`GRPC_PORT: must differ from PORT`,
+	}, validationErr.Problems)
+}
+
+func TestLoadConfig_RequiresGRPCTokens(t *testing.T) {
+	chdir(t)
+	t.Setenv("DATABASE_URL", "sqlite://blog.db")
+	t.Setenv("GRPC_PORT", "9090")
+
+	_, err := LoadConfig(nil)
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{
+		`GRPC_AUTH_TOKENS: must be set when GRPC_PORT is, unless GRPC_ALLOW_ANONYMOUS is true`,
+	}, validationErr.Problems)
+
+	t.Setenv("GRPC_ALLOW_ANONYMOUS", "true")
+	cfg, err := LoadConfig(nil)
+	require.NoError(t, err)
+	assert.True(t, cfg.GRPCAnonymous)
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Source returns where the effective value of key came from, e.g. "default", "env PORT" or "flag --port".
+func (c *Config) Source(key string) string {
+	return c.sources[key]
+}
+
+// Print writes the effective value and source of every setting to w, one per line.
+// Secret values are masked.
+func (c *Config) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE"); err != nil {
+		return err
+	}
+	for _, s := range settings {
+		value := c.values[s.key]
+		if isSecret(s.key) && value != "" {
+			value = redacted
+		}
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\n", s.key, value, c.Source(s.key)); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
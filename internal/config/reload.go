@@ -0,0 +1,240 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+)
+
+// reloadableKeys lists the settings that take effect without a restart.
+// Changes to any other key are detected on reload but only applied after the next restart.
+var reloadableKeys = []string{
+	"LOG_LEVEL",
+	"RATE_LIMIT_ENABLED",
+	"RATE_LIMIT_READ_RPS",
+	"RATE_LIMIT_READ_BURST",
+	"RATE_LIMIT_WRITE_RPS",
+	"RATE_LIMIT_WRITE_BURST",
+	"CORS_ALLOWED_ORIGINS",
+	"CORS_ALLOWED_METHODS",
+	"CORS_ALLOWED_HEADERS",
+	"CORS_EXPOSED_HEADERS",
+	"CORS_ALLOW_CREDENTIALS",
+	"CORS_MAX_AGE",
+}
+
+// reloadDebounce groups the burst of file events produced by a single save into one reload.
+const reloadDebounce = 100 * time.Millisecond
+
+// Change describes a setting whose value differs after a reload.
+type Change struct {
+	Key     string // Configuration key
+	Old     string // Previous raw value
+	New     string // Raw value after the reload
+	Applied bool   // False when the setting requires a restart to take effect
+}
+
+// String formats the change for logs; values of settings that are not applied are omitted
+// because they may hold credentials (e.g. DATABASE_URL).
+func (c Change) String() string {
+	if !c.Applied {
+		return c.Key + " (requires restart)"
+	}
+	return fmt.Sprintf("%s: %q -> %q", c.Key, c.Old, c.New)
+}
+
+// Manager holds the current configuration and reloads its reloadable settings at runtime.
+// Readers always see a complete, validated Config; a reload that fails to load or validate
+// is rejected and the previous configuration is kept.
+type Manager struct {
+	mu          sync.Mutex // Serializes reloads; Viper is not safe for concurrent use
+	loader      *loader    // Sources created by LoadConfig, re-read on every reload
+	current     atomic.Pointer[Config]
+	subscribers []func(*Config)
+}
+
+// NewManager loads the configuration like LoadConfig and keeps it ready for reloading.
+func NewManager(args []string) (*Manager, error) {
+	l, config, err := load(args)
+	if err != nil {
+		return nil, err
+	}
+	m := &Manager{loader: l}
+	m.current.Store(config)
+	return m, nil
+}
+
+// Current returns the configuration in effect. The returned value must not be modified.
+func (m *Manager) Current() *Config {
+	return m.current.Load()
+}
+
+// Subscribe registers fn to be called with the new configuration after every reload
+// that changed at least one reloadable setting. Subscribers are called one at a time.
+func (m *Manager) Subscribe(fn func(*Config)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscribers = append(m.subscribers, fn)
+}
+
+// Reload re-reads the config file and applies the reloadable settings that changed.
+// Environment variables and flags keep their precedence over the file. It returns every
+// setting that changed, including those that need a restart and were therefore not applied.
+func (m *Manager) Reload() ([]Change, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old := m.current.Load()
+	if old.ConfigFile != "" {
+		if err := m.loader.v.ReadInConfig(); err != nil {
+			return nil, m.reject(fmt.Errorf("failed to load config file %s: %w", old.ConfigFile, err))
+		}
+	}
+
+	loaded, err := m.loader.build()
+	if err != nil {
+		return nil, m.reject(err)
+	}
+
+	changes := diff(old, loaded)
+	if len(changes) == 0 {
+		logrus.Info("Configuration reloaded, nothing changed")
+		return nil, nil
+	}
+
+	// Start from the running configuration so settings that need a restart keep their current value
+	updated := *old
+	updated.values = make(map[string]string, len(old.values))
+	for key, value := range old.values {
+		updated.values[key] = value
+	}
+	applied := false
+	for _, change := range changes {
+		if change.Applied {
+			updated.values[change.Key] = change.New
+			applied = true
+		}
+	}
+	updated.sources = loaded.sources
+	applyReloadable(&updated, loaded)
+	m.current.Store(&updated)
+
+	logrus.WithField("changes", changeStrings(changes)).Info("Configuration reloaded")
+	if applied {
+		for _, notify := range m.subscribers {
+			notify(&updated)
+		}
+	}
+	return changes, nil
+}
+
+// reject logs a failed reload and returns its error.
+func (m *Manager) reject(err error) error {
+	logrus.Errorf("Configuration reload rejected, keeping the previous configuration: %v", err)
+	return err
+}
+
+// Watch reloads the configuration whenever the config file changes, until ctx is cancelled.
+// The file's directory is watched rather than the file itself so that editors that replace the
+// file and Kubernetes ConfigMap symlink swaps are picked up. It returns immediately if no config file is in use.
+func (m *Manager) Watch(ctx context.Context) error {
+	path := m.Current().ConfigFile
+	if path == "" {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch config file: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("failed to watch config file %s: %w", path, err)
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if affectsConfigFile(event, path) {
+					debounce = time.After(reloadDebounce)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logrus.Warnf("Config file watcher error: %v", err)
+			case <-debounce:
+				debounce = nil
+				_, _ = m.Reload() // Failures are logged by Reload
+			}
+		}
+	}()
+	return nil
+}
+
+// affectsConfigFile reports whether a file system event in the config file's directory may have changed it.
+func affectsConfigFile(event fsnotify.Event, path string) bool {
+	if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) {
+		return false
+	}
+	// Kubernetes mounts ConfigMaps through a "..data" symlink that is swapped atomically
+	name := filepath.Base(event.Name)
+	return filepath.Clean(event.Name) == filepath.Clean(path) || name == "..data"
+}
+
+// diff lists the settings whose raw values differ between two configurations, in settings order.
+func diff(old, loaded *Config) []Change {
+	var changes []Change
+	for _, s := range settings {
+		if oldValue, newValue := old.values[s.key], loaded.values[s.key]; oldValue != newValue {
+			changes = append(changes, Change{
+				Key:     s.key,
+				Old:     oldValue,
+				New:     newValue,
+				Applied: slices.Contains(reloadableKeys, s.key),
+			})
+		}
+	}
+	return changes
+}
+
+// applyReloadable copies the settings listed in reloadableKeys from src to dst.
+func applyReloadable(dst, src *Config) {
+	dst.LogLevel = src.LogLevel
+
+	dst.RateLimitEnabled = src.RateLimitEnabled
+	dst.RateLimitRead = src.RateLimitRead
+	dst.RateLimitWrite = src.RateLimitWrite
+
+	dst.CORSAllowedOrigins = src.CORSAllowedOrigins
+	dst.CORSAllowedMethods = src.CORSAllowedMethods
+	dst.CORSAllowedHeaders = src.CORSAllowedHeaders
+	dst.CORSExposedHeaders = src.CORSExposedHeaders
+	dst.CORSAllowCredentials = src.CORSAllowCredentials
+	dst.CORSMaxAge = src.CORSMaxAge
+}
+
+// changeStrings formats changes for logging.
+func changeStrings(changes []Change) []string {
+	formatted := make([]string, len(changes))
+	for i, change := range changes {
+		formatted[i] = change.String()
+	}
+	return formatted
+}
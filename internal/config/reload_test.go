@@ -0,0 +1,93 @@
+package config
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_Reload(t *testing.T) {
+	chdir(t)
+	path := writeConfigFile(t, "config.yaml", `
+database_url: postgres://file
+log_level: info
+port: 9000
+`)
+
+	manager, err := NewManager([]string{"--config", path})
+	require.NoError(t, err)
+
+	var notified []*Config
+	manager.Subscribe(func(cfg *Config) { notified = append(notified, cfg) })
+
+	require.NoError(t, os.WriteFile(path, []byte(`
+database_url: postgres://file
+log_level: debug
+port: 9100
+rate_limit_write_burst: 3
+`), 0o600))
+
+	changes, err := manager.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Key: "PORT", Old: "9000", New: "9100", Applied: false},
+		{Key: "LOG_LEVEL", Old: "info", New: "debug", Applied: true},
+		{Key: "RATE_LIMIT_WRITE_BURST", Old: "10", New: "3", Applied: true},
+	}, changes)
+
+	cfg := manager.Current()
+	assert.Equal(t, "debug", cfg.LogLevel)
+	assert.Equal(t, 3, cfg.RateLimitWrite.Burst)
+	assert.Equal(t, "9000", cfg.Port) // Requires a restart
+	require.Len(t, notified, 1)
+	assert.Same(t, cfg, notified[0])
+
+	// An invalid file is rejected and the previous configuration kept.
+	require.NoError(t, os.WriteFile(path, []byte("database_url: postgres://file\nlog_level: loud\n"), 0o600))
+	_, err = manager.Reload()
+	assert.ErrorContains(t, err, `LOG_LEVEL: unknown level "loud"`)
+	assert.Same(t, cfg, manager.Current())
+	assert.Len(t, notified, 1)
+
+	// A later change to a setting that needs a restart is reported but not announced to subscribers.
+	require.NoError(t, os.WriteFile(path, []byte("database_url: postgres://file\nlog_level: debug\nport: 9200\nrate_limit_write_burst: 3\n"), 0o600))
+	changes, err = manager.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []Change{{Key: "PORT", Old: "9000", New: "9200", Applied: false}}, changes)
+	assert.Len(t, notified, 1)
+}
+
+func TestManager_Watch(t *testing.T) {
+	chdir(t)
+	path := writeConfigFile(t, "config.yaml", "database_url: postgres://file\nlog_level: info\n")
+
+	manager, err := NewManager([]string{"--config", path})
+	require.NoError(t, err)
+
+	reloaded := make(chan *Config, 1)
+	manager.Subscribe(func(cfg *Config) { reloaded <- cfg })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, manager.Watch(ctx))
+
+	require.NoError(t, os.WriteFile(path, []byte("database_url: postgres://file\nlog_level: warn\n"), 0o600))
+
+	select {
+	case cfg := <-reloaded:
+		assert.Equal(t, "warn", cfg.LogLevel)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config file change was not picked up")
+	}
+}
+
+func TestChange_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `LOG_LEVEL: "info" -> "debug"`, Change{Key: "LOG_LEVEL", Old: "info", New: "debug", Applied: true}.String())
+	assert.Equal(t, "DATABASE_URL (requires restart)", Change{Key: "DATABASE_URL", Old: "a", New: "b"}.String())
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// redacted replaces secret values in every textual representation.
+const redacted = "********"
+
+// secretKeys lists the settings whose values are credentials and must never be logged or printed.
+var secretKeys = []string{"DATABASE_URL", "DATABASE_REPLICA_URLS", "GRPC_AUTH_TOKENS"}
+
+// isSecret reports whether key holds a credential.
+func isSecret(key string) bool {
+	return slices.Contains(secretKeys, key)
+}
+
+// Secret is a configuration value that must not leak into logs or output.
+// It formats as a fixed mask with every fmt verb and in JSON; use Value to get the actual value.
+type Secret string
+
+// Value returns the secret in plain text.
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// String returns a mask, or an empty string if the secret is not set.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// Format applies the mask to every verb, including %#v and %q, which would otherwise bypass String.
+func (s Secret) Format(f fmt.State, verb rune) {
+	if verb == 'q' {
+		_, _ = fmt.Fprintf(f, "%q", s.String())
+		return
+	}
+	_, _ = fmt.Fprint(f, s.String())
+}
+
+// MarshalJSON masks the secret in JSON, including structured log fields.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// MarshalText masks the secret in text encodings such as YAML and logfmt.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// secrets converts a list of secret values.
+func secrets(values []string) []Secret {
+	if values == nil {
+		return nil
+	}
+	converted := make([]Secret, len(values))
+	for i, value := range values {
+		converted[i] = Secret(value)
+	}
+	return converted
+}
+
+// fingerprint identifies a secret value without revealing it, so changes can still be detected on reload.
+func fingerprint(value string) string {
+	if value == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(value))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
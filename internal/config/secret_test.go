@@ -0,0 +1,61 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecret_Redacts(t *testing.T) {
+	t.Parallel()
+
+	secret := Secret("postgres://user:hunter2@db/blog")
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x"} {
+		assert.NotContains(t, fmt.Sprintf(verb, secret), "hunter2", verb)
+	}
+	assert.NotContains(t, fmt.Sprintf("%+v", Config{DatabaseURL: secret}), "hunter2")
+
+	encoded, err := json.Marshal(map[string]Secret{"url": secret})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"url":"********"}`, string(encoded))
+
+	assert.Equal(t, "postgres://user:hunter2@db/blog", secret.Value())
+	assert.Equal(t, "", Secret("").String())
+}
+
+func TestLoadConfig_SecretFile(t *testing.T) {
+	chdir(t)
+	path := writeConfigFile(t, "database_url", "postgres://user:hunter2@db/blog\n")
+	t.Setenv("DATABASE_URL_FILE", path)
+	t.Setenv("LOG_LEVEL", "debug")
+
+	cfg, err := LoadConfig([]string{"--port", "9100"})
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://user:hunter2@db/blog", cfg.DatabaseURL.Value())
+
+	var out bytes.Buffer
+	require.NoError(t, cfg.Print(&out))
+	printed := out.String()
+	assert.NotContains(t, printed, "hunter2")
+	assert.Regexp(t, `DATABASE_URL\s+\*{8}\s+file `+regexp.QuoteMeta(path)+` \(DATABASE_URL_FILE\)`, printed)
+	assert.Regexp(t, `LOG_LEVEL\s+debug\s+env LOG_LEVEL`, printed)
+	assert.Regexp(t, `PORT\s+9100\s+flag --port`, printed)
+	assert.Regexp(t, `ENVIRONMENT\s+development\s+default`, printed)
+}
+
+func TestLoadConfig_SecretFileErrors(t *testing.T) {
+	chdir(t)
+	t.Setenv("DATABASE_URL", "postgres://env")
+	t.Setenv("DATABASE_URL_FILE", writeConfigFile(t, "database_url", "postgres://file"))
+	t.Setenv("LOG_LEVEL_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	_, err := LoadConfig(nil)
+	assert.ErrorContains(t, err, "DATABASE_URL: set either DATABASE_URL or DATABASE_URL_FILE, not both")
+	assert.ErrorContains(t, err, "LOG_LEVEL: cannot read LOG_LEVEL_FILE")
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// setting describes a configuration key together with its default value and help text.
+// Every key can be set in the config file, as an environment variable of the same name,
+// or with the matching command-line flag (e.g. RATE_LIMIT_READ_RPS -> --rate-limit-read-rps).
+type setting struct {
+	key          string
+	defaultValue interface{}
+	usage        string
+}
+
+// settings lists every configuration key understood by the application.
+var settings = []setting{
+	// Application
+	{"PORT", "8080", "Port on which the server will run"},
+	{"STORAGE_BACKEND", "database", "Where blogs are stored: database (the PostgreSQL or SQLite DATABASE_URL), or memory for tests and local development"},
+	{"DATABASE_URL", "", "URL for the PostgreSQL or SQLite database (required by the database backend)"},
+	{"ENVIRONMENT", "development", "Application environment (development, staging, production or test)"},
+	{"LOG_LEVEL", "info", "Minimum log level (debug, info, warn or error)"},
+
+	// HTTP server
+	{"SERVER_READ_TIMEOUT", "5s", "Maximum duration for reading an entire request"},
+	{"SERVER_READ_HEADER_TIMEOUT", "5s", "Maximum duration for reading request headers"},
+	{"SERVER_WRITE_TIMEOUT", "5s", "Maximum duration before timing out writes of a response"},
+	{"SERVER_IDLE_TIMEOUT", "5s", "Maximum time to wait for the next request on a keep-alive connection"},
+
+	// Database pool
+	{"DB_MAX_OPEN_CONNS", 0, "Maximum number of open database connections (0 means unlimited)"},
+	{"DB_MAX_IDLE_CONNS", 2, "Maximum number of idle database connections"},
+	{"DB_CONN_MAX_LIFETIME", "30m", "Maximum time a database connection may be reused (0 means forever)"},
+	{"DB_CONN_MAX_IDLE_TIME", "5m", "Maximum time a database connection may sit idle (0 means forever)"},
+	{"DB_CONNECT_TIMEOUT", "30s", "How long to keep retrying the database at startup"},
+
+	// Read replicas
+	{"DATABASE_REPLICA_URLS", "", "Comma-separated URLs of read replicas for listing and lookups"},
+	{"DB_REPLICA_CHECK_INTERVAL", "5s", "Time between read replica health checks"},
+	{"DB_READ_YOUR_WRITES_WINDOW", "5s", "How long a client reads from the primary after it writes (0 disables)"},
+
+	// Response cache
+	{"CACHE_ENABLED", false, "Cache blog lookups and listings in memory; invalidated only on the instance that writes"},
+	{"CACHE_TTL", "1m", "How long a cached lookup or listing is served (0 keeps it until evicted)"},
+	{"CACHE_MAX_ENTRIES", 1000, "Maximum number of cached lookups and listings"},
+
+	// Feeds
+	{"FEED_TITLE", "Blog", "Title of the RSS and Atom feeds"},
+	{"FEED_DESCRIPTION", "Latest posts", "Description of the RSS and Atom feeds"},
+	{"FEED_SITE_URL", "http://localhost:8080", "Absolute URL of the site the feeds belong to"},
+	{"FEED_POST_URL", "", "Absolute URL of a post with {id} in place of its ID (default: the post in the API under FEED_SITE_URL)"},
+	{"FEED_AUTHOR", "", "Author named in the feeds (optional)"},
+	{"FEED_LANGUAGE", "en", "Language of the posts, as a language tag"},
+	{"FEED_MAX_ITEMS", 20, "Number of most recent posts in each feed"},
+	{"SITEMAP_MAX_URLS", 50000, "Number of posts in each sitemap before it is split under a sitemap index"},
+	{"EXCERPT_LENGTH", 200, "Number of characters in the excerpt field of sparse fieldsets"},
+
+	// GraphQL
+	{"GRAPHQL_MAX_DEPTH", 10, "Deepest nesting of fields accepted in a GraphQL query"},
+	{"GRAPHQL_MAX_COMPLEXITY", 1000, "Most fields a GraphQL query may resolve, counting list fields once per item"},
+
+	// gRPC
+	{"GRPC_PORT", "", "Port of the gRPC server (empty to disable it)"},
+	{"GRPC_AUTH_TOKENS", "", "Comma-separated principal:token pairs accepted as gRPC bearer tokens (required with GRPC_PORT)"},
+	{"GRPC_ALLOW_ANONYMOUS", false, "Serve gRPC without GRPC_AUTH_TOKENS, accepting every call unauthenticated"},
+
+	// Health checks and shutdown
+	{"HEALTH_CHECK_TIMEOUT", "2s", "Timeout for each readiness dependency check"},
+	{"SHUTDOWN_DRAIN_DELAY", "0s", "Time to keep serving after readiness starts failing"},
+	{"SHUTDOWN_TIMEOUT", "15s", "Grace period for in-flight requests on shutdown"},
+
+	// Tracing
+	{"OTEL_SERVICE_NAME", "blogging-platform-api", "Service name reported in traces"},
+	{"TRACING_EXPORTER", "none", "Span exporter: none, stdout or otlp"},
+	{"OTEL_EXPORTER_OTLP_ENDPOINT", "localhost:4317", "OTLP gRPC collector endpoint (host:port)"},
+	{"OTEL_EXPORTER_OTLP_INSECURE", true, "Disable TLS when talking to the OTLP collector"},
+	{"TRACING_SAMPLE_RATIO", 1.0, "Fraction of new traces to sample, between 0 and 1"},
+
+	// Rate limiting
+	{"TRUSTED_PROXIES", "", "Comma-separated proxy IPs/CIDRs allowed to set X-Forwarded-For"},
+	{"RATE_LIMIT_ENABLED", true, "Enable per-client rate limiting"},
+	{"RATE_LIMIT_READ_RPS", 20.0, "Sustained requests per second for read routes"},
+	{"RATE_LIMIT_READ_BURST", 40, "Maximum burst of requests for read routes"},
+	{"RATE_LIMIT_WRITE_RPS", 2.0, "Sustained requests per second for write routes"},
+	{"RATE_LIMIT_WRITE_BURST", 10, "Maximum burst of requests for write routes"},
+
+	// CORS
+	{"CORS_ALLOWED_ORIGINS", "*", "Comma-separated origins allowed to make cross-origin requests"},
+	{"CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS", "Comma-separated methods allowed in cross-origin requests"},
+	{"CORS_ALLOWED_HEADERS", "Origin,Accept,Content-Type,Authorization,X-Request-ID", "Comma-separated request headers allowed in cross-origin requests"},
+	{"CORS_EXPOSED_HEADERS", "X-Request-ID,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,Retry-After", "Comma-separated response headers exposed to cross-origin clients"},
+	{"CORS_ALLOW_CREDENTIALS", false, "Allow credentials in cross-origin requests"},
+	{"CORS_MAX_AGE", "10m", "How long browsers may cache preflight results"},
+}
+
+// setDefaults sets default values for configuration keys.
+// These values will be used if not specified in the configuration file, environment variables or flags.
+func setDefaults(v *viper.Viper) {
+	for _, s := range settings {
+		v.SetDefault(s.key, s.defaultValue)
+	}
+}
+
+// newFlagSet creates the command-line flags: --config plus one flag per configuration key.
+func newFlagSet(name string) *pflag.FlagSet {
+	flags := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	flags.String(configFileFlag, "", "Path to a config file (.env, .yaml or .toml); overrides "+configFileEnv)
+	for _, s := range settings {
+		flags.String(flagName(s.key), fmt.Sprint(s.defaultValue), s.usage)
+	}
+	return flags
+}
+
+// bindFlags makes flags that were set on the command line take precedence over every other source.
+func bindFlags(v *viper.Viper, flags *pflag.FlagSet) error {
+	for _, s := range settings {
+		if err := v.BindPFlag(s.key, flags.Lookup(flagName(s.key))); err != nil {
+			return fmt.Errorf("failed to bind flag for %s: %w", s.key, err)
+		}
+	}
+	return nil
+}
+
+// flagName converts a configuration key to its command-line flag name.
+func flagName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
@@ -0,0 +1,224 @@
+package config
+
+import (
+	"bloggingplatformapi/pkg/db"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ValidationError lists every invalid configuration key found while loading the configuration.
+type ValidationError struct {
+	Problems []string // One "KEY: reason" entry per invalid key
+}
+
+// Error reports all problems at once so they can be fixed in a single pass.
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// mergeValidationErrors combines conversion problems with validation problems.
+// A key that could not be converted is only reported once, with its conversion problem.
+func mergeValidationErrors(conversionErr, validationErr error) error {
+	var conversion, validation *ValidationError
+	errors.As(conversionErr, &conversion)
+	errors.As(validationErr, &validation)
+	if conversion == nil {
+		return validationErr
+	}
+	if validation == nil {
+		return conversion
+	}
+
+	problems := slices.Clone(conversion.Problems)
+	for _, problem := range validation.Problems {
+		reported := slices.ContainsFunc(conversion.Problems, func(p string) bool {
+			return problemKey(p) == problemKey(problem)
+		})
+		if !reported {
+			problems = append(problems, problem)
+		}
+	}
+	slices.Sort(problems)
+	return &ValidationError{Problems: problems}
+}
+
+// problemKey returns the configuration key a problem refers to.
+func problemKey(problem string) string {
+	key, _, _ := strings.Cut(problem, ":")
+	return key
+}
+
+// Storage backends.
+const (
+	StorageDatabase = "database" // Blogs are stored in the PostgreSQL or SQLite database named by DATABASE_URL
+	StorageMemory   = "memory"   // Blogs are kept in memory and lost on restart
+)
+
+// maxFeedItems bounds FEED_MAX_ITEMS, since every feed request renders that many posts.
+const maxFeedItems = 1000
+
+// maxSitemapURLs bounds SITEMAP_MAX_URLS at the largest sitemap the sitemap protocol allows.
+const maxSitemapURLs = 50000
+
+// maxExcerptLength bounds EXCERPT_LENGTH, since an excerpt is cut for every post in a listing.
+const maxExcerptLength = 10000
+
+// Valid values for enumerated settings.
+var (
+	validStorageBackends  = []string{StorageDatabase, StorageMemory}
+	validEnvironments     = []string{"development", "staging", "production", "test"}
+	validTracingExporters = []string{"none", "stdout", "otlp"}
+)
+
+// Validate checks every setting and returns a *ValidationError describing all invalid keys, or nil.
+func (c *Config) Validate() error {
+	var problems []string
+	invalid := func(key, format string, args ...interface{}) {
+		problems = append(problems, key+": "+fmt.Sprintf(format, args...))
+	}
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		invalid("PORT", "must be a number between 1 and 65535, got %q", c.Port)
+	}
+	if !slices.Contains(validStorageBackends, c.StorageBackend) {
+		invalid("STORAGE_BACKEND", "must be one of %s, got %q", strings.Join(validStorageBackends, ", "), c.StorageBackend)
+	}
+	if c.StorageBackend == StorageDatabase {
+		if strings.TrimSpace(c.DatabaseURL.Value()) == "" {
+			invalid("DATABASE_URL", "must not be empty")
+		} else if driver, _ := db.ParseDataSource(c.DatabaseURL.Value()); driver == db.DriverSQLite && len(c.DatabaseReplicaURLs) > 0 {
+			invalid("DATABASE_REPLICA_URLS", "read replicas are not supported with SQLite")
+		}
+	}
+	if !slices.Contains(validEnvironments, c.Environment) {
+		invalid("ENVIRONMENT", "must be one of %s, got %q", strings.Join(validEnvironments, ", "), c.Environment)
+	}
+	if _, err := logrus.ParseLevel(c.LogLevel); err != nil {
+		invalid("LOG_LEVEL", "unknown level %q", c.LogLevel)
+	}
+
+	positive := map[string]time.Duration{
+		"SERVER_READ_TIMEOUT":        c.ReadTimeout,
+		"SERVER_READ_HEADER_TIMEOUT": c.ReadHeaderTimeout,
+		"SERVER_WRITE_TIMEOUT":       c.WriteTimeout,
+		"SERVER_IDLE_TIMEOUT":        c.IdleTimeout,
+		"HEALTH_CHECK_TIMEOUT":       c.HealthCheckTimeout,
+		"DB_CONNECT_TIMEOUT":         c.DBConnectTimeout,
+		"DB_REPLICA_CHECK_INTERVAL":  c.DBReplicaCheckInterval,
+		"SHUTDOWN_TIMEOUT":           c.ShutdownTimeout,
+	}
+	for key, d := range positive {
+		if d <= 0 {
+			invalid(key, "must be a positive duration, got %s", d)
+		}
+	}
+	nonNegative := map[string]time.Duration{
+		"SHUTDOWN_DRAIN_DELAY":       c.ShutdownDrainDelay,
+		"CORS_MAX_AGE":               c.CORSMaxAge,
+		"DB_CONN_MAX_LIFETIME":       c.DBConnMaxLifetime,
+		"DB_CONN_MAX_IDLE_TIME":      c.DBConnMaxIdleTime,
+		"DB_READ_YOUR_WRITES_WINDOW": c.DBReadYourWritesWindow,
+		"CACHE_TTL":                  c.CacheTTL,
+	}
+	for key, d := range nonNegative {
+		if d < 0 {
+			invalid(key, "must not be negative, got %s", d)
+		}
+	}
+
+	if c.DBMaxOpenConns < 0 {
+		invalid("DB_MAX_OPEN_CONNS", "must not be negative, got %d", c.DBMaxOpenConns)
+	}
+	if c.DBMaxIdleConns < 0 {
+		invalid("DB_MAX_IDLE_CONNS", "must not be negative, got %d", c.DBMaxIdleConns)
+	} else if c.DBMaxOpenConns > 0 && c.DBMaxIdleConns > c.DBMaxOpenConns {
+		invalid("DB_MAX_IDLE_CONNS", "must not exceed DB_MAX_OPEN_CONNS (%d), got %d", c.DBMaxOpenConns, c.DBMaxIdleConns)
+	}
+
+	if c.CacheEnabled && c.CacheMaxEntries < 1 {
+		invalid("CACHE_MAX_ENTRIES", "must be at least 1 when caching is enabled, got %d", c.CacheMaxEntries)
+	}
+
+	if strings.TrimSpace(c.FeedTitle) == "" {
+		invalid("FEED_TITLE", "must not be empty")
+	}
+	if !isAbsoluteURL(c.FeedSiteURL) {
+		invalid("FEED_SITE_URL", "must be an absolute http or https URL, got %q", c.FeedSiteURL)
+	}
+	if c.FeedPostURL != "" && (!isAbsoluteURL(strings.ReplaceAll(c.FeedPostURL, "{id}", "1")) || !strings.Contains(c.FeedPostURL, "{id}")) {
+		invalid("FEED_POST_URL", "must be an absolute http or https URL containing {id}, got %q", c.FeedPostURL)
+	}
+	if c.FeedMaxItems < 1 || c.FeedMaxItems > maxFeedItems {
+		invalid("FEED_MAX_ITEMS", "must be between 1 and %d, got %d", maxFeedItems, c.FeedMaxItems)
+	}
+	if c.SitemapMaxURLs < 1 || c.SitemapMaxURLs > maxSitemapURLs {
+		invalid("SITEMAP_MAX_URLS", "must be between 1 and %d, got %d", maxSitemapURLs, c.SitemapMaxURLs)
+	}
+	if c.ExcerptLength < 1 || c.ExcerptLength > maxExcerptLength {
+		invalid("EXCERPT_LENGTH", "must be between 1 and %d, got %d", maxExcerptLength, c.ExcerptLength)
+	}
+	if c.GraphQLMaxDepth < 1 {
+		invalid("GRAPHQL_MAX_DEPTH", "must be positive, got %d", c.GraphQLMaxDepth)
+	}
+	if c.GraphQLMaxComplexity < 1 {
+		invalid("GRAPHQL_MAX_COMPLEXITY", "must be positive, got %d", c.GraphQLMaxComplexity)
+	}
+
+	if c.GRPCPort != "" {
+		if port, err := strconv.Atoi(c.GRPCPort); err != nil || port < 1 || port > 65535 {
+			invalid("GRPC_PORT", "must be empty or a number between 1 and 65535, got %q", c.GRPCPort)
+		} else if c.GRPCPort == c.Port {
+			invalid("GRPC_PORT", "must differ from PORT")
+		}
+		// The gRPC API writes blogs and is not rate limited, so serving it anonymously must be a choice
+		if len(c.GRPCAuthTokens) == 0 && !c.GRPCAnonymous {
+			invalid("GRPC_AUTH_TOKENS", "must be set when GRPC_PORT is, unless GRPC_ALLOW_ANONYMOUS is true")
+		}
+	}
+	for i, token := range c.GRPCAuthTokens {
+		if principal, value, ok := strings.Cut(token.Value(), ":"); !ok || principal == "" || value == "" {
+			invalid("GRPC_AUTH_TOKENS", "token %d must have the form principal:token", i+1)
+		}
+	}
+
+	if !slices.Contains(validTracingExporters, strings.ToLower(c.TracingExporter)) {
+		invalid("TRACING_EXPORTER", "must be one of %s, got %q", strings.Join(validTracingExporters, ", "), c.TracingExporter)
+	}
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		invalid("TRACING_SAMPLE_RATIO", "must be between 0 and 1, got %g", c.TracingSampleRatio)
+	}
+
+	if c.RateLimitEnabled {
+		for key, limit := range map[string]RateLimit{"RATE_LIMIT_READ": c.RateLimitRead, "RATE_LIMIT_WRITE": c.RateLimitWrite} {
+			if limit.RequestsPerSecond <= 0 {
+				invalid(key+"_RPS", "must be positive when rate limiting is enabled, got %g", limit.RequestsPerSecond)
+			}
+			if limit.Burst < 1 {
+				invalid(key+"_BURST", "must be at least 1 when rate limiting is enabled, got %d", limit.Burst)
+			}
+		}
+	}
+
+	if c.CORSAllowCredentials && slices.Contains(c.CORSAllowedOrigins, "*") {
+		invalid("CORS_ALLOW_CREDENTIALS", "cannot be used with the \"*\" origin; list the allowed origins explicitly")
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	slices.Sort(problems) // Map iteration order is random; keep the report stable
+	return &ValidationError{Problems: problems}
+}
+
+// isAbsoluteURL reports whether raw is an absolute http or https URL with a host.
+func isAbsoluteURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"bloggingplatformapi/internal/archive"
+	"bloggingplatformapi/internal/logging"
+	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/internal/services"
+	"bloggingplatformapi/internal/utils"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxImportBytes is the largest request body accepted by POST /blogs/import, and the most the blogs of
+// an import may total once decompressed.
+const MaxImportBytes = 32 << 20
+
+// importLimits bounds the archives accepted by POST /blogs/import to what one batch may hold.
+var importLimits = archive.Limits{Items: services.MaxBatchOperations, Bytes: MaxImportBytes}
+
+// exportFileName is the base name of the file offered by GET /blogs/export.
+const exportFileName = "blogs"
+
+// ExportBlogs streams every blog as an archive via GET /blogs/export.
+// The format query parameter selects JSON Lines (jsonl, the default) or a zip of Markdown files (zip).
+// Blogs are written as they are read, so the response is not held in memory.
+func (c *BlogController) ExportBlogs(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", archive.FormatJSONL)
+	if !archive.ValidFormat(format) {
+		utils.RespondWithError(ctx, http.StatusBadRequest, "format must be jsonl or zip")
+		return
+	}
+
+	// An export of many blogs can outlast the server's write timeout.
+	if err := http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		logging.FromContext(ctx.Request.Context()).Warnf("Failed to clear the write deadline of the export: %v", err)
+	}
+
+	ctx.Header("Content-Type", archive.ContentType(format))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", archive.FileName(exportFileName, format)))
+	ctx.Status(http.StatusOK)
+
+	writer, err := archive.NewWriter(ctx.Writer, format)
+	if err == nil {
+		err = c.Service.ExportBlogs(ctx.Request.Context(), 0, 0, func(blog *models.Blog) error {
+			return writer.Write(presentBlog(blog, false)) // The rendering is derived, and redone on import
+		})
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		// The status has been sent, so the client can only learn of the failure from the truncated archive.
+		logging.FromContext(ctx.Request.Context()).Errorf("Failed to export blogs: %v", err)
+		ctx.Abort()
+	}
+}
+
+// ImportBlogs creates blogs from an archive in the format produced by ExportBlogs via POST /blogs/import.
+// The format is taken from the format query parameter, or from a Content-Type of application/zip.
+// Every item is validated like a single create. IDs in the archive are ignored, so imported blogs get new ones;
+// so are timestamps, unless keep_timestamps=true restores them along with the blogs. With dry_run=true nothing
+// is stored. The mode query parameter works as for POST /blogs:batch, and the response lists the outcome of every item.
+func (c *BlogController) ImportBlogs(ctx *gin.Context) {
+	format := ctx.Query("format")
+	if format == "" {
+		format = archive.FormatJSONL
+		if ctx.ContentType() == archive.ContentType(archive.FormatZip) {
+			format = archive.FormatZip
+		}
+	}
+	if !archive.ValidFormat(format) {
+		utils.RespondWithError(ctx, http.StatusBadRequest, "format must be jsonl or zip")
+		return
+	}
+	mode := ctx.DefaultQuery("mode", BatchModeAtomic)
+	if mode != BatchModeAtomic && mode != BatchModeBestEffort {
+		utils.RespondWithError(ctx, http.StatusBadRequest, "mode must be atomic or best-effort")
+		return
+	}
+	atomic := mode == BatchModeAtomic
+	dryRun, err := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		utils.RespondWithError(ctx, http.StatusBadRequest, "dry_run must be true or false")
+		return
+	}
+	keepTimestamps, err := strconv.ParseBool(ctx.DefaultQuery("keep_timestamps", "false"))
+	if err != nil {
+		utils.RespondWithError(ctx, http.StatusBadRequest, "keep_timestamps must be true or false")
+		return
+	}
+
+	// Read and validate every item, collecting a create operation for each valid one.
+	var results []models.ImportResult
+	var valid []models.BatchOperation
+	var positions []int // Index in results of each valid item
+	collect := func(item archive.Item) error {
+		result := models.ImportResult{Index: len(results), Source: item.Source}
+		if err := importable(item); err != nil {
+			result.Status, result.Error = http.StatusBadRequest, err.Error()
+		} else {
+			valid = append(valid, models.BatchOperation{Op: models.BatchCreate, Blog: item.Blog, KeepTimestamps: keepTimestamps})
+			positions = append(positions, result.Index)
+		}
+		results = append(results, result)
+		return nil
+	}
+	if err := readArchive(ctx, format, collect); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.RespondWithError(ctx, http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("An import may be at most %d bytes", MaxImportBytes))
+			return
+		}
+		if errors.Is(err, archive.ErrTooLarge) {
+			utils.RespondWithError(ctx, http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("An import may hold at most %d blogs totalling %d bytes", importLimits.Items, importLimits.Bytes))
+			return
+		}
+		logAndRespond(ctx, http.StatusBadRequest, "Invalid archive", err)
+		return
+	}
+	if len(results) == 0 {
+		utils.RespondWithError(ctx, http.StatusBadRequest, "The archive contains no blogs")
+		return
+	}
+
+	failed := len(valid) < len(results)
+	respond := func(code int) {
+		imported := 0
+		for _, result := range results {
+			if result.Status == http.StatusCreated {
+				imported++
+			}
+		}
+		body := gin.H{"dryRun": dryRun, "imported": imported, "results": results}
+		if code >= http.StatusBadRequest {
+			utils.RespondWithErrorDetails(ctx, code, "Import failed", body)
+			return
+		}
+		utils.RespondWithJSON(ctx, code, body)
+	}
+
+	if atomic && failed {
+		for _, i := range positions {
+			results[i].Status, results[i].Error = http.StatusFailedDependency, services.ErrBatchAborted.Error()
+		}
+		respond(http.StatusBadRequest)
+		return
+	}
+	if dryRun {
+		for _, i := range positions {
+			results[i].Status = http.StatusOK // Would be created
+		}
+		if failed {
+			respond(http.StatusMultiStatus)
+		} else {
+			respond(http.StatusOK)
+		}
+		return
+	}
+
+	var outcomes []services.BatchOutcome
+	if len(valid) > 0 {
+		outcomes, err = c.Service.ApplyBatch(ctx.Request.Context(), valid, atomic)
+	}
+	var batchErr *services.BatchError
+	if err != nil && !errors.As(err, &batchErr) {
+		logAndRespond(ctx, http.StatusInternalServerError, "Failed to import blogs", err)
+		return
+	}
+
+	for k, outcome := range outcomes {
+		result := &results[positions[k]]
+		batch := models.BatchResult{Index: result.Index, Op: models.BatchCreate}
+		batchResult(ctx, &batch, outcome)
+		result.Status, result.ID, result.Error = batch.Status, batch.ID, batch.Error
+		failed = failed || outcome.Err != nil
+	}
+
+	switch {
+	case batchErr != nil:
+		respond(results[positions[batchErr.Index]].Status)
+	case failed:
+		respond(http.StatusMultiStatus)
+	default:
+		respond(http.StatusOK)
+	}
+}
+
+// readArchive calls fn with every item of the archive in the request body, which may be at most MaxImportBytes
+// and hold at most importLimits.
+func readArchive(ctx *gin.Context, format string, fn func(archive.Item) error) error {
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, MaxImportBytes)
+	if format == archive.FormatJSONL {
+		return archive.ReadJSONL(body, importLimits, fn)
+	}
+
+	// The zip directory is at the end of the archive, so it must be read completely first.
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	return archive.ReadZip(bytes.NewReader(data), int64(len(data)), importLimits, fn)
+}
+
+// importable checks that an archive item holds a valid blog and clears its ID, which is assigned on creation.
+func importable(item archive.Item) error {
+	if item.Err != nil {
+		return item.Err
+	}
+	item.Blog.ID = 0
+	return utils.ValidateBlog(item.Blog)
+}
@@ -1,35 +1,50 @@
 package controllers
 
 import (
+	"bloggingplatformapi/internal/logging"
+	"bloggingplatformapi/internal/markdown"
 	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/internal/repository"
 	"bloggingplatformapi/internal/services"
 	"bloggingplatformapi/internal/utils"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
-	log "github.com/sirupsen/logrus"
 )
 
 // BlogController is responsible for handling HTTP requests related to blogs.
 type BlogController struct {
-	Service services.BlogService
+	Service       services.BlogService
+	ExcerptLength int // Number of characters in the excerpt field of sparse fieldsets
 }
 
 // NewBlogController creates a new instance of BlogController with the provided BlogService.
-func NewBlogController(service services.BlogService) *BlogController {
-	return &BlogController{service}
+// Excerpts are excerptLength characters long, or markdown.DefaultExcerptLength if excerptLength is not positive.
+func NewBlogController(service services.BlogService, excerptLength int) *BlogController {
+	if excerptLength <= 0 {
+		excerptLength = markdown.DefaultExcerptLength
+	}
+	return &BlogController{Service: service, ExcerptLength: excerptLength}
 }
 
 // CreateBlog handles the creation of a new blog via POST /blogs.
 // It validates the incoming request, processes the creation through the service layer, and returns the created blog.
 func (c *BlogController) CreateBlog(ctx *gin.Context) {
+	html, ok := renderHTML(ctx)
+	if !ok {
+		return
+	}
+
 	var blog models.Blog
-	// Bind incoming JSON payload to the Blog model.
-	if err := ctx.ShouldBindJSON(&blog); err != nil {
-		logAndRespond(ctx, http.StatusBadRequest, "Invalid request payload", err)
+	// Bind the incoming payload, in the format named by its Content-Type, to the Blog model.
+	if err := utils.BindBody(ctx, &blog); err != nil {
+		respondInvalidBody(ctx, err)
 		return
 	}
 
@@ -40,44 +55,90 @@ func (c *BlogController) CreateBlog(ctx *gin.Context) {
 	}
 
 	// Pass the blog to the service for creation.
-	if err := c.Service.CreateBlog(&blog); err != nil {
+	if err := c.Service.CreateBlog(ctx.Request.Context(), &blog); err != nil {
 		logAndRespond(ctx, http.StatusInternalServerError, "Failed to create blog", err)
 		return
 	}
 
-	utils.RespondWithJSON(ctx, http.StatusCreated, blog)
+	utils.RespondWithJSON(ctx, http.StatusCreated, presentBlog(&blog, html))
 }
 
 // GetBlog retrieves a specific key by its ID via GET /blogs/:id.
 // It validates the ID parameter and fetches the blog from the service layer.
+// The fields query parameter selects a sparse fieldset, as for GetAllBlogs.
 func (c *BlogController) GetBlog(ctx *gin.Context) {
 	id, err := parseID(ctx.Param("blogId"))
 	if err != nil {
 		logAndRespond(ctx, http.StatusBadRequest, "Invalid blog ID", err)
 		return
 	}
+	html, ok := renderHTML(ctx)
+	if !ok {
+		return
+	}
+	fields, ok := parseFields(ctx)
+	if !ok {
+		return
+	}
 
 	// Fetch the blog from the service layer.
-	blog, err := c.Service.GetBlogByID(id)
+	blog, err := c.Service.GetBlogByID(ctx.Request.Context(), id)
 	if handleServiceError(ctx, err, "Failed to retrieve blog") {
 		return
 	}
 
-	utils.RespondWithJSON(ctx, http.StatusOK, blog)
+	if fields != nil {
+		utils.RespondWithJSON(ctx, http.StatusOK, c.sparseBlog(blog, fields))
+		return
+	}
+	utils.RespondWithJSON(ctx, http.StatusOK, presentBlog(blog, html))
 }
 
 // GetAllBlogs retrieves all blogs or filters them based on a search term via GET /blogs.
 // It handles optional query parameters and fetches the blogs from the service layer.
+// The limit and offset query parameters select a page of the results; without a limit every result is returned.
+// The after query parameter only returns blogs with a greater ID, so a client can page by the last ID it got,
+// which stays cheap however deep it pages. Pages are cut by the database, which counts the total separately.
+// The fields query parameter, e.g. fields=id,title,excerpt, selects a sparse fieldset: only those fields are
+// returned, and only the columns holding them are read.
 func (c *BlogController) GetAllBlogs(ctx *gin.Context) {
 	term := ctx.Query("term")
-	// Fetch blogs with an optional search term.
-	blogs, err := c.Service.GetAllBlogs(term)
+	html, ok := renderHTML(ctx)
+	if !ok {
+		return
+	}
+	limit, offset, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+	afterID, err := strconv.Atoi(ctx.DefaultQuery("after", "0"))
+	if err != nil || afterID < 0 {
+		utils.RespondWithError(ctx, http.StatusBadRequest, "after must be a non-negative integer")
+		return
+	}
+	fields, ok := parseFields(ctx)
+	if !ok {
+		return
+	}
+
+	// Fetch a page of the blogs with an optional search term.
+	opts := repository.SearchOptions{Term: term, AfterID: afterID, Fields: storedFields(fields), Limit: limit, Offset: offset}
+	page, total, err := c.Service.SearchBlogs(ctx.Request.Context(), opts)
 	if err != nil {
 		logAndRespond(ctx, http.StatusInternalServerError, "Failed to retrieve blogs", err)
 		return
 	}
 
-	utils.RespondWithJSON(ctx, http.StatusOK, blogs)
+	utils.SetPagination(ctx, utils.Pagination{Total: total, Limit: limit, Offset: offset})
+	if fields != nil {
+		sparse := make([]gin.H, len(page))
+		for i, blog := range page {
+			sparse[i] = c.sparseBlog(blog, fields)
+		}
+		utils.RespondWithJSON(ctx, http.StatusOK, sparse)
+		return
+	}
+	utils.RespondWithJSON(ctx, http.StatusOK, presentBlogs(page, html))
 }
 
 // UpdateBlog updates an existing blog post by its ID via PUT /blogs/:id.
@@ -88,11 +149,15 @@ func (c *BlogController) UpdateBlog(ctx *gin.Context) {
 		logAndRespond(ctx, http.StatusBadRequest, "Invalid blog ID", err)
 		return
 	}
+	html, ok := renderHTML(ctx)
+	if !ok {
+		return
+	}
 
 	var blog models.Blog
-	// Bind incoming JSON payload to the Blog model.
-	if err := ctx.ShouldBindJSON(&blog); err != nil {
-		logAndRespond(ctx, http.StatusBadRequest, "Invalid request payload", err)
+	// Bind the incoming payload, in the format named by its Content-Type, to the Blog model.
+	if err := utils.BindBody(ctx, &blog); err != nil {
+		respondInvalidBody(ctx, err)
 		return
 	}
 
@@ -102,21 +167,15 @@ func (c *BlogController) UpdateBlog(ctx *gin.Context) {
 		return
 	}
 
-	// Assign the blog ID and pass it to the service for update.
+	// Assign the blog ID and pass it to the service, which returns the blog as stored.
 	blog.ID = id
-	if err := c.Service.UpdateBlog(&blog); err != nil {
+	if err := c.Service.UpdateBlog(ctx.Request.Context(), &blog); err != nil {
 		if handleServiceError(ctx, err, "Failed to update blog") {
 			return
 		}
 	}
 
-	// Fetch the updated blog to ensure successful update.
-	updatedBlog, err := c.Service.GetBlogByID(id)
-	if handleServiceError(ctx, err, "Failed to retrieve updated blog") {
-		return
-	}
-
-	utils.RespondWithJSON(ctx, http.StatusOK, updatedBlog)
+	utils.RespondWithJSON(ctx, http.StatusOK, presentBlog(&blog, html))
 }
 
 // DeleteBlog handles DELETE /blogs/:id
@@ -128,7 +187,7 @@ func (c *BlogController) DeleteBlog(ctx *gin.Context) {
 	}
 
 	// Perform the deletion through the service layer.
-	if err := c.Service.DeleteBlog(id); err != nil {
+	if err := c.Service.DeleteBlog(ctx.Request.Context(), id); err != nil {
 		if handleServiceError(ctx, err, "Failed to delete blog") {
 			return
 		}
@@ -137,26 +196,258 @@ func (c *BlogController) DeleteBlog(ctx *gin.Context) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// Batch modes accepted in the mode query parameter of POST /blogs:batch.
+const (
+	BatchModeAtomic     = "atomic"      // All operations apply in one transaction, or none do
+	BatchModeBestEffort = "best-effort" // Each operation applies on its own
+)
+
+// Batch applies an array of create, update and delete operations via POST /blogs:batch.
+// Every operation is validated like the matching single request. In atomic mode (the default) an invalid
+// or failing operation cancels the whole batch; in best-effort mode it only fails itself. The response
+// lists the outcome of every operation with the status it would have had as a single request.
+func (c *BlogController) Batch(ctx *gin.Context) {
+	mode := ctx.DefaultQuery("mode", BatchModeAtomic)
+	if mode != BatchModeAtomic && mode != BatchModeBestEffort {
+		utils.RespondWithError(ctx, http.StatusBadRequest, "mode must be atomic or best-effort")
+		return
+	}
+	atomic := mode == BatchModeAtomic
+	html, ok := renderHTML(ctx)
+	if !ok {
+		return
+	}
+
+	// Decode without binding validation, which would reject the whole batch for a single invalid blog.
+	var ops []models.BatchOperation
+	if err := utils.DecodeBody(ctx, &ops); err != nil {
+		respondInvalidBody(ctx, err)
+		return
+	}
+
+	outcomes, err := services.RunBatch(ctx.Request.Context(), c.Service, ops, atomic)
+	var batchErr *services.BatchError
+	switch {
+	case errors.Is(err, services.ErrEmptyBatch):
+		utils.RespondWithError(ctx, http.StatusBadRequest, "At least one operation is required")
+		return
+	case errors.Is(err, services.ErrBatchTooLarge):
+		utils.RespondWithError(ctx, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("A batch may contain at most %d operations", services.MaxBatchOperations))
+		return
+	case err != nil && !errors.Is(err, services.ErrInvalidBatch) && !errors.As(err, &batchErr):
+		logAndRespond(ctx, http.StatusInternalServerError, "Failed to apply batch", err)
+		return
+	}
+
+	results := make([]models.BatchResult, len(ops))
+	failed := false
+	for i, outcome := range outcomes {
+		result := &results[i]
+		*result = models.BatchResult{Index: i, Op: ops[i].Op, ID: ops[i].ID}
+		batchResult(ctx, result, outcome)
+		if result.Blog != nil {
+			result.Blog = presentBlog(result.Blog, html)
+		}
+		failed = failed || outcome.Err != nil
+	}
+
+	switch {
+	case errors.Is(err, services.ErrInvalidBatch):
+		utils.RespondWithErrorDetails(ctx, http.StatusBadRequest, "Invalid batch operations", gin.H{"results": results})
+	case batchErr != nil:
+		failedResult := results[batchErr.Index]
+		message := fmt.Sprintf("Operation %d failed: %s", failedResult.Index, failedResult.Error)
+		if batchErr.Count > 1 {
+			message = fmt.Sprintf("One of operations %d to %d failed: %s", failedResult.Index, batchErr.Index+batchErr.Count-1, failedResult.Error)
+		}
+		utils.RespondWithErrorDetails(ctx, failedResult.Status, message, gin.H{"results": results})
+	case failed:
+		utils.RespondWithJSON(ctx, http.StatusMultiStatus, gin.H{"results": results})
+	default:
+		utils.RespondWithJSON(ctx, http.StatusOK, gin.H{"results": results})
+	}
+}
+
+// batchResult fills result with the outcome of its operation.
+func batchResult(ctx *gin.Context, result *models.BatchResult, outcome services.BatchOutcome) {
+	switch {
+	case outcome.Err == nil:
+		result.Blog = outcome.Blog
+		if outcome.Blog != nil {
+			result.ID = outcome.Blog.ID
+		}
+		result.Status = map[string]int{
+			models.BatchCreate: http.StatusCreated,
+			models.BatchUpdate: http.StatusOK,
+			models.BatchDelete: http.StatusNoContent,
+		}[result.Op]
+	case errors.As(outcome.Err, new(*services.InvalidOperationError)):
+		result.Status, result.Error = http.StatusBadRequest, outcome.Err.Error()
+	case errors.Is(outcome.Err, services.ErrBatchAborted):
+		result.Status, result.Error = http.StatusFailedDependency, outcome.Err.Error()
+	case errors.Is(outcome.Err, sql.ErrNoRows):
+		result.Status, result.Error = http.StatusNotFound, "Blog not found"
+	default:
+		logging.FromContext(ctx.Request.Context()).Errorf("Batch operation %d failed: %v", result.Index, outcome.Err)
+		result.Status, result.Error = http.StatusInternalServerError, "Failed to apply operation"
+	}
+}
+
+// MaxPageLimit is the largest value accepted in the limit query parameter of GET /blogs.
+const MaxPageLimit = 1000
+
+// FieldExcerpt is the field of sparse fieldsets holding the start of a blog's content as plain text.
+const FieldExcerpt = "excerpt"
+
+// Render modes accepted in the render query parameter.
+const (
+	RenderMarkdown = "markdown" // Content only, as written (the default)
+	RenderHTML     = "html"     // Content and its sanitized HTML rendering in contentHtml
+)
+
 // Helper functions
 
+// renderHTML reports whether the render query parameter asks for the HTML rendering of content.
+// If the parameter is invalid it responds with an error and returns ok false.
+func renderHTML(ctx *gin.Context) (html bool, ok bool) {
+	switch ctx.DefaultQuery("render", RenderMarkdown) {
+	case RenderMarkdown:
+		return false, true
+	case RenderHTML:
+		return true, true
+	default:
+		utils.RespondWithError(ctx, http.StatusBadRequest, "render must be markdown or html")
+		return false, false
+	}
+}
+
+// pageParams parses the limit and offset query parameters, which default to 0 (no limit, from the start).
+// If either is invalid it responds with an error and returns ok false.
+func pageParams(ctx *gin.Context) (limit, offset int, ok bool) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "0"))
+	if err != nil || limit < 0 || limit > MaxPageLimit {
+		utils.RespondWithError(ctx, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d, or 0 for no limit", MaxPageLimit))
+		return 0, 0, false
+	}
+	offset, err = strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		utils.RespondWithError(ctx, http.StatusBadRequest, "offset must be a non-negative integer")
+		return 0, 0, false
+	}
+	return limit, offset, true
+}
+
+// parseFields parses the fields query parameter, a comma-separated list of blog fields and excerpt.
+// It returns nil if the parameter is absent. If it is invalid it responds with an error and returns ok false.
+func parseFields(ctx *gin.Context) (fields []string, ok bool) {
+	param, present := ctx.GetQuery("fields")
+	if !present {
+		return nil, true
+	}
+	for _, field := range strings.Split(param, ",") {
+		field = strings.TrimSpace(field)
+		if field != FieldExcerpt && !repository.ValidField(field) {
+			utils.RespondWithError(ctx, http.StatusBadRequest, fmt.Sprintf("fields must be a comma-separated list of %s",
+				strings.Join(append(slices.Clone(repository.BlogFields), FieldExcerpt), ", ")))
+			return nil, false
+		}
+		fields = append(fields, field)
+	}
+	return fields, true
+}
+
+// storedFields returns the stored fields needed to present fields: the excerpt is cut from the content.
+func storedFields(fields []string) []string {
+	stored := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field == FieldExcerpt {
+			field = repository.FieldContent
+		}
+		stored = append(stored, field)
+	}
+	return stored
+}
+
+// sparseBlog returns the given fields of blog, keyed by their JSON names.
+func (c *BlogController) sparseBlog(blog *models.Blog, fields []string) gin.H {
+	sparse := make(gin.H, len(fields))
+	for _, field := range fields {
+		switch field {
+		case repository.FieldID:
+			sparse[field] = blog.ID
+		case repository.FieldTitle:
+			sparse[field] = blog.Title
+		case repository.FieldContent:
+			sparse[field] = blog.Content
+		case repository.FieldContentHTML:
+			sparse[field] = blog.ContentHTML
+		case repository.FieldCategory:
+			sparse[field] = blog.Category
+		case repository.FieldTags:
+			sparse[field] = blog.Tags
+		case repository.FieldCreatedAt:
+			sparse[field] = blog.CreatedAt
+		case repository.FieldUpdatedAt:
+			sparse[field] = blog.UpdatedAt
+		case FieldExcerpt:
+			sparse[field] = markdown.Excerpt(blog.Content, c.ExcerptLength)
+		}
+	}
+	return sparse
+}
+
+// presentBlog returns blog as it is shown in responses: without its HTML rendering unless html is set.
+func presentBlog(blog *models.Blog, html bool) *models.Blog {
+	if html || blog.ContentHTML == "" {
+		return blog
+	}
+	presented := *blog
+	presented.ContentHTML = ""
+	return &presented
+}
+
+// presentBlogs applies presentBlog to every blog in blogs.
+func presentBlogs(blogs []*models.Blog, html bool) []*models.Blog {
+	if html || blogs == nil {
+		return blogs
+	}
+	presented := make([]*models.Blog, len(blogs))
+	for i, blog := range blogs {
+		presented[i] = presentBlog(blog, false)
+	}
+	return presented
+}
+
 // parseID converts a string parameter to an integer.
 // It returns an error if the conversion fails.
 func parseID(param string) (int, error) {
 	return strconv.Atoi(param)
 }
 
-// logAndRespond logs the error and sends a JSON response with the provided status code and message.
+// logAndRespond logs the error with the request-scoped logger and sends a JSON response with the provided status code and message.
 func logAndRespond(ctx *gin.Context, status int, message string, err error) {
-	log.Errorf("%s: %v", message, err)
+	logging.FromContext(ctx.Request.Context()).Errorf("%s: %v", message, err)
 	utils.RespondWithError(ctx, status, message)
 }
 
+// respondInvalidBody responds to a request body that could not be decoded or validated: with 415 if its
+// Content-Type is not supported, otherwise with 400.
+func respondInvalidBody(ctx *gin.Context, err error) {
+	if errors.Is(err, utils.ErrUnsupportedMediaType) {
+		logging.FromContext(ctx.Request.Context()).Debugf("Rejected request payload: %v", err)
+		utils.RespondWithError(ctx, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
+	logAndRespond(ctx, http.StatusBadRequest, "Invalid request payload", err)
+}
+
 // handleServiceError handles common errors from the service layer.
 // It checks for specific conditions (e.g., sql.ErrNoRows) and responds accordingly.
 // Returns true if an error is handler, otherwise false.
 func handleServiceError(ctx *gin.Context, err error, message string) bool {
 	if err != nil {
-		log.Errorf("%s: %v", message, err)
+		logging.FromContext(ctx.Request.Context()).Errorf("%s: %v", message, err)
 		if errors.Is(err, sql.ErrNoRows) {
 			utils.RespondWithError(ctx, http.StatusNotFound, "Blog not found")
 		} else {
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"bloggingplatformapi/internal/feed"
+	"bloggingplatformapi/internal/repository"
+	"bloggingplatformapi/internal/services"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeedController serves RSS, Atom and JSON feeds of the newest blogs.
+type FeedController struct {
+	Service  services.BlogService
+	Site     feed.Site // Site metadata shown in every feed
+	MaxItems int       // Number of posts in each feed
+}
+
+// NewFeedController creates a new instance of FeedController serving up to maxItems posts per feed,
+// or feed.DefaultMaxItems if maxItems is not positive.
+func NewFeedController(service services.BlogService, site feed.Site, maxItems int) *FeedController {
+	if maxItems <= 0 {
+		maxItems = feed.DefaultMaxItems
+	}
+	return &FeedController{Service: service, Site: site, MaxItems: maxItems}
+}
+
+// RSS serves the RSS 2.0 feed of every post via GET /feeds/rss.xml, of a category via
+// GET /feeds/categories/:category/rss.xml or of a tag via GET /feeds/tags/:tag/rss.xml.
+func (c *FeedController) RSS(ctx *gin.Context) {
+	c.serve(ctx, feed.RSS, feed.RSSContentType)
+}
+
+// Atom serves the Atom 1.0 feed of every post via GET /feeds/atom.xml, of a category via
+// GET /feeds/categories/:category/atom.xml or of a tag via GET /feeds/tags/:tag/atom.xml.
+func (c *FeedController) Atom(ctx *gin.Context) {
+	c.serve(ctx, feed.Atom, feed.AtomContentType)
+}
+
+// JSON serves the JSON Feed 1.1 feed of every post via GET /feeds/feed.json, of a category via
+// GET /feeds/categories/:category/feed.json or of a tag via GET /feeds/tags/:tag/feed.json.
+func (c *FeedController) JSON(ctx *gin.Context) {
+	c.serve(ctx, feed.JSON, feed.JSONContentType)
+}
+
+// serve builds the feed selected by the route parameters with encode and sends it.
+// Responses carry an ETag derived from the document and a Last-Modified time from the newest update
+// of its posts, so readers polling with If-None-Match or If-Modified-Since get 304 Not Modified.
+func (c *FeedController) serve(ctx *gin.Context, encode func(feed.Feed) ([]byte, error), contentType string) {
+	opts := repository.ListOptions{Category: ctx.Param("category"), Tag: ctx.Param("tag"), Limit: c.MaxItems}
+	blogs, err := c.Service.ListBlogs(ctx.Request.Context(), opts)
+	if err != nil {
+		logAndRespond(ctx, http.StatusInternalServerError, "Failed to retrieve blogs", err)
+		return
+	}
+
+	f := feed.Feed{Site: c.Site, Title: c.title(opts), Self: c.Site.Link(ctx.Request.URL.Path), Blogs: blogs}
+	body, err := encode(f)
+	if err != nil {
+		logAndRespond(ctx, http.StatusInternalServerError, "Failed to build feed", err)
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	http.ServeContent(ctx.Writer, ctx.Request, "", f.Updated(), bytes.NewReader(body))
+}
+
+// title returns the title of the feed selected by opts.
+func (c *FeedController) title(opts repository.ListOptions) string {
+	switch {
+	case opts.Category != "":
+		return fmt.Sprintf("%s: %s", c.Site.Title, opts.Category)
+	case opts.Tag != "":
+		return fmt.Sprintf("%s: posts tagged %s", c.Site.Title, opts.Tag)
+	default:
+		return c.Site.Title
+	}
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"bloggingplatformapi/internal/graph"
+	"bloggingplatformapi/internal/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQLController serves GraphQL requests over HTTP.
+type GraphQLController struct {
+	Server *graph.Server
+}
+
+// NewGraphQLController creates a new instance of GraphQLController executing requests with server.
+func NewGraphQLController(server *graph.Server) *GraphQLController {
+	return &GraphQLController{Server: server}
+}
+
+// Query executes a query sent via GET /graphql in the query, operationName and variables query parameters,
+// the last as JSON. Mutations are refused with 405 Method Not Allowed, since GET requests must not write.
+func (c *GraphQLController) Query(ctx *gin.Context) {
+	var req graph.Request
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		logAndRespond(ctx, http.StatusBadRequest, "Invalid GraphQL request", err)
+		return
+	}
+	if variables := ctx.Query("variables"); variables != "" {
+		if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+			logAndRespond(ctx, http.StatusBadRequest, "variables must be a JSON object", err)
+			return
+		}
+	}
+	c.execute(ctx, req, true)
+}
+
+// Execute executes a query or mutation sent via POST /graphql as a JSON object with query, operationName and
+// variables members.
+func (c *GraphQLController) Execute(ctx *gin.Context) {
+	var req graph.Request
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logAndRespond(ctx, http.StatusBadRequest, "Invalid GraphQL request", err)
+		return
+	}
+	c.execute(ctx, req, false)
+}
+
+// execute runs req and sends its result. As usual for GraphQL, errors in the document or in resolving fields are
+// reported in the errors member of a 200 OK response.
+func (c *GraphQLController) execute(ctx *gin.Context, req graph.Request, readOnly bool) {
+	if req.Query == "" {
+		utils.RespondWithError(ctx, http.StatusBadRequest, "query is required")
+		return
+	}
+	result, err := c.Server.Execute(ctx.Request.Context(), req, readOnly)
+	if errors.Is(err, graph.ErrMutationNotAllowed) {
+		ctx.Header("Allow", http.MethodPost)
+		utils.RespondWithError(ctx, http.StatusMethodNotAllowed, err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"bloggingplatformapi/internal/health"
+	"bloggingplatformapi/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthController serves the liveness, readiness and detailed health endpoints.
+type HealthController struct {
+	Checker *health.Checker
+}
+
+// NewHealthController creates a new instance of HealthController with the provided Checker.
+func NewHealthController(checker *health.Checker) *HealthController {
+	return &HealthController{checker}
+}
+
+// Liveness reports that the process is running via GET /healthz.
+// It never touches dependencies so a slow database cannot get the process restarted.
+func (c *HealthController) Liveness(ctx *gin.Context) {
+	utils.RespondWithJSON(ctx, http.StatusOK, gin.H{"status": health.StatusUp})
+}
+
+// Readiness reports whether the service can take traffic via GET /readyz.
+// It fails as soon as graceful shutdown begins or any dependency check fails.
+func (c *HealthController) Readiness(ctx *gin.Context) {
+	if c.Checker.ShuttingDown() {
+		utils.RespondWithJSON(ctx, http.StatusServiceUnavailable, gin.H{"status": health.StatusDown, "shuttingDown": true})
+		return
+	}
+
+	report := c.Checker.Run(ctx.Request.Context())
+	utils.RespondWithJSON(ctx, statusCode(report), gin.H{"status": report.Status})
+}
+
+// Health returns the detailed result of every dependency check via GET /health.
+func (c *HealthController) Health(ctx *gin.Context) {
+	report := c.Checker.Run(ctx.Request.Context())
+	utils.RespondWithJSON(ctx, statusCode(report), report)
+}
+
+// statusCode maps a health report to the HTTP status returned to probes.
+func statusCode(report health.Report) int {
+	if report.Healthy() {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
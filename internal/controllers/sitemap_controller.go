@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"bloggingplatformapi/internal/feed"
+	"bloggingplatformapi/internal/logging"
+	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/internal/services"
+	"bloggingplatformapi/internal/sitemap"
+	"bloggingplatformapi/internal/utils"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SitemapController serves sitemaps listing the URL of every post, for search engines.
+type SitemapController struct {
+	Service services.BlogService
+	Site    feed.Site // Site the post URLs belong to
+	MaxURLs int       // Number of posts in a sitemap before it is split under a sitemap index
+}
+
+// NewSitemapController creates a new instance of SitemapController that splits sitemaps beyond maxURLs posts,
+// or sitemap.MaxURLs if maxURLs is not positive or above the protocol's limit.
+func NewSitemapController(service services.BlogService, site feed.Site, maxURLs int) *SitemapController {
+	if maxURLs <= 0 || maxURLs > sitemap.MaxURLs {
+		maxURLs = sitemap.MaxURLs
+	}
+	return &SitemapController{Service: service, Site: site, MaxURLs: maxURLs}
+}
+
+// Sitemap serves GET /sitemap.xml. Up to MaxURLs posts are listed directly; beyond that it is a sitemap index
+// of the pages served by SitemapPage, so search engines fetch them separately.
+func (c *SitemapController) Sitemap(ctx *gin.Context) {
+	stats, err := c.Service.BlogStats(ctx.Request.Context())
+	if err != nil {
+		logAndRespond(ctx, http.StatusInternalServerError, "Failed to retrieve blogs", err)
+		return
+	}
+	if stats.Count <= c.MaxURLs {
+		c.stream(ctx, 0, 0)
+		return
+	}
+
+	w, err := c.start(ctx, sitemap.NewIndexWriter)
+	for page := 1; err == nil && page <= c.pages(stats.MaxID); page++ {
+		err = w.Add(c.Site.Link(fmt.Sprintf("/sitemaps/blogs-%d.xml", page)), time.Time{})
+	}
+	c.finish(ctx, w, err)
+}
+
+// SitemapPage serves page N of the sitemap index via GET /sitemaps/blogs-N.xml. Page N lists the posts whose
+// IDs are in ((N-1)*MaxURLs, N*MaxURLs], so a post stays on the same page when others are deleted.
+func (c *SitemapController) SitemapPage(ctx *gin.Context) {
+	name := ctx.Param("name")
+	page, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "blogs-"), ".xml"))
+	if err != nil || page < 1 || name != fmt.Sprintf("blogs-%d.xml", page) {
+		utils.RespondWithError(ctx, http.StatusNotFound, "Sitemap not found")
+		return
+	}
+	stats, err := c.Service.BlogStats(ctx.Request.Context())
+	if err != nil {
+		logAndRespond(ctx, http.StatusInternalServerError, "Failed to retrieve blogs", err)
+		return
+	}
+	if page > c.pages(stats.MaxID) {
+		utils.RespondWithError(ctx, http.StatusNotFound, "Sitemap not found")
+		return
+	}
+	c.stream(ctx, (page-1)*c.MaxURLs, page*c.MaxURLs)
+}
+
+// pages returns the number of pages of the sitemap index when the highest blog ID is maxID.
+func (c *SitemapController) pages(maxID int) int {
+	return max(1, (maxID+c.MaxURLs-1)/c.MaxURLs)
+}
+
+// stream sends a sitemap of the posts whose IDs are in (afterID, lastID], writing each as it is read.
+func (c *SitemapController) stream(ctx *gin.Context, afterID, lastID int) {
+	w, err := c.start(ctx, sitemap.NewWriter)
+	if err == nil {
+		err = c.Service.ExportBlogs(ctx.Request.Context(), afterID, lastID, func(blog *models.Blog) error {
+			return w.Add(c.Site.PostLink(blog.ID), blog.UpdatedAt)
+		})
+	}
+	c.finish(ctx, w, err)
+}
+
+// start sends the response headers and begins a document with newWriter.
+func (c *SitemapController) start(ctx *gin.Context, newWriter func(w io.Writer) (*sitemap.Writer, error)) (*sitemap.Writer, error) {
+	// A sitemap of many posts can outlast the server's write timeout.
+	if err := http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		logging.FromContext(ctx.Request.Context()).Warnf("Failed to clear the write deadline of the sitemap: %v", err)
+	}
+	ctx.Header("Content-Type", sitemap.ContentType)
+	ctx.Status(http.StatusOK)
+	return newWriter(ctx.Writer)
+}
+
+// finish ends the document written by w, or logs err if writing it failed.
+func (c *SitemapController) finish(ctx *gin.Context, w *sitemap.Writer, err error) {
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		// The status has been sent, so the client can only learn of the failure from the truncated document.
+		logging.FromContext(ctx.Request.Context()).Errorf("Failed to write sitemap: %v", err)
+		ctx.Abort()
+	}
+}
@@ -0,0 +1,92 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// atomNamespace is the XML namespace of Atom 1.0.
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+// atomFeed is the root element of an Atom 1.0 document.
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang     string      `xml:"xml:lang,attr,omitempty"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Author   atomAuthor  `xml:"author"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+// atomLink relates the feed or an entry to a URL.
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// atomAuthor names the author of the feed, which every entry inherits.
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+// atomEntry is a single post.
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Categories []atomCategory `xml:"category"`
+	Content    atomContent    `xml:"content"`
+}
+
+// atomCategory files an entry under a term.
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// atomContent holds the HTML rendering of a post, escaped as text.
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Atom encodes the feed as an Atom 1.0 document. Each entry's content holds the post's HTML rendering.
+// Atom requires an author, so the site title stands in when none is configured.
+func Atom(f Feed) ([]byte, error) {
+	author := f.Site.Author
+	if author == "" {
+		author = f.Site.Title
+	}
+	doc := atomFeed{
+		Lang:     f.Site.Language,
+		ID:       f.Self,
+		Title:    f.Title,
+		Subtitle: f.Site.Description,
+		Updated:  f.Updated().UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.Self, Rel: "self", Type: "application/atom+xml"},
+			{Href: f.Site.URL, Rel: "alternate"},
+		},
+		Author: atomAuthor{Name: author},
+	}
+	for _, blog := range f.Blogs {
+		entry := atomEntry{
+			ID:        f.Site.GUID(blog),
+			Title:     blog.Title,
+			Links:     []atomLink{{Href: f.Site.PostLink(blog.ID), Rel: "alternate"}},
+			Published: blog.CreatedAt.UTC().Format(time.RFC3339),
+			Updated:   blog.UpdatedAt.UTC().Format(time.RFC3339),
+			Content:   atomContent{Type: "html", Value: blog.ContentHTML},
+		}
+		for _, term := range categories(blog) {
+			entry.Categories = append(entry.Categories, atomCategory{Term: term})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return marshal(doc)
+}
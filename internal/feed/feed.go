@@ -0,0 +1,83 @@
+// Package feed builds RSS 2.0, Atom 1.0 and JSON Feed 1.1 feeds of blogs.
+package feed
+
+import (
+	"bloggingplatformapi/internal/models"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Media types of the feed formats.
+const (
+	RSSContentType  = "application/rss+xml; charset=utf-8"
+	AtomContentType = "application/atom+xml; charset=utf-8"
+	JSONContentType = "application/feed+json; charset=utf-8"
+)
+
+// DefaultMaxItems is the number of posts in a feed when none is configured.
+const DefaultMaxItems = 20
+
+// Site describes the site feeds belong to.
+type Site struct {
+	Title       string // Title of the site and of its main feed
+	Description string // What the site is about
+	URL         string // Absolute URL of the site, where the API and its feeds are served
+	PostURL     string // Absolute URL of a post with {id} in place of its ID; empty for the post in the API
+	Author      string // Author of the posts, empty for none
+	Language    string // Language of the posts, as a language tag such as en
+}
+
+// PostLink returns the absolute URL of a blog.
+func (s Site) PostLink(id int) string {
+	if s.PostURL != "" {
+		return strings.ReplaceAll(s.PostURL, "{id}", strconv.Itoa(id))
+	}
+	return strings.TrimSuffix(s.URL, "/") + "/api/v1/blogs/" + strconv.Itoa(id)
+}
+
+// Link returns the absolute URL of path on the site.
+func (s Site) Link(path string) string {
+	return strings.TrimSuffix(s.URL, "/") + path
+}
+
+// GUID returns the permanent identifier of a blog in feeds: a tag URI (RFC 4151) made of the site's
+// host, the blog's creation date and its ID, e.g. tag:example.com,2024-10-16:blogs/12. It does not
+// change when the blog is edited, or when FEED_POST_URL moves posts elsewhere.
+func (s Site) GUID(blog *models.Blog) string {
+	return fmt.Sprintf("tag:%s,%s:blogs/%d", s.host(), blog.CreatedAt.UTC().Format(time.DateOnly), blog.ID)
+}
+
+// host returns the host name of the site URL, or localhost if it has none.
+func (s Site) host() string {
+	if u, err := url.Parse(s.URL); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return "localhost"
+}
+
+// Feed is a list of blogs published as a feed: all posts, or those of a category or tag.
+type Feed struct {
+	Site  Site
+	Title string         // Title of this feed
+	Self  string         // Absolute URL the feed is served at
+	Blogs []*models.Blog // Posts in the feed, newest first
+}
+
+// Updated returns when the feed last changed: the latest update time of its blogs, or the zero time if it has none.
+func (f Feed) Updated() time.Time {
+	var updated time.Time
+	for _, blog := range f.Blogs {
+		if blog.UpdatedAt.After(updated) {
+			updated = blog.UpdatedAt
+		}
+	}
+	return updated
+}
+
+// categories returns the category of a blog followed by its tags, the terms it is filed under in feeds.
+func categories(blog *models.Blog) []string {
+	return append([]string{blog.Category}, blog.Tags...)
+}
@@ -0,0 +1,130 @@
+package feed
+
+import (
+	"bloggingplatformapi/internal/models"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFeed returns a feed of two blogs, the newer one edited after it was created.
+func testFeed() Feed {
+	created := time.Date(2024, 10, 16, 14, 45, 0, 0, time.UTC)
+	return Feed{
+		Site:  Site{Title: "Blog", Description: "Latest posts", URL: "https://example.com:8443/", Language: "en"},
+		Title: "Blog: Tech",
+		Self:  "https://example.com:8443/feeds/categories/Tech/atom.xml",
+		Blogs: []*models.Blog{
+			{ID: 2, Title: "Second", ContentHTML: "<p>Two &amp; more</p>", Category: "Tech", Tags: []string{"Go"},
+				CreatedAt: created.Add(time.Hour), UpdatedAt: created.Add(48 * time.Hour)},
+			{ID: 1, Title: "First", ContentHTML: "<p>One</p>", Category: "Tech", Tags: []string{},
+				CreatedAt: created, UpdatedAt: created},
+		},
+	}
+}
+
+func TestSite_PostLinkAndGUID(t *testing.T) {
+	t.Parallel()
+
+	site := testFeed().Site
+	blog := testFeed().Blogs[0]
+	assert.Equal(t, "https://example.com:8443/api/v1/blogs/2", site.PostLink(blog.ID))
+	assert.Equal(t, "tag:example.com,2024-10-16:blogs/2", site.GUID(blog))
+
+	site.PostURL = "https://blog.example.com/posts/{id}"
+	assert.Equal(t, "https://blog.example.com/posts/2", site.PostLink(blog.ID))
+	assert.Equal(t, "tag:example.com,2024-10-16:blogs/2", site.GUID(blog), "moving posts keeps their GUID")
+}
+
+func TestRSS(t *testing.T) {
+	t.Parallel()
+
+	data, err := RSS(testFeed())
+	require.NoError(t, err)
+
+	var doc struct {
+		Channel struct {
+			Title         string `xml:"title"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				GUID        string   `xml:"guid"`
+				PubDate     string   `xml:"pubDate"`
+				Categories  []string `xml:"category"`
+				Description string   `xml:"description"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	require.NoError(t, xml.Unmarshal(data, &doc))
+	assert.Equal(t, "Blog: Tech", doc.Channel.Title)
+	assert.Equal(t, "Fri, 18 Oct 2024 14:45:00 +0000", doc.Channel.LastBuildDate)
+	require.Len(t, doc.Channel.Items, 2)
+	assert.Equal(t, "tag:example.com,2024-10-16:blogs/2", doc.Channel.Items[0].GUID)
+	assert.Equal(t, "Wed, 16 Oct 2024 15:45:00 +0000", doc.Channel.Items[0].PubDate)
+	assert.Equal(t, []string{"Tech", "Go"}, doc.Channel.Items[0].Categories)
+	assert.Equal(t, "<p>Two &amp; more</p>", doc.Channel.Items[0].Description)
+}
+
+func TestAtom(t *testing.T) {
+	t.Parallel()
+
+	data, err := Atom(testFeed())
+	require.NoError(t, err)
+
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		Updated string   `xml:"updated"`
+		Author  string   `xml:"author>name"`
+		Links   []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Updated string `xml:"updated"`
+			Content string `xml:"content"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(data, &doc))
+	assert.Equal(t, "2024-10-18T14:45:00Z", doc.Updated)
+	assert.Equal(t, "Blog", doc.Author, "the site title stands in for a missing author")
+	assert.Equal(t, "https://example.com:8443/feeds/categories/Tech/atom.xml", doc.Links[0].Href)
+	assert.Equal(t, "self", doc.Links[0].Rel)
+	require.Len(t, doc.Entries, 2)
+	assert.Equal(t, "tag:example.com,2024-10-16:blogs/1", doc.Entries[1].ID)
+	assert.Equal(t, "2024-10-18T14:45:00Z", doc.Entries[0].Updated)
+	assert.Equal(t, "<p>One</p>", doc.Entries[1].Content)
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+
+	data, err := JSON(testFeed())
+	require.NoError(t, err)
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, "https://jsonfeed.org/version/1.1", doc["version"])
+	assert.Equal(t, "https://example.com:8443/feeds/categories/Tech/atom.xml", doc["feed_url"])
+	assert.NotContains(t, doc, "authors", "authors are omitted when none is configured")
+	items := doc["items"].([]any)
+	require.Len(t, items, 2)
+	assert.Equal(t, map[string]any{
+		"id":             "tag:example.com,2024-10-16:blogs/2",
+		"url":            "https://example.com:8443/api/v1/blogs/2",
+		"title":          "Second",
+		"content_html":   "<p>Two &amp; more</p>",
+		"date_published": "2024-10-16T15:45:00Z",
+		"date_modified":  "2024-10-18T14:45:00Z",
+		"tags":           []any{"Tech", "Go"},
+	}, items[0])
+
+	empty := testFeed()
+	empty.Blogs = nil
+	data, err = JSON(empty)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"items": []`)
+}
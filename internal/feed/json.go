@@ -0,0 +1,70 @@
+package feed
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// jsonFeedVersion identifies the JSON Feed specification a document follows.
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+// jsonFeed is the top-level object of a JSON Feed 1.1 document.
+type jsonFeed struct {
+	Version     string       `json:"version"`
+	Title       string       `json:"title"`
+	HomePageURL string       `json:"home_page_url"`
+	FeedURL     string       `json:"feed_url"`
+	Description string       `json:"description,omitempty"`
+	Language    string       `json:"language,omitempty"`
+	Authors     []jsonAuthor `json:"authors,omitempty"`
+	Items       []jsonItem   `json:"items"`
+}
+
+// jsonAuthor names the author of the feed, which every item inherits.
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+// jsonItem is a single post.
+type jsonItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	ContentHTML   string   `json:"content_html"`
+	DatePublished string   `json:"date_published"`
+	DateModified  string   `json:"date_modified"`
+	Tags          []string `json:"tags"`
+}
+
+// JSON encodes the feed as a JSON Feed 1.1 document. Each item's content_html holds the post's HTML rendering
+// and its tags are the post's category followed by its tags.
+func JSON(f Feed) ([]byte, error) {
+	doc := jsonFeed{
+		Version:     jsonFeedVersion,
+		Title:       f.Title,
+		HomePageURL: f.Site.URL,
+		FeedURL:     f.Self,
+		Description: f.Site.Description,
+		Language:    f.Site.Language,
+		Items:       []jsonItem{},
+	}
+	if f.Site.Author != "" {
+		doc.Authors = []jsonAuthor{{Name: f.Site.Author}}
+	}
+	for _, blog := range f.Blogs {
+		doc.Items = append(doc.Items, jsonItem{
+			ID:            f.Site.GUID(blog),
+			URL:           f.Site.PostLink(blog.ID),
+			Title:         blog.Title,
+			ContentHTML:   blog.ContentHTML,
+			DatePublished: blog.CreatedAt.UTC().Format(time.RFC3339),
+			DateModified:  blog.UpdatedAt.UTC().Format(time.RFC3339),
+			Tags:          categories(blog),
+		})
+	}
+	body, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(body, '\n'), nil
+}
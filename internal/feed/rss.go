@@ -0,0 +1,79 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// rss is the root element of an RSS 2.0 document.
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+// rssChannel describes the feed.
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	AtomLink      atomLink  `xml:"atom:link"` // Self link, recommended by the RSS Advisory Board
+	Items         []rssItem `xml:"item"`
+}
+
+// rssItem is a single post.
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description"` // HTML, escaped as text
+}
+
+// rssGUID identifies an item; it is not a link, since posts may move.
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS encodes the feed as an RSS 2.0 document. Each item's description holds the post's HTML rendering.
+func RSS(f Feed) ([]byte, error) {
+	doc := rss{
+		Version: "2.0",
+		AtomNS:  atomNamespace,
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        f.Site.URL,
+			Description: f.Site.Description,
+			Language:    f.Site.Language,
+			AtomLink:    atomLink{Href: f.Self, Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+	if updated := f.Updated(); !updated.IsZero() {
+		doc.Channel.LastBuildDate = updated.UTC().Format(time.RFC1123Z)
+	}
+	for _, blog := range f.Blogs {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       blog.Title,
+			Link:        f.Site.PostLink(blog.ID),
+			GUID:        rssGUID{Value: f.Site.GUID(blog)},
+			PubDate:     blog.CreatedAt.UTC().Format(time.RFC1123Z),
+			Categories:  categories(blog),
+			Description: blog.ContentHTML,
+		})
+	}
+	return marshal(doc)
+}
+
+// marshal encodes doc as an indented XML document with a declaration.
+func marshal(doc any) ([]byte, error) {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(body, '\n')...), nil
+}
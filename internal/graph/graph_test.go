@@ -0,0 +1,189 @@
+package graph
+
+import (
+	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/internal/repository"
+	"bloggingplatformapi/internal/services"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingBlogService counts the reads of several blogs that reach the wrapped service.
+type countingBlogService struct {
+	services.BlogService
+	reads  int
+	groups [][]repository.ListOptions // Groups of every ListBlogGroups call
+}
+
+func (s *countingBlogService) GetAllBlogs(ctx context.Context, term string, fields ...string) ([]*models.Blog, error) {
+	s.reads++
+	return s.BlogService.GetAllBlogs(ctx, term, fields...)
+}
+
+func (s *countingBlogService) SearchBlogs(ctx context.Context, opts repository.SearchOptions) ([]*models.Blog, int, error) {
+	s.reads++
+	return s.BlogService.SearchBlogs(ctx, opts)
+}
+
+func (s *countingBlogService) ListBlogs(ctx context.Context, opts repository.ListOptions) ([]*models.Blog, error) {
+	s.reads++
+	return s.BlogService.ListBlogs(ctx, opts)
+}
+
+func (s *countingBlogService) ListBlogGroups(ctx context.Context, groups []repository.ListOptions) ([]*models.Blog, error) {
+	s.reads++
+	s.groups = append(s.groups, groups)
+	return s.BlogService.ListBlogGroups(ctx, groups)
+}
+
+// setupServer returns a server over an in-memory service holding blogs and the counter of its reads.
+func setupServer(t *testing.T, maxDepth, maxComplexity int, blogs ...*models.Blog) (*Server, *countingBlogService) {
+	t.Helper()
+	repo := repository.NewMemoryBlogRepository()
+	service := &countingBlogService{BlogService: services.NewBlogService(repo, repo.UnitOfWork())}
+	for _, blog := range blogs {
+		require.NoError(t, service.CreateBlog(context.Background(), blog))
+	}
+	service.reads = 0
+	return NewServer(service, 0, maxDepth, maxComplexity), service
+}
+
+// execute runs query and returns its result as JSON.
+func execute(t *testing.T, server *Server, query string, variables map[string]any) string {
+	t.Helper()
+	result, err := server.Execute(context.Background(), Request{Query: query, Variables: variables}, false)
+	require.NoError(t, err)
+	body, err := json.Marshal(result)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestServer_BatchesPostsOfCategoriesAndTags(t *testing.T) {
+	t.Parallel()
+	server, service := setupServer(t, 0, 0,
+		&models.Blog{Title: "First", Content: "One", Category: "Tech", Tags: []string{"Go", "go"}},
+		&models.Blog{Title: "Second", Content: "Two", Category: "Life", Tags: []string{"Go"}},
+	)
+
+	body := execute(t, server, `{
+		blogs(limit: 5) { items { title category { name posts(limit: 5) { title } } tags { name posts(limit: 5) { title } } } }
+	}`, nil)
+
+	assert.NotContains(t, body, "errors")
+	assert.Contains(t, body, `"category":{"name":"Tech","posts":[{"title":"First"}]}`)
+	assert.Contains(t, body, `{"name":"Go","posts":[{"title":"Second"},{"title":"First"}]}`)
+	// One read for the page, one for the posts of every category and tag
+	assert.Equal(t, 2, service.reads)
+	assert.Equal(t, [][]repository.ListOptions{{
+		{Category: "life", Limit: 5}, {Category: "tech", Limit: 5}, {Tag: "go", Limit: 5},
+	}}, service.groups)
+}
+
+func TestServer_LoadsAsManyPostsAsAsked(t *testing.T) {
+	t.Parallel()
+	server, service := setupServer(t, 0, 0,
+		&models.Blog{Title: "First", Content: "One", Category: "Tech", Tags: []string{"Go"}},
+		&models.Blog{Title: "Second", Content: "Two", Category: "Tech", Tags: []string{"Go"}},
+		&models.Blog{Title: "Third", Content: "Three", Category: "Tech", Tags: []string{"Rust"}},
+	)
+
+	body := execute(t, server, `{
+		tech: category(name: "tech") { posts(limit: 1) { title } }
+		go: tag(name: "Go") { posts(limit: 2) { title } }
+	}`, nil)
+
+	assert.JSONEq(t, `{"data":{"tech":{"posts":[{"title":"Third"}]},"go":{"posts":[{"title":"Second"},{"title":"First"}]}}}`, body)
+	// Checking that a category or tag exists asks for one post; its posts field asks for more, in the next read
+	assert.Equal(t, [][]repository.ListOptions{
+		{{Category: "tech", Limit: 1}, {Tag: "go", Limit: 1}},
+		{{Tag: "go", Limit: 2}},
+	}, service.groups)
+}
+
+func TestServer_FiltersAndPaginates(t *testing.T) {
+	t.Parallel()
+	server, _ := setupServer(t, 0, 0,
+		&models.Blog{Title: "First", Content: "One", Category: "Tech", Tags: []string{"Go"}},
+		&models.Blog{Title: "Second", Content: "Two", Category: "tech", Tags: []string{"Rust"}},
+		&models.Blog{Title: "Third", Content: "Three", Category: "Tech", Tags: []string{"Go"}},
+	)
+
+	body := execute(t, server, `query($limit: Int) {
+		blogs(category: "TECH", tag: "go", limit: $limit, offset: 1) { total limit offset items { title } }
+	}`, map[string]any{"limit": 1})
+
+	assert.JSONEq(t, `{"data":{"blogs":{"total":2,"limit":1,"offset":1,"items":[{"title":"Third"}]}}}`, body)
+}
+
+func TestServer_PagesCategoriesAndTags(t *testing.T) {
+	t.Parallel()
+	server, _ := setupServer(t, 0, 0,
+		&models.Blog{Title: "First", Content: "One", Category: "Tech", Tags: []string{"Go", "SQL"}},
+		&models.Blog{Title: "Second", Content: "Two", Category: "Life", Tags: []string{"rust"}},
+	)
+
+	body := execute(t, server, `{ categories(limit: 1) { name } tags(limit: 2, offset: 1) { name } }`, nil)
+	assert.JSONEq(t, `{"data":{"categories":[{"name":"Life"}],"tags":[{"name":"rust"},{"name":"SQL"}]}}`, body)
+
+	body = execute(t, server, `{ tags(limit: 101) { name } }`, nil)
+	assert.Contains(t, body, "limit must be between 1 and 100")
+
+	// tags: 1 + 100 * name: 1, counted as the page asked for rather than the default limit
+	server, _ = setupServer(t, 0, 100)
+	body = execute(t, server, `{ tags(limit: 100) { name } }`, nil)
+	assert.Contains(t, body, "query complexity 101 exceeds the limit of 100")
+}
+
+func TestServer_MutationsValidateInput(t *testing.T) {
+	t.Parallel()
+	server, _ := setupServer(t, 0, 0)
+
+	body := execute(t, server, `mutation {
+		createBlog(input: {title: "New", content: "*Hi*", category: "Tech", tags: ["Go"]}) { id contentHtml }
+	}`, nil)
+	assert.JSONEq(t, `{"data":{"createBlog":{"id":1,"contentHtml":"<p><em>Hi</em></p>\n"}}}`, body)
+
+	body = execute(t, server, `mutation {
+		updateBlog(id: 1, input: {title: " ", content: "Hi", category: "Tech", tags: ["Go"]}) { id }
+	}`, nil)
+	assert.Contains(t, body, `"message":"title is required"`)
+	assert.Contains(t, body, `"code":"BAD_USER_INPUT"`)
+
+	body = execute(t, server, `mutation { deleteBlog(id: 2) }`, nil)
+	assert.Contains(t, body, `"code":"NOT_FOUND"`)
+}
+
+func TestServer_RefusesMutationsWhenReadOnly(t *testing.T) {
+	t.Parallel()
+	server, _ := setupServer(t, 0, 0)
+
+	_, err := server.Execute(context.Background(), Request{Query: `mutation { deleteBlog(id: 1) }`}, true)
+	assert.ErrorIs(t, err, ErrMutationNotAllowed)
+}
+
+func TestServer_LimitsDepthAndComplexity(t *testing.T) {
+	t.Parallel()
+	deep := `{ blogs { items { tags { posts { tags { name } } } } } }`
+
+	server, _ := setupServer(t, 5, 0)
+	body := execute(t, server, deep, nil)
+	assert.Contains(t, body, "query depth 6 exceeds the limit of 5")
+	assert.Contains(t, body, `"code":"QUERY_TOO_COSTLY"`)
+
+	// blogs: 1 + 2 * (items: 1 + (tags: 1 + 20 * name: 1))
+	server, _ = setupServer(t, 0, 45)
+	assert.NotContains(t, execute(t, server, `{ blogs(limit: 2) { items { tags { name } } } }`, nil), "errors")
+	server, _ = setupServer(t, 0, 44)
+	body = execute(t, server, `query($n: Int) { blogs(limit: $n) { items { tags { name } } } }`, map[string]any{"n": 2.0})
+	assert.Contains(t, body, "query complexity 45 exceeds the limit of 44")
+}
+
+func TestMeasure_IgnoresIntrospection(t *testing.T) {
+	t.Parallel()
+	server, _ := setupServer(t, 1, 1)
+	assert.NotContains(t, execute(t, server, `{ __schema { queryType { fields { name args { name } } } } }`, nil), "errors")
+}
@@ -0,0 +1,105 @@
+package graph
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// cost is the measured size of an operation, checked against the server's limits before it is executed.
+type cost struct {
+	depth      int // Deepest nesting of fields, counting top-level fields as 1
+	complexity int // Number of fields resolved, with list fields counted once per item they may return
+}
+
+// measurer measures the selections of one operation of a validated document.
+type measurer struct {
+	fragments map[string]*ast.FragmentDefinition // Fragments of the document by name
+	variables map[string]any                     // Variables of the request, with the operation's defaults
+}
+
+// measure returns the cost of operation in doc. The document must have passed validation, which rules out
+// unknown and cyclic fragments. Introspection fields, whose names start with "__", are free.
+func measure(doc *ast.Document, operation *ast.OperationDefinition, variables map[string]any) cost {
+	m := measurer{fragments: make(map[string]*ast.FragmentDefinition), variables: make(map[string]any)}
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			m.fragments[fragment.Name.Value] = fragment
+		}
+	}
+	for _, definition := range operation.VariableDefinitions {
+		if definition.DefaultValue != nil {
+			m.variables[definition.Variable.Name.Value] = definition.DefaultValue.GetValue()
+		}
+	}
+	for name, value := range variables {
+		m.variables[name] = value
+	}
+	return m.selectionSet(operation.SelectionSet)
+}
+
+// selectionSet returns the cost of the fields selected by set, directly or through fragments.
+func (m measurer) selectionSet(set *ast.SelectionSet) cost {
+	var total cost
+	if set == nil {
+		return total
+	}
+	for _, selection := range set.Selections {
+		var c cost
+		switch selection := selection.(type) {
+		case *ast.Field:
+			c = m.field(selection)
+		case *ast.InlineFragment:
+			c = m.selectionSet(selection.SelectionSet)
+		case *ast.FragmentSpread:
+			if fragment, ok := m.fragments[selection.Name.Value]; ok {
+				c = m.selectionSet(fragment.SelectionSet)
+			}
+		}
+		total.depth = max(total.depth, c.depth)
+		total.complexity += c.complexity
+	}
+	return total
+}
+
+// field returns the cost of resolving field and its selections. The selections of a list field are counted
+// once for each item it may return.
+func (m measurer) field(field *ast.Field) cost {
+	if strings.HasPrefix(field.Name.Value, "__") {
+		return cost{}
+	}
+	children := m.selectionSet(field.SelectionSet)
+	items := 1
+	if listFields[field.Name.Value] {
+		items = m.limit(field)
+	}
+	return cost{depth: children.depth + 1, complexity: 1 + items*children.complexity}
+}
+
+// limit returns the number of items a list field may return: its limit argument, bounded by MaxLimit, or
+// DefaultLimit without one.
+func (m measurer) limit(field *ast.Field) int {
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != argLimit {
+			continue
+		}
+		var value any = argument.Value.GetValue()
+		if variable, ok := argument.Value.(*ast.Variable); ok {
+			value = m.variables[variable.Name.Value]
+		}
+		var limit int
+		switch value := value.(type) {
+		case string: // Literals keep their source text
+			limit, _ = strconv.Atoi(value)
+		case float64: // Variables decoded from JSON
+			limit = int(value)
+		case int:
+			limit = value
+		}
+		if limit > 0 {
+			return min(limit, MaxLimit)
+		}
+	}
+	return DefaultLimit
+}
@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/internal/repository"
+	"bloggingplatformapi/internal/services"
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Kinds of groups of posts the postsLoader loads.
+const (
+	groupCategory = "category"
+	groupTag      = "tag"
+)
+
+// postsKey names a group of posts: the posts in a category or with a tag, ignoring case.
+type postsKey struct {
+	kind string // groupCategory or groupTag
+	name string // Category or tag name, in lower case
+}
+
+// newPostsKey returns the key of the posts of the given kind and name.
+func newPostsKey(kind, name string) postsKey {
+	return postsKey{kind: kind, name: strings.ToLower(name)}
+}
+
+// postsLoader batches the loading of the posts of categories and tags within one request, so resolving the
+// posts of many categories or tags costs one service call rather than one per category or tag.
+//
+// Resolvers register keys with load and get back a thunk; the executor calls thunks only after resolving every
+// field at the same level, so the first thunk called loads the keys of all of them at once, each up to the most
+// posts asked of it. Loaded groups are kept for the rest of the request and only reloaded if more posts are
+// asked of them later.
+type postsLoader struct {
+	service services.BlogService
+	pending map[postsKey]int         // Keys registered but not yet loaded, with the most posts asked of each
+	loaded  map[postsKey]loadedPosts // Posts of each loaded key
+}
+
+// loadedPosts are the newest posts of a key.
+type loadedPosts struct {
+	posts []*models.Blog // Posts, newest first
+	limit int            // Most posts that were loaded; fewer mean there are no others
+}
+
+// newPostsLoader creates a loader for one request.
+func newPostsLoader(service services.BlogService) *postsLoader {
+	return &postsLoader{service: service, pending: make(map[postsKey]int), loaded: make(map[postsKey]loadedPosts)}
+}
+
+// load registers key and returns a thunk resolving to at least its limit newest posts, or all if it has fewer.
+func (l *postsLoader) load(ctx context.Context, key postsKey, limit int) func() (any, error) {
+	if !l.has(key, limit) {
+		l.pending[key] = max(l.pending[key], limit)
+	}
+	return func() (any, error) {
+		if !l.has(key, limit) {
+			if err := l.flush(ctx); err != nil {
+				return nil, err
+			}
+		}
+		return l.loaded[key].posts, nil
+	}
+}
+
+// has reports whether the limit newest posts of key are loaded.
+func (l *postsLoader) has(key postsKey, limit int) bool {
+	loaded, ok := l.loaded[key]
+	return ok && (loaded.limit >= limit || len(loaded.posts) < loaded.limit)
+}
+
+// flush loads every pending key with a single service call listing the newest posts of each, then groups them
+// by key. A post of a key may have been loaded for another key, but every post loaded for a key is among the
+// posts matching it, so the first posts matching the key, up to its limit, are its newest.
+func (l *postsLoader) flush(ctx context.Context) error {
+	keys := slices.SortedFunc(maps.Keys(l.pending), func(a, b postsKey) int {
+		return cmp.Or(strings.Compare(a.kind, b.kind), strings.Compare(a.name, b.name))
+	})
+	if len(keys) == 0 {
+		return nil
+	}
+	limits := l.pending
+	l.pending = make(map[postsKey]int)
+
+	groups := make([]repository.ListOptions, len(keys))
+	for i, key := range keys {
+		groups[i] = repository.ListOptions{Category: key.name, Limit: limits[key]}
+		if key.kind == groupTag {
+			groups[i] = repository.ListOptions{Tag: key.name, Limit: limits[key]}
+		}
+	}
+	blogs, err := l.service.ListBlogGroups(ctx, groups)
+	if err != nil {
+		return err
+	}
+
+	batch := make(map[postsKey][]*models.Blog, len(keys))
+	for _, key := range keys {
+		batch[key] = []*models.Blog{}
+	}
+	add := func(key postsKey, blog *models.Blog) {
+		// A blog tagged twice in different case is listed once
+		if posts, ok := batch[key]; ok && len(posts) < limits[key] && (len(posts) == 0 || posts[len(posts)-1] != blog) {
+			batch[key] = append(posts, blog)
+		}
+	}
+	for _, blog := range blogs {
+		add(newPostsKey(groupCategory, blog.Category), blog)
+		for _, tag := range blog.Tags {
+			add(newPostsKey(groupTag, tag), blog)
+		}
+	}
+	for key, posts := range batch {
+		l.loaded[key] = loadedPosts{posts: posts, limit: limits[key]}
+	}
+	return nil
+}
@@ -0,0 +1,507 @@
+package graph
+
+import (
+	"bloggingplatformapi/internal/logging"
+	"bloggingplatformapi/internal/markdown"
+	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/internal/repository"
+	"bloggingplatformapi/internal/utils"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Paging of list fields taking a limit argument.
+const (
+	DefaultLimit = 20  // Items returned without a limit argument
+	MaxLimit     = 100 // Largest limit accepted
+)
+
+// Argument names shared by several fields.
+const (
+	argID     = "id"
+	argInput  = "input"
+	argLimit  = "limit"
+	argOffset = "offset"
+	argName   = "name"
+)
+
+// listFields names the fields returning lists, whose selections the complexity limit counts once per item.
+var listFields = map[string]bool{"blogs": true, "posts": true, "categories": true, "tags": true}
+
+// Error codes reported in the extensions of GraphQL errors.
+const (
+	codeBadUserInput = "BAD_USER_INPUT"
+	codeNotFound     = "NOT_FOUND"
+	codeTooCostly    = "QUERY_TOO_COSTLY"
+	codeInternal     = "INTERNAL_SERVER_ERROR"
+)
+
+// codedError is an error reported with a machine-readable code in its GraphQL extensions.
+type codedError struct {
+	message string // Message shown to the client
+	code    string // One of the code constants
+}
+
+// Error returns the message shown to the client.
+func (e *codedError) Error() string {
+	return e.message
+}
+
+// Extensions returns the code of the error, for clients to branch on.
+func (e *codedError) Extensions() map[string]any {
+	return map[string]any{"code": e.code}
+}
+
+// badInput returns an error reporting invalid arguments.
+func badInput(format string, args ...any) error {
+	return &codedError{message: fmt.Sprintf(format, args...), code: codeBadUserInput}
+}
+
+// tooCostly returns an error reporting a query beyond the server's depth or complexity limit.
+func tooCostly(format string, args ...any) *codedError {
+	return &codedError{message: fmt.Sprintf(format, args...), code: codeTooCostly}
+}
+
+// serviceError logs err and returns the error shown to the client: not found for a missing blog, otherwise an
+// internal error that does not leak details.
+func serviceError(ctx context.Context, err error, message string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return &codedError{message: "Blog not found", code: codeNotFound}
+	}
+	logging.FromContext(ctx).Errorf("%s: %v", message, err)
+	return &codedError{message: message, code: codeInternal}
+}
+
+// category and tag are the values of the Category and Tag types. Posts have no author in this API, so
+// categories and tags are the only types related to them.
+type (
+	category struct{ name string }
+	tag      struct{ name string }
+)
+
+// blogPage is the value of the BlogPage type: a page of the blogs matching a query.
+type blogPage struct {
+	total  int
+	limit  int
+	offset int
+	items  []*models.Blog
+}
+
+// schema is the GraphQL schema over blogs. It is built once; resolvers find the request they serve, with its
+// service and loaders, in the root value.
+var schema = mustBuildSchema()
+
+// mustBuildSchema builds the schema, panicking if its definition is invalid.
+func mustBuildSchema() graphql.Schema {
+	built, err := buildSchema()
+	if err != nil {
+		panic(fmt.Sprintf("graph: invalid schema: %v", err))
+	}
+	return built
+}
+
+// buildSchema defines the query and mutation types over blogs, categories and tags.
+func buildSchema() (graphql.Schema, error) {
+	limitArg := &graphql.ArgumentConfig{
+		Type:        graphql.Int,
+		Description: fmt.Sprintf("Maximum number of items, at most %d (default %d)", MaxLimit, DefaultLimit),
+	}
+	offsetArg := &graphql.ArgumentConfig{Type: graphql.Int, Description: "Number of items to skip"}
+
+	var blogType *graphql.Object
+	categoryType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Category",
+		Description: "A category of blog posts; names are compared ignoring case",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"name": {Type: graphql.NewNonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(category).name, nil
+				}},
+				"posts": {
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(blogType))),
+					Description: "Posts in the category, newest first",
+					Args:        graphql.FieldConfigArgument{argLimit: limitArg},
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return posts(p, newPostsKey(groupCategory, p.Source.(category).name))
+					},
+				},
+			}
+		}),
+	})
+	tagType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Tag",
+		Description: "A tag of blog posts; names are compared ignoring case",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"name": {Type: graphql.NewNonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(tag).name, nil
+				}},
+				"posts": {
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(blogType))),
+					Description: "Posts with the tag, newest first",
+					Args:        graphql.FieldConfigArgument{argLimit: limitArg},
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return posts(p, newPostsKey(groupTag, p.Source.(tag).name))
+					},
+				},
+			}
+		}),
+	})
+
+	blogType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Blog",
+		Description: "A blog post",
+		Fields: graphql.Fields{
+			"id":          blogField(graphql.Int, func(b *models.Blog) any { return b.ID }),
+			"title":       blogField(graphql.String, func(b *models.Blog) any { return b.Title }),
+			"content":     blogField(graphql.String, func(b *models.Blog) any { return b.Content }),
+			"contentHtml": blogField(graphql.String, func(b *models.Blog) any { return b.ContentHTML }),
+			"createdAt":   blogField(graphql.DateTime, func(b *models.Blog) any { return b.CreatedAt }),
+			"updatedAt":   blogField(graphql.DateTime, func(b *models.Blog) any { return b.UpdatedAt }),
+			"excerpt": {
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Start of the content as plain text, cut at a word boundary",
+				Args: graphql.FieldConfigArgument{
+					"length": {Type: graphql.Int, Description: "Number of characters (default EXCERPT_LENGTH)"},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					length, _ := p.Args["length"].(int)
+					if length <= 0 {
+						length = requestOf(p).excerptLength
+					}
+					return markdown.Excerpt(p.Source.(*models.Blog).Content, length), nil
+				},
+			},
+			"category": {
+				Type: graphql.NewNonNull(categoryType),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return category{p.Source.(*models.Blog).Category}, nil
+				},
+			},
+			"tags": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tagType))),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					names := p.Source.(*models.Blog).Tags
+					tags := make([]tag, len(names))
+					for i, name := range names {
+						tags[i] = tag{name}
+					}
+					return tags, nil
+				},
+			},
+		},
+	})
+
+	blogPageType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "BlogPage",
+		Description: "A page of the blog posts matching a query",
+		Fields: graphql.Fields{
+			"total":  pageField(graphql.Int, func(p blogPage) any { return p.total }),
+			"limit":  pageField(graphql.Int, func(p blogPage) any { return p.limit }),
+			"offset": pageField(graphql.Int, func(p blogPage) any { return p.offset }),
+			"items": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(blogType))),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(blogPage).items, nil
+				},
+			},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"blog": {
+				Type:    blogType,
+				Args:    graphql.FieldConfigArgument{argID: {Type: graphql.NewNonNull(graphql.Int)}},
+				Resolve: resolveBlog,
+			},
+			"blogs": {
+				Type:        graphql.NewNonNull(blogPageType),
+				Description: "Blog posts in ID order, optionally filtered",
+				Args: graphql.FieldConfigArgument{
+					"term":     {Type: graphql.String, Description: "Search term matched against the title, content and category"},
+					"category": {Type: graphql.String, Description: "Only posts in this category, ignoring case"},
+					"tag":      {Type: graphql.String, Description: "Only posts with this tag, ignoring case"},
+					argLimit:   limitArg,
+					argOffset:  offsetArg,
+				},
+				Resolve: resolveBlogs,
+			},
+			"category": {
+				Type:        categoryType,
+				Description: "The category with the given name, or null if no post is in it",
+				Args:        graphql.FieldConfigArgument{argName: {Type: graphql.NewNonNull(graphql.String)}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return named(p, groupCategory, func(name string) any { return category{name} })
+				},
+			},
+			"tag": {
+				Type:        tagType,
+				Description: "The tag with the given name, or null if no post has it",
+				Args:        graphql.FieldConfigArgument{argName: {Type: graphql.NewNonNull(graphql.String)}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return named(p, groupTag, func(name string) any { return tag{name} })
+				},
+			},
+			"categories": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryType))),
+				Description: "A page of the categories with posts, in alphabetical order",
+				Args:        graphql.FieldConfigArgument{argLimit: limitArg, argOffset: offsetArg},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return distinct(p, repository.FieldCategory, func(b *models.Blog) []string { return []string{b.Category} },
+						func(name string) any { return category{name} })
+				},
+			},
+			"tags": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tagType))),
+				Description: "A page of the tags with posts, in alphabetical order",
+				Args:        graphql.FieldConfigArgument{argLimit: limitArg, argOffset: offsetArg},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return distinct(p, repository.FieldTags, func(b *models.Blog) []string { return b.Tags },
+						func(name string) any { return tag{name} })
+				},
+			},
+		},
+	})
+
+	blogInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "BlogInput",
+		Description: "The writable fields of a blog post",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":    {Type: graphql.NewNonNull(graphql.String)},
+			"content":  {Type: graphql.NewNonNull(graphql.String), Description: "Markdown content"},
+			"category": {Type: graphql.NewNonNull(graphql.String)},
+			"tags":     {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createBlog": {
+				Type:    graphql.NewNonNull(blogType),
+				Args:    graphql.FieldConfigArgument{argInput: {Type: graphql.NewNonNull(blogInput)}},
+				Resolve: resolveCreateBlog,
+			},
+			"updateBlog": {
+				Type: graphql.NewNonNull(blogType),
+				Args: graphql.FieldConfigArgument{
+					argID:    {Type: graphql.NewNonNull(graphql.Int)},
+					argInput: {Type: graphql.NewNonNull(blogInput)},
+				},
+				Resolve: resolveUpdateBlog,
+			},
+			"deleteBlog": {
+				Type:    graphql.NewNonNull(graphql.Boolean),
+				Args:    graphql.FieldConfigArgument{argID: {Type: graphql.NewNonNull(graphql.Int)}},
+				Resolve: resolveDeleteBlog,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+// blogField defines a non-null field of the Blog type read from the blog with get.
+func blogField(fieldType graphql.Output, get func(*models.Blog) any) *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.NewNonNull(fieldType),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return get(p.Source.(*models.Blog)), nil
+		},
+	}
+}
+
+// pageField defines a non-null field of the BlogPage type read from the page with get.
+func pageField(fieldType graphql.Output, get func(blogPage) any) *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.NewNonNull(fieldType),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return get(p.Source.(blogPage)), nil
+		},
+	}
+}
+
+// limitOf returns the limit argument of a list field, or DefaultLimit without one.
+func limitOf(p graphql.ResolveParams) (int, error) {
+	limit, ok := p.Args[argLimit].(int)
+	if !ok {
+		return DefaultLimit, nil
+	}
+	if limit < 1 || limit > MaxLimit {
+		return 0, badInput("limit must be between 1 and %d", MaxLimit)
+	}
+	return limit, nil
+}
+
+// offsetOf returns the offset argument of a list field, or 0 without one.
+func offsetOf(p graphql.ResolveParams) (int, error) {
+	offset, _ := p.Args[argOffset].(int)
+	if offset < 0 {
+		return 0, badInput("offset must not be negative")
+	}
+	return offset, nil
+}
+
+// resolveBlog fetches the blog with the given ID, or null if there is none.
+func resolveBlog(p graphql.ResolveParams) (any, error) {
+	blog, err := requestOf(p).service.GetBlogByID(p.Context, p.Args[argID].(int))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, serviceError(p.Context, err, "Failed to retrieve blog")
+	}
+	return blog, nil
+}
+
+// resolveBlogs fetches a page of the blogs matching the search term, category and tag.
+func resolveBlogs(p graphql.ResolveParams) (any, error) {
+	limit, err := limitOf(p)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := offsetOf(p)
+	if err != nil {
+		return nil, err
+	}
+	term, _ := p.Args["term"].(string)
+	categoryName, _ := p.Args["category"].(string)
+	tagName, _ := p.Args["tag"].(string)
+
+	opts := repository.SearchOptions{Term: term, Category: categoryName, Tag: tagName, Limit: limit, Offset: offset}
+	items, total, err := requestOf(p).service.SearchBlogs(p.Context, opts)
+	if err != nil {
+		return nil, serviceError(p.Context, err, "Failed to retrieve blogs")
+	}
+	return blogPage{total: total, limit: limit, offset: offset, items: items}, nil
+}
+
+// posts resolves the posts of a category or tag through the request's loader, so the posts of every category
+// and tag at the same level of the query are loaded together.
+func posts(p graphql.ResolveParams, key postsKey) (any, error) {
+	limit, err := limitOf(p)
+	if err != nil {
+		return nil, err
+	}
+	load := requestOf(p).posts.load(p.Context, key, limit)
+	return func() (any, error) {
+		blogs, err := load()
+		if err != nil {
+			return nil, serviceError(p.Context, err, "Failed to retrieve blogs")
+		}
+		list := blogs.([]*models.Blog)
+		return list[:min(limit, len(list))], nil
+	}, nil
+}
+
+// named resolves the category or tag named in the name argument, or null if no post is in it.
+func named(p graphql.ResolveParams, kind string, value func(name string) any) (any, error) {
+	name := p.Args[argName].(string)
+	load := requestOf(p).posts.load(p.Context, newPostsKey(kind, name), 1)
+	return func() (any, error) {
+		blogs, err := load()
+		if err != nil {
+			return nil, serviceError(p.Context, err, "Failed to retrieve blogs")
+		}
+		if len(blogs.([]*models.Blog)) == 0 {
+			return nil, nil
+		}
+		return value(name), nil
+	}, nil
+}
+
+// distinct lists the page given by the limit and offset arguments of the distinct names, ignoring case, that
+// names finds in the blogs, reading only field. Each is spelled as in the first blog it was found in.
+func distinct(p graphql.ResolveParams, field string, names func(*models.Blog) []string, value func(name string) any) (any, error) {
+	limit, err := limitOf(p)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := offsetOf(p)
+	if err != nil {
+		return nil, err
+	}
+	blogs, err := requestOf(p).service.GetAllBlogs(p.Context, "", field)
+	if err != nil {
+		return nil, serviceError(p.Context, err, "Failed to retrieve blogs")
+	}
+	seen := make(map[string]bool)
+	var found []string
+	for _, blog := range blogs {
+		for _, name := range names(blog) {
+			if key := strings.ToLower(name); !seen[key] {
+				seen[key] = true
+				found = append(found, name)
+			}
+		}
+	}
+	slices.SortFunc(found, func(a, b string) int { return strings.Compare(strings.ToLower(a), strings.ToLower(b)) })
+	found = found[min(offset, len(found)):]
+	found = found[:min(limit, len(found))]
+
+	values := make([]any, len(found))
+	for i, name := range found {
+		values[i] = value(name)
+	}
+	return values, nil
+}
+
+// blogFromInput returns the blog described by the input argument, validated as the REST API validates it.
+func blogFromInput(p graphql.ResolveParams) (*models.Blog, error) {
+	input := p.Args[argInput].(map[string]any)
+	blog := &models.Blog{}
+	blog.Title, _ = input["title"].(string)
+	blog.Content, _ = input["content"].(string)
+	blog.Category, _ = input["category"].(string)
+	tags, _ := input["tags"].([]any)
+	for _, t := range tags {
+		if name, ok := t.(string); ok {
+			blog.Tags = append(blog.Tags, name)
+		}
+	}
+	if err := utils.ValidateBlog(blog); err != nil {
+		return nil, badInput("%v", err)
+	}
+	return blog, nil
+}
+
+// resolveCreateBlog creates a blog from the input argument.
+func resolveCreateBlog(p graphql.ResolveParams) (any, error) {
+	blog, err := blogFromInput(p)
+	if err != nil {
+		return nil, err
+	}
+	if err := requestOf(p).service.CreateBlog(p.Context, blog); err != nil {
+		return nil, serviceError(p.Context, err, "Failed to create blog")
+	}
+	return blog, nil
+}
+
+// resolveUpdateBlog replaces the blog with the given ID by the input argument.
+func resolveUpdateBlog(p graphql.ResolveParams) (any, error) {
+	blog, err := blogFromInput(p)
+	if err != nil {
+		return nil, err
+	}
+	blog.ID = p.Args[argID].(int)
+	if err := requestOf(p).service.UpdateBlog(p.Context, blog); err != nil {
+		return nil, serviceError(p.Context, err, "Failed to update blog")
+	}
+	return blog, nil
+}
+
+// resolveDeleteBlog deletes the blog with the given ID and returns true.
+func resolveDeleteBlog(p graphql.ResolveParams) (any, error) {
+	if err := requestOf(p).service.DeleteBlog(p.Context, p.Args[argID].(int)); err != nil {
+		return nil, serviceError(p.Context, err, "Failed to delete blog")
+	}
+	return true, nil
+}
@@ -0,0 +1,133 @@
+// Package graph serves a GraphQL schema over the blog service: queries with filtering and paging, mutations
+// validated like the REST API, batched loading of related posts, and limits on query depth and complexity.
+package graph
+
+import (
+	"bloggingplatformapi/internal/markdown"
+	"bloggingplatformapi/internal/services"
+	"context"
+	"errors"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Default limits on the cost of a query.
+const (
+	DefaultMaxDepth      = 10   // Deepest nesting of fields
+	DefaultMaxComplexity = 1000 // Most fields resolved, counting list fields once per item
+)
+
+// ErrMutationNotAllowed is returned for a mutation in a request that may only read, such as a GET request.
+var ErrMutationNotAllowed = errors.New("mutations are only accepted in POST requests")
+
+// Request is a GraphQL request as clients send it.
+type Request struct {
+	Query         string         `json:"query" form:"query"`                 // GraphQL document
+	OperationName string         `json:"operationName" form:"operationName"` // Operation to execute, if the document has several
+	Variables     map[string]any `json:"variables" form:"-"`                 // Values of the operation's variables
+}
+
+// Server executes GraphQL requests against a blog service.
+type Server struct {
+	service       services.BlogService
+	excerptLength int // Default length of Blog.excerpt
+	maxDepth      int // Deepest nesting of fields accepted
+	maxComplexity int // Highest complexity accepted
+}
+
+// NewServer creates a server executing requests against service. Non-positive limits and excerpt lengths fall
+// back to DefaultMaxDepth, DefaultMaxComplexity and markdown.DefaultExcerptLength.
+func NewServer(service services.BlogService, excerptLength, maxDepth, maxComplexity int) *Server {
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	if maxComplexity <= 0 {
+		maxComplexity = DefaultMaxComplexity
+	}
+	if excerptLength <= 0 {
+		excerptLength = markdown.DefaultExcerptLength
+	}
+	return &Server{service: service, excerptLength: excerptLength, maxDepth: maxDepth, maxComplexity: maxComplexity}
+}
+
+// request is the state of one request, passed to resolvers as the root value.
+type request struct {
+	service       services.BlogService
+	excerptLength int
+	posts         *postsLoader // Posts of categories and tags, loaded in batches
+}
+
+// requestOf returns the request a resolver serves.
+func requestOf(p graphql.ResolveParams) *request {
+	return p.Info.RootValue.(*request)
+}
+
+// Execute parses, validates, measures and executes req. Problems with the document are reported as errors in
+// the result; the only error returned is ErrMutationNotAllowed, for a mutation when readOnly is set.
+func (s *Server) Execute(ctx context.Context, req Request, readOnly bool) (*graphql.Result, error) {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, nil
+	}
+	if validation := graphql.ValidateDocument(&schema, doc, nil); !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}, nil
+	}
+
+	// Without a single matching operation the executor reports the problem
+	if operation := findOperation(doc, req.OperationName); operation != nil {
+		if readOnly && operation.Operation == ast.OperationTypeMutation {
+			return nil, ErrMutationNotAllowed
+		}
+		if err := s.checkCost(measure(doc, operation, req.Variables)); err != nil {
+			formatted := gqlerrors.FormatError(err)
+			formatted.Extensions = err.Extensions()
+			return &graphql.Result{Errors: []gqlerrors.FormattedError{formatted}}, nil
+		}
+	}
+
+	root := &request{service: s.service, excerptLength: s.excerptLength, posts: newPostsLoader(s.service)}
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        schema,
+		Root:          root,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	}), nil
+}
+
+// checkCost returns an error if c exceeds the server's limits.
+func (s *Server) checkCost(c cost) *codedError {
+	if c.depth > s.maxDepth {
+		return tooCostly("query depth %d exceeds the limit of %d", c.depth, s.maxDepth)
+	}
+	if c.complexity > s.maxComplexity {
+		return tooCostly("query complexity %d exceeds the limit of %d", c.complexity, s.maxComplexity)
+	}
+	return nil
+}
+
+// findOperation returns the operation of doc named name, or its only operation if name is empty.
+// It returns nil if there is no such operation.
+func findOperation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = operation
+		} else if operation.Name != nil && operation.Name.Value == name {
+			return operation
+		}
+	}
+	return found
+}
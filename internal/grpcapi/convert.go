@@ -0,0 +1,61 @@
+package grpcapi
+
+import (
+	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/pkg/blogpb"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// batchOps maps the operation kinds of the proto to those of the model.
+var batchOps = map[blogpb.BatchOperation_Op]string{
+	blogpb.BatchOperation_OP_CREATE: models.BatchCreate,
+	blogpb.BatchOperation_OP_UPDATE: models.BatchUpdate,
+	blogpb.BatchOperation_OP_DELETE: models.BatchDelete,
+}
+
+// toProto converts a blog to its message. Unset times, e.g. of projected blogs, stay unset.
+func toProto(blog *models.Blog) *blogpb.Blog {
+	if blog == nil {
+		return nil
+	}
+	return &blogpb.Blog{
+		Id:          int64(blog.ID),
+		Title:       blog.Title,
+		Content:     blog.Content,
+		ContentHtml: blog.ContentHTML,
+		Category:    blog.Category,
+		Tags:        blog.Tags,
+		CreatedAt:   timestamp(blog.CreatedAt),
+		UpdatedAt:   timestamp(blog.UpdatedAt),
+	}
+}
+
+// fromProto converts a blog message to the model, ignoring the fields the server sets.
+func fromProto(blog *blogpb.Blog) *models.Blog {
+	if blog == nil {
+		return nil
+	}
+	return &models.Blog{
+		ID:       int(blog.GetId()),
+		Title:    blog.GetTitle(),
+		Content:  blog.GetContent(),
+		Category: blog.GetCategory(),
+		Tags:     blog.GetTags(),
+	}
+}
+
+// fromProtoOperation converts a batch operation message to the model. Unknown kinds convert to an empty kind,
+// which validation rejects.
+func fromProtoOperation(op *blogpb.BatchOperation) models.BatchOperation {
+	return models.BatchOperation{Op: batchOps[op.GetOp()], ID: int(op.GetId()), Blog: fromProto(op.GetBlog())}
+}
+
+// timestamp converts t to a message, or nil if t is zero.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
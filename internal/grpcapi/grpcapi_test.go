@@ -0,0 +1,191 @@
+package grpcapi
+
+import (
+	"bloggingplatformapi/internal/repository"
+	"bloggingplatformapi/internal/services"
+	"bloggingplatformapi/pkg/blogpb"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// setupClient serves the API over an in-memory listener with opts and returns a client connected to it.
+func setupClient(t *testing.T, opts Options) blogpb.BlogServiceClient {
+	t.Helper()
+	repo := repository.NewMemoryBlogRepository()
+	server, err := NewServer(services.NewBlogService(repo, repo.UnitOfWork()), opts)
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return blogpb.NewBlogServiceClient(conn)
+}
+
+// receive reads a stream of blogs to its end and returns their titles.
+func receive(t *testing.T, stream grpc.ServerStreamingClient[blogpb.Blog]) []string {
+	t.Helper()
+	var titles []string
+	for {
+		blog, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return titles
+		}
+		require.NoError(t, err)
+		titles = append(titles, blog.GetTitle())
+	}
+}
+
+func TestBlogServer_CreatesReadsUpdatesAndDeletes(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := setupClient(t, Options{})
+
+	created, err := client.CreateBlog(ctx, &blogpb.CreateBlogRequest{Blog: &blogpb.Blog{
+		Title: "First", Content: "*Hello*", Category: "Tech", Tags: []string{"Go"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created.GetId())
+	assert.Equal(t, "<p><em>Hello</em></p>\n", created.GetContentHtml())
+	assert.NotNil(t, created.GetCreatedAt())
+
+	_, err = client.CreateBlog(ctx, &blogpb.CreateBlogRequest{Blog: &blogpb.Blog{Title: "Untagged", Content: "Hi", Category: "Tech"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "at least one tag is required", status.Convert(err).Message())
+
+	updated, err := client.UpdateBlog(ctx, &blogpb.UpdateBlogRequest{Blog: &blogpb.Blog{
+		Id: 1, Title: "Renamed", Content: "Hello", Category: "Tech", Tags: []string{"Go"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", updated.GetTitle())
+
+	got, err := client.GetBlog(ctx, &blogpb.GetBlogRequest{Id: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", got.GetTitle())
+
+	_, err = client.DeleteBlog(ctx, &blogpb.DeleteBlogRequest{Id: 1})
+	require.NoError(t, err)
+	_, err = client.GetBlog(ctx, &blogpb.GetBlogRequest{Id: 1})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestBlogServer_StreamsBlogs(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := setupClient(t, Options{})
+	for _, title := range []string{"Go tips", "Rust tips", "Go news"} {
+		_, err := client.CreateBlog(ctx, &blogpb.CreateBlogRequest{Blog: &blogpb.Blog{
+			Title: title, Content: "Content", Category: "Tech", Tags: []string{title[:2]},
+		}})
+		require.NoError(t, err)
+	}
+
+	search, err := client.SearchBlogs(ctx, &blogpb.SearchBlogsRequest{Term: "Go", Fields: []string{"title"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Go tips", "Go news"}, receive(t, search))
+
+	list, err := client.ListBlogs(ctx, &blogpb.ListBlogsRequest{Tag: "ru"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Rust tips"}, receive(t, list))
+
+	export, err := client.ExportBlogs(ctx, &blogpb.ExportBlogsRequest{AfterId: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Rust tips", "Go news"}, receive(t, export))
+
+	search, err = client.SearchBlogs(ctx, &blogpb.SearchBlogsRequest{Fields: []string{"author"}})
+	require.NoError(t, err)
+	_, err = search.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestBlogServer_AppliesBatches(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := setupClient(t, Options{})
+	blog := &blogpb.Blog{Title: "New", Content: "Content", Category: "Tech", Tags: []string{"Go"}}
+
+	resp, err := client.BatchBlogs(ctx, &blogpb.BatchBlogsRequest{Atomic: true, Operations: []*blogpb.BatchOperation{
+		{Op: blogpb.BatchOperation_OP_CREATE, Blog: blog},
+		{Op: blogpb.BatchOperation_OP_DELETE},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, int32(codes.Aborted), resp.GetResults()[0].GetCode())
+	assert.Equal(t, int32(codes.InvalidArgument), resp.GetResults()[1].GetCode())
+
+	resp, err = client.BatchBlogs(ctx, &blogpb.BatchBlogsRequest{Operations: []*blogpb.BatchOperation{
+		{Op: blogpb.BatchOperation_OP_CREATE, Blog: blog},
+		{Op: blogpb.BatchOperation_OP_DELETE, Id: 7},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, int32(codes.OK), resp.GetResults()[0].GetCode())
+	assert.Equal(t, int64(1), resp.GetResults()[0].GetId())
+	assert.Equal(t, int32(codes.NotFound), resp.GetResults()[1].GetCode())
+
+	stats, err := client.GetBlogStats(ctx, &blogpb.GetBlogStatsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.GetCount())
+}
+
+func TestNewServer_AuthenticatesLogsAndMeasuresCalls(t *testing.T) {
+	t.Parallel()
+	logger, hook := test.NewNullLogger()
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+	client := setupClient(t, Options{Logger: logger, Tokens: map[string]string{"s3cret": "billing"}, Meter: meter})
+
+	_, err := client.GetBlogStats(context.Background(), &blogpb.GetBlogStatsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer s3cret", "x-request-id", "req-1")
+	var header metadata.MD
+	_, err = client.GetBlogStats(ctx, &blogpb.GetBlogStatsRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"req-1"}, header.Get("x-request-id"))
+
+	entries := hook.AllEntries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Unauthenticated", entries[0].Data["code"])
+	assert.Equal(t, logrus.Fields{
+		"requestId": "req-1", "method": "/blog.v1.BlogService/GetBlogStats", "code": "OK",
+		"principal": "billing", "latency": entries[1].Data["latency"],
+	}, entries[1].Data)
+
+	var metrics metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &metrics))
+	require.Len(t, metrics.ScopeMetrics, 1)
+	histogram := metrics.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, "rpc.server.duration", histogram.Name)
+	assert.Len(t, histogram.Data.(metricdata.Histogram[float64]).DataPoints, 2) // One per status code
+}
+
+func TestParseTokens(t *testing.T) {
+	t.Parallel()
+
+	tokens, err := ParseTokens([]string{"billing:abc", "search:d:ef"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"abc": "billing", "d:ef": "search"}, tokens)
+
+	_, err = ParseTokens([]string{"billing:abc", "tokenonly"})
+	assert.EqualError(t, err, "token 2 must have the form principal:token")
+}
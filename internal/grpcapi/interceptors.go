@@ -0,0 +1,188 @@
+package grpcapi
+
+import (
+	"bloggingplatformapi/internal/logging"
+	"bloggingplatformapi/internal/utils"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys read and written by the interceptors.
+const (
+	authorizationKey = "authorization"
+	requestIDKey     = "x-request-id"
+)
+
+// middleware wraps the handling of a unary or streaming call to method. It may derive the context the call
+// runs in, and must call next to run it unless it rejects the call.
+type middleware func(ctx context.Context, method string, next func(context.Context) error) error
+
+// unaryInterceptor adapts m to unary calls.
+func unaryInterceptor(m middleware) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var resp any
+		err := m(ctx, info.FullMethod, func(ctx context.Context) (err error) {
+			resp, err = handler(ctx, req)
+			return err
+		})
+		return resp, err
+	}
+}
+
+// streamInterceptor adapts m to streaming calls.
+func streamInterceptor(m middleware) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return m(stream.Context(), info.FullMethod, func(ctx context.Context) error {
+			return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+		})
+	}
+}
+
+// contextStream is a server stream running in a derived context.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the derived context.
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// callKey is the context key of the callInfo of a call.
+type callKey struct{}
+
+// callInfo describes a call for the interceptors that run before it is authenticated.
+type callInfo struct {
+	principal string // Authenticated principal, empty for anonymous calls
+}
+
+// PrincipalFromContext returns the principal authenticated for the call running in ctx, or an empty string if
+// the call is anonymous.
+func PrincipalFromContext(ctx context.Context) string {
+	if info, ok := ctx.Value(callKey{}).(*callInfo); ok {
+		return info.principal
+	}
+	return ""
+}
+
+// withCallInfo returns ctx carrying the callInfo of its call, creating one if there is none yet.
+func withCallInfo(ctx context.Context) (context.Context, *callInfo) {
+	if info, ok := ctx.Value(callKey{}).(*callInfo); ok {
+		return ctx, info
+	}
+	info := &callInfo{}
+	return context.WithValue(ctx, callKey{}, info), info
+}
+
+// loggingMiddleware gives every call a request ID and a request-scoped logger, as utils.RequestID does for HTTP
+// requests, and logs the call once it completes. A valid x-request-id sent by the client is reused, and the ID
+// is returned in the x-request-id header.
+func loggingMiddleware(logger *logrus.Logger) middleware {
+	return func(ctx context.Context, method string, next func(context.Context) error) error {
+		start := time.Now()
+		requestID := firstValue(ctx, requestIDKey)
+		if !utils.ValidRequestID(requestID) {
+			requestID = utils.NewRequestID()
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID)) // Fails only once headers were sent
+
+		entry := logger.WithFields(logrus.Fields{"requestId": requestID, "method": method})
+		ctx, info := withCallInfo(ctx)
+		ctx = logging.NewContext(logging.WithRequestID(ctx, requestID), entry)
+
+		err := next(ctx)
+
+		fields := logrus.Fields{"code": status.Code(err).String(), "latency": time.Since(start).Milliseconds()}
+		if info.principal != "" {
+			fields["principal"] = info.principal
+		}
+		if err != nil {
+			fields["error"] = status.Convert(err).Message()
+		}
+		entry.WithFields(fields).Info("gRPC request processed")
+		return err
+	}
+}
+
+// metricsMiddleware records the duration of every call in the rpc.server.duration histogram of meter, with the
+// attributes of the OpenTelemetry RPC semantic conventions.
+func metricsMiddleware(meter metric.Meter) (middleware, error) {
+	duration, err := meter.Float64Histogram("rpc.server.duration",
+		metric.WithUnit("ms"), metric.WithDescription("Duration of inbound RPCs"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RPC duration histogram: %w", err)
+	}
+	return func(ctx context.Context, method string, next func(context.Context) error) error {
+		start := time.Now()
+		err := next(ctx)
+
+		service, name, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+		duration.Record(ctx, float64(time.Since(start))/float64(time.Millisecond), metric.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.service", service),
+			attribute.String("rpc.method", name),
+			attribute.Int("rpc.grpc.status_code", int(status.Code(err))),
+		))
+		return err
+	}, nil
+}
+
+// authMiddleware requires every call to carry one of tokens, mapped to the principals they authenticate, in an
+// "authorization: Bearer <token>" header. Without tokens every call is anonymous.
+func authMiddleware(tokens map[string]string) middleware {
+	return func(ctx context.Context, method string, next func(context.Context) error) error {
+		if len(tokens) == 0 {
+			return next(ctx)
+		}
+		token, ok := strings.CutPrefix(firstValue(ctx, authorizationKey), "Bearer ")
+		principal := ""
+		for candidate, name := range tokens {
+			// Compare every token in constant time, so timing does not reveal how much of one matched
+			if subtle.ConstantTimeCompare([]byte(token), []byte(candidate)) == 1 {
+				principal = name
+			}
+		}
+		if !ok || principal == "" {
+			return status.Error(codes.Unauthenticated, "a valid bearer token is required")
+		}
+
+		ctx, info := withCallInfo(ctx)
+		info.principal = principal
+		ctx = logging.NewContext(ctx, logging.FromContext(ctx).WithField("principal", principal))
+		return next(ctx)
+	}
+}
+
+// firstValue returns the first value of the incoming metadata key, or an empty string.
+func firstValue(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// ParseTokens parses GRPC_AUTH_TOKENS entries of the form principal:token into a map from token to principal.
+func ParseTokens(entries []string) (map[string]string, error) {
+	tokens := make(map[string]string, len(entries))
+	for i, entry := range entries {
+		principal, token, ok := strings.Cut(entry, ":")
+		if !ok || principal == "" || token == "" {
+			return nil, fmt.Errorf("token %d must have the form principal:token", i+1)
+		}
+		tokens[token] = principal
+	}
+	return tokens, nil
+}
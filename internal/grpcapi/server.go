@@ -0,0 +1,260 @@
+// Package grpcapi serves the blog API over gRPC, as defined in api/proto/blog/v1/blog.proto, for
+// service-to-service use. It calls the same BlogService as the REST API, so both share validation, storage and
+// cache.
+package grpcapi
+
+import (
+	"bloggingplatformapi/internal/logging"
+	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/internal/repository"
+	"bloggingplatformapi/internal/services"
+	"bloggingplatformapi/internal/tracing"
+	"bloggingplatformapi/internal/utils"
+	"bloggingplatformapi/pkg/blogpb"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// BlogServer implements blogpb.BlogServiceServer on top of a BlogService.
+type BlogServer struct {
+	blogpb.UnimplementedBlogServiceServer
+	Service services.BlogService
+}
+
+// NewBlogServer creates a new instance of BlogServer calling service.
+func NewBlogServer(service services.BlogService) *BlogServer {
+	return &BlogServer{Service: service}
+}
+
+// Options configure the gRPC server created by NewServer.
+type Options struct {
+	Logger *logrus.Logger    // Logger of the calls; nil for the standard logger
+	Tokens map[string]string // Bearer tokens accepted, mapped to the principals they authenticate; empty for none
+	Meter  metric.Meter      // Meter recording call durations; nil for the global meter provider's
+}
+
+// NewServer creates a gRPC server serving BlogServer over service. Every call is logged, measured and, if
+// opts.Tokens is not empty, authenticated, in that order.
+func NewServer(service services.BlogService, opts Options) (*grpc.Server, error) {
+	if opts.Logger == nil {
+		opts.Logger = logrus.StandardLogger()
+	}
+	if opts.Meter == nil {
+		opts.Meter = otel.Meter(tracing.InstrumentationName)
+	}
+	metrics, err := metricsMiddleware(opts.Meter)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := []middleware{loggingMiddleware(opts.Logger), metrics, authMiddleware(opts.Tokens)}
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+	for _, m := range chain {
+		unary = append(unary, unaryInterceptor(m))
+		stream = append(stream, streamInterceptor(m))
+	}
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...))
+	blogpb.RegisterBlogServiceServer(server, NewBlogServer(service))
+	return server, nil
+}
+
+// CreateBlog validates the blog as the REST API does and creates it.
+func (s *BlogServer) CreateBlog(ctx context.Context, req *blogpb.CreateBlogRequest) (*blogpb.Blog, error) {
+	blog, err := validBlog(req.GetBlog())
+	if err != nil {
+		return nil, err
+	}
+	if blog.ID != 0 {
+		return nil, status.Error(codes.InvalidArgument, "id must not be set when creating a blog")
+	}
+	if err := s.Service.CreateBlog(ctx, blog); err != nil {
+		return nil, serviceError(ctx, err, "Failed to create blog")
+	}
+	return toProto(blog), nil
+}
+
+// GetBlog fetches the blog with the requested ID.
+func (s *BlogServer) GetBlog(ctx context.Context, req *blogpb.GetBlogRequest) (*blogpb.Blog, error) {
+	id, err := validID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	blog, err := s.Service.GetBlogByID(ctx, id)
+	if err != nil {
+		return nil, serviceError(ctx, err, "Failed to retrieve blog")
+	}
+	return toProto(blog), nil
+}
+
+// SearchBlogs streams the blogs matching the search term, reading only the requested fields.
+func (s *BlogServer) SearchBlogs(req *blogpb.SearchBlogsRequest, stream blogpb.BlogService_SearchBlogsServer) error {
+	for _, field := range req.GetFields() {
+		if !repository.ValidField(field) {
+			return status.Errorf(codes.InvalidArgument, "unknown blog field %q", field)
+		}
+	}
+	blogs, err := s.Service.GetAllBlogs(stream.Context(), req.GetTerm(), req.GetFields()...)
+	if err != nil {
+		return serviceError(stream.Context(), err, "Failed to retrieve blogs")
+	}
+	return send(stream.Send, blogs)
+}
+
+// ListBlogs streams the newest blogs of the requested category and tag.
+func (s *BlogServer) ListBlogs(req *blogpb.ListBlogsRequest, stream blogpb.BlogService_ListBlogsServer) error {
+	if req.GetLimit() < 0 {
+		return status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+	opts := repository.ListOptions{Category: req.GetCategory(), Tag: req.GetTag(), Limit: int(req.GetLimit())}
+	blogs, err := s.Service.ListBlogs(stream.Context(), opts)
+	if err != nil {
+		return serviceError(stream.Context(), err, "Failed to retrieve blogs")
+	}
+	return send(stream.Send, blogs)
+}
+
+// UpdateBlog validates the blog as the REST API does and replaces the stored blog with the same ID.
+func (s *BlogServer) UpdateBlog(ctx context.Context, req *blogpb.UpdateBlogRequest) (*blogpb.Blog, error) {
+	if _, err := validID(req.GetBlog().GetId()); err != nil {
+		return nil, err
+	}
+	blog, err := validBlog(req.GetBlog())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Service.UpdateBlog(ctx, blog); err != nil {
+		return nil, serviceError(ctx, err, "Failed to update blog")
+	}
+	return toProto(blog), nil
+}
+
+// DeleteBlog deletes the blog with the requested ID.
+func (s *BlogServer) DeleteBlog(ctx context.Context, req *blogpb.DeleteBlogRequest) (*emptypb.Empty, error) {
+	id, err := validID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Service.DeleteBlog(ctx, id); err != nil {
+		return nil, serviceError(ctx, err, "Failed to delete blog")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// BatchBlogs validates and applies several operations, with the semantics of POST /blogs:batch. The call
+// succeeds whenever the batch was processed; the outcome of each operation is in its result. In an atomic batch
+// with a failing or invalid operation nothing is applied, and every other result is ABORTED.
+func (s *BlogServer) BatchBlogs(ctx context.Context, req *blogpb.BatchBlogsRequest) (*blogpb.BatchBlogsResponse, error) {
+	operations := req.GetOperations()
+	ops := make([]models.BatchOperation, len(operations))
+	for i, operation := range operations {
+		ops[i] = fromProtoOperation(operation)
+	}
+	outcomes, err := services.RunBatch(ctx, s.Service, ops, req.GetAtomic())
+	switch {
+	case errors.Is(err, services.ErrEmptyBatch), errors.Is(err, services.ErrBatchTooLarge):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil && !errors.Is(err, services.ErrInvalidBatch) && !errors.As(err, new(*services.BatchError)):
+		return nil, serviceError(ctx, err, "Failed to apply batch") // Failed batches report their outcomes below
+	}
+
+	results := make([]*blogpb.BatchResult, len(operations))
+	for i, outcome := range outcomes {
+		result := &blogpb.BatchResult{Index: int32(i), Id: operations[i].GetId()}
+		results[i] = result
+		switch {
+		case outcome.Err == nil:
+			result.Code = int32(codes.OK)
+			if outcome.Blog != nil {
+				result.Blog, result.Id = toProto(outcome.Blog), int64(outcome.Blog.ID)
+			}
+		case errors.As(outcome.Err, new(*services.InvalidOperationError)):
+			result.Code, result.Error = int32(codes.InvalidArgument), outcome.Err.Error()
+		case errors.Is(outcome.Err, services.ErrBatchAborted):
+			result.Code, result.Error = int32(codes.Aborted), outcome.Err.Error()
+		default:
+			st := status.Convert(serviceError(ctx, fmt.Errorf("batch operation %d: %w", result.Index, outcome.Err), "Failed to apply operation"))
+			result.Code, result.Error = int32(st.Code()), st.Message()
+		}
+	}
+	return &blogpb.BatchBlogsResponse{Results: results}, nil
+}
+
+// ExportBlogs streams every blog after the requested ID in ID order, reading them in chunks rather than all at once.
+func (s *BlogServer) ExportBlogs(req *blogpb.ExportBlogsRequest, stream blogpb.BlogService_ExportBlogsServer) error {
+	if req.GetAfterId() < 0 {
+		return status.Error(codes.InvalidArgument, "after_id must not be negative")
+	}
+	err := s.Service.ExportBlogs(stream.Context(), int(req.GetAfterId()), 0, func(blog *models.Blog) error {
+		return stream.Send(toProto(blog))
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err // The stream failed while sending
+		}
+		return serviceError(stream.Context(), err, "Failed to export blogs")
+	}
+	return nil
+}
+
+// GetBlogStats counts the blogs.
+func (s *BlogServer) GetBlogStats(ctx context.Context, _ *blogpb.GetBlogStatsRequest) (*blogpb.BlogStats, error) {
+	stats, err := s.Service.BlogStats(ctx)
+	if err != nil {
+		return nil, serviceError(ctx, err, "Failed to count blogs")
+	}
+	return &blogpb.BlogStats{Count: int64(stats.Count), MaxId: int64(stats.MaxID)}, nil
+}
+
+// send streams blogs with sendFn, stopping at the first error.
+func send(sendFn func(*blogpb.Blog) error, blogs []*models.Blog) error {
+	for _, blog := range blogs {
+		if err := sendFn(toProto(blog)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validID returns id as a blog ID, or an InvalidArgument error if it is not positive.
+func validID(id int64) (int, error) {
+	if id <= 0 {
+		return 0, status.Error(codes.InvalidArgument, "id must be a positive integer")
+	}
+	return int(id), nil
+}
+
+// validBlog converts blog to the model and validates it, returning an InvalidArgument error if it is invalid.
+func validBlog(blog *blogpb.Blog) (*models.Blog, error) {
+	if blog == nil {
+		return nil, status.Error(codes.InvalidArgument, "blog is required")
+	}
+	converted := fromProto(blog)
+	if err := utils.ValidateBlog(converted); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return converted, nil
+}
+
+// serviceError converts an error of the service to a status: NotFound for a missing blog, the status of a
+// canceled or expired context, and otherwise Internal with message, logging the cause.
+func serviceError(ctx context.Context, err error, message string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Error(codes.NotFound, "Blog not found")
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	logging.FromContext(ctx).Errorf("%s: %v", message, err)
+	return status.Error(codes.Internal, message)
+}
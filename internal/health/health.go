@@ -0,0 +1,150 @@
+package health
+
+import (
+	"bloggingplatformapi/internal/logging"
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status values reported by checks and the overall report.
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// CheckFunc verifies a single dependency and returns an error if it is unhealthy.
+type CheckFunc func(ctx context.Context) error
+
+// ErrorUnavailable is the Error of a failed check. The error itself is logged rather than reported, since
+// reports are public and errors may reveal hosts, users or schema details.
+const ErrorUnavailable = "unavailable"
+
+// CheckResult is the outcome of a single dependency check.
+type CheckResult struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latencyMs"`
+	Error     string `json:"error,omitempty"` // ErrorUnavailable if the check failed
+}
+
+// Report is the aggregated outcome of all registered checks.
+type Report struct {
+	Status       string                 `json:"status"`
+	ShuttingDown bool                   `json:"shuttingDown"`
+	Checks       map[string]CheckResult `json:"checks"`
+}
+
+// Healthy reports whether the service can accept traffic.
+func (r Report) Healthy() bool {
+	return r.Status == StatusUp
+}
+
+// check is a named CheckFunc registered with a Checker.
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker runs dependency checks for the readiness and health endpoints
+// and tracks whether the server has started shutting down.
+type Checker struct {
+	timeout      time.Duration // Upper bound for each individual check
+	checks       []check
+	shuttingDown atomic.Bool
+}
+
+// NewChecker creates a Checker that gives each check at most timeout to complete.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Register adds a named dependency check. Checks must be registered before the server starts.
+func (c *Checker) Register(name string, fn CheckFunc) {
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// SetShuttingDown marks the service as shutting down so readiness fails immediately,
+// letting load balancers drain traffic before connections are closed.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// ShuttingDown reports whether graceful shutdown has begun.
+func (c *Checker) ShuttingDown() bool {
+	return c.shuttingDown.Load()
+}
+
+// Run executes all checks concurrently and aggregates their results.
+func (c *Checker) Run(ctx context.Context) Report {
+	report := Report{
+		Status:       StatusUp,
+		ShuttingDown: c.ShuttingDown(),
+		Checks:       make(map[string]CheckResult, len(c.checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, chk := range c.checks {
+		wg.Add(1)
+		go func(chk check) {
+			defer wg.Done()
+			result := c.runCheck(ctx, chk)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[chk.name] = result
+		}(chk)
+	}
+	wg.Wait()
+
+	if report.ShuttingDown {
+		report.Status = StatusDown
+	}
+	for _, result := range report.Checks {
+		if result.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+// runCheck executes a single check with the configured timeout and measures its latency. A failure is logged.
+func (c *Checker) runCheck(ctx context.Context, chk check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := chk.fn(ctx)
+	result := CheckResult{Status: StatusUp, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = ErrorUnavailable
+		logging.FromContext(ctx).Warnf("Health check %q failed: %v", chk.name, err)
+	}
+	return result
+}
+
+// DatabaseCheck verifies that the database accepts connections.
+func DatabaseCheck(db *sql.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// MigrationCheck verifies that the database schema is at least at the expected migration version.
+func MigrationCheck(db *sql.DB, expected int) CheckFunc {
+	return func(ctx context.Context) error {
+		var current int
+		err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current)
+		if err != nil {
+			return fmt.Errorf("failed to read schema version: %w", err)
+		}
+		if current < expected {
+			return fmt.Errorf("schema version %d is behind expected version %d", current, expected)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,70 @@
+package health
+
+import (
+	"bloggingplatformapi/internal/logging"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker_Run(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+
+	// Checks run concurrently, so expectations may be met in any order.
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectPing()
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(version\), 0\) FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+
+	checker := NewChecker(time.Second)
+	checker.Register("database", DatabaseCheck(db))
+	checker.Register("migrations", MigrationCheck(db, 2))
+
+	logger, logs := logtest.NewNullLogger()
+	report := checker.Run(logging.NewContext(context.Background(), logrus.NewEntry(logger)))
+	assert.False(t, report.Healthy())
+	assert.Equal(t, StatusUp, report.Checks["database"].Status)
+	assert.Equal(t, StatusDown, report.Checks["migrations"].Status)
+	assert.Equal(t, ErrorUnavailable, report.Checks["migrations"].Error, "the cause is not reported")
+	require.Len(t, logs.Entries, 1)
+	assert.Contains(t, logs.LastEntry().Message, "behind expected version 2", "but logged")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChecker_ShuttingDown(t *testing.T) {
+	t.Parallel()
+
+	checker := NewChecker(time.Second)
+	checker.Register("ok", func(context.Context) error { return nil })
+	assert.True(t, checker.Run(context.Background()).Healthy())
+
+	checker.SetShuttingDown()
+	report := checker.Run(context.Background())
+	assert.False(t, report.Healthy())
+	assert.True(t, report.ShuttingDown)
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	t.Parallel()
+
+	checker := NewChecker(10 * time.Millisecond)
+	checker.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return errors.New("timed out")
+	})
+
+	report := checker.Run(context.Background())
+	assert.False(t, report.Healthy())
+	assert.Equal(t, StatusDown, report.Checks["slow"].Status)
+}
@@ -0,0 +1,67 @@
+package markdown
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// DefaultExcerptLength is the number of characters in an excerpt when none is configured.
+const DefaultExcerptLength = 200
+
+// Excerpt returns the start of the plain text of Markdown source, at most length characters long.
+// Markup, raw HTML, code blocks and footnotes are left out and whitespace is collapsed. Longer text is cut
+// at a word boundary where possible and ends with an ellipsis, which counts towards length.
+func Excerpt(source string, length int) string {
+	if length <= 0 {
+		return ""
+	}
+	return truncate(plainText([]byte(source)), length)
+}
+
+// plainText returns the text of the Markdown document in source, with whitespace collapsed.
+func plainText(source []byte) string {
+	var sb strings.Builder
+	doc := converter.Parser().Parse(text.NewReader(source))
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock, *ast.HTMLBlock, *ast.RawHTML, *extast.FootnoteList, *extast.FootnoteLink:
+			return ast.WalkSkipChildren, nil
+		case *ast.AutoLink:
+			sb.Write(n.Label(source))
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			sb.Write(n.Segment.Value(source))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				sb.WriteByte(' ')
+			}
+		case *ast.String:
+			sb.Write(n.Value)
+		default:
+			if n.Type() == ast.TypeBlock {
+				sb.WriteByte(' ') // Separate the text of consecutive blocks
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// truncate shortens s to at most length characters, preferring to cut at a space in its second half.
+func truncate(s string, length int) string {
+	if utf8.RuneCountInString(s) <= length {
+		return s
+	}
+	runes := []rune(s)
+	cut := string(runes[:length-1]) // Leave room for the ellipsis
+	if i := strings.LastIndexByte(cut, ' '); runes[length-1] != ' ' && i > len(cut)/2 {
+		cut = cut[:i] // Drop the partial word
+	}
+	return strings.TrimRight(cut, " ") + "…"
+}
@@ -0,0 +1,56 @@
+// Package markdown renders blog content to HTML that is safe to embed in a page.
+// Content is CommonMark with the GitHub Flavored Markdown extensions (tables, strikethrough, autolinks
+// and task lists) and footnotes.
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// converter turns Markdown into HTML. Raw HTML in the source is passed through and left to policy.
+// Table cells are aligned with the align attribute, since policy drops style attributes.
+var converter = goldmark.New(
+	goldmark.WithExtensions(
+		extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+		extension.Strikethrough,
+		extension.Linkify,
+		extension.TaskList,
+		extension.Footnote,
+	),
+	goldmark.WithRendererOptions(html.WithUnsafe()),
+)
+
+// policy is the allow-list applied to the converted HTML. It starts from bluemonday's policy for
+// user-generated content, which drops scripts, styles, event handlers and unsafe URLs, and adds the
+// attributes the Markdown extensions emit.
+var policy = newPolicy()
+
+// newPolicy builds the sanitization policy.
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	// Fenced code blocks name their language, e.g. <code class="language-go">
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	// Footnote references, the footnote list and its back links
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^footnote-(ref|backref)$`)).OnElements("a")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^footnotes$`)).OnElements("div")
+	p.AllowAttrs("role").Matching(regexp.MustCompile(`^doc-(noteref|endnotes|backlink)$`)).OnElements("a", "div")
+	// Task list checkboxes, which are always disabled
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}
+
+// Render converts Markdown source to sanitized HTML.
+func Render(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := converter.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return policy.Sanitize(buf.String()), nil
+}
@@ -0,0 +1,95 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		source   string
+		contains []string // Fragments the HTML must contain
+		excludes []string // Fragments the HTML must not contain
+	}{
+		{
+			name:     "CommonMark",
+			source:   "# Title\n\nSome *emphasis* and [a link](https://example.com).",
+			contains: []string{"<h1>Title</h1>", "<em>emphasis</em>", `<a href="https://example.com" rel="nofollow">a link</a>`},
+		},
+		{
+			name:     "table",
+			source:   "| Name | Value |\n|------|------:|\n| a | 1 |",
+			contains: []string{"<table>", "<th>Name</th>", `<td align="right">1</td>`},
+		},
+		{
+			name:     "code fence",
+			source:   "```go\nfmt.Println(\"<hi>\")\n```",
+			contains: []string{`<pre><code class="language-go">fmt.Println(&#34;&lt;hi&gt;&#34;)`},
+		},
+		{
+			name:   "footnote",
+			source: "Claim[^1].\n\n[^1]: Source.",
+			contains: []string{
+				`<sup id="fnref:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref"`,
+				`<div class="footnotes" role="doc-endnotes">`,
+				`<li id="fn:1">`,
+			},
+		},
+		{
+			name:     "task list",
+			source:   "- [x] done\n- [ ] todo",
+			contains: []string{`<input checked="" disabled="" type="checkbox"`, `<input disabled="" type="checkbox"`},
+		},
+		{
+			name:     "raw HTML",
+			source:   "<p onclick=\"steal()\">Hi<script>alert(1)</script></p>\n\n<sup>2</sup> [x](javascript:alert(1))",
+			contains: []string{"<p>Hi</p>", "<sup>2</sup>"},
+			excludes: []string{"onclick", "<script", "alert", "javascript:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			html, err := Render(tt.source)
+			require.NoError(t, err)
+			for _, fragment := range tt.contains {
+				assert.Contains(t, html, fragment)
+			}
+			for _, fragment := range tt.excludes {
+				assert.NotContains(t, html, fragment)
+			}
+		})
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+		length int
+		want   string
+	}{
+		{"plain text of markup", "# Title\n\nSome *emphasis* and [a link](https://example.com).", 100, "Title Some emphasis and a link."},
+		{"code, HTML and footnotes left out", "Intro[^1]\n\n```go\nfmt.Println()\n```\n\n<div>raw</div>\n\nOutro with `code`\n\n[^1]: Note", 100, "Intro Outro with code"},
+		{"autolink", "See https://example.com now", 100, "See https://example.com now"},
+		{"cut at a word boundary", "The quick brown fox jumps over the lazy dog", 20, "The quick brown fox…"},
+		{"cut inside a long word", "Supercalifragilisticexpialidocious", 10, "Supercali…"},
+		{"counts characters, not bytes", "Ünïcödé ünïcödé", 9, "Ünïcödé…"},
+		{"zero length", "Text", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, Excerpt(tt.source, tt.length))
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package models
+
+// ImportResult is the outcome of importing a single item of an archive.
+type ImportResult struct {
+	Index  int    `json:"index" xml:"index" yaml:"index"`                      // Position of the item in the archive
+	Source string `json:"source" xml:"source" yaml:"source"`                   // Where the item was found, e.g. "line 3" or "blogs/3-hello.md"
+	Status int    `json:"status" xml:"status" yaml:"status"`                   // HTTP status creating the item as a single request would have had
+	ID     int    `json:"id,omitempty" xml:"id,omitempty" yaml:"id,omitempty"` // ID of the created blog
+	Error  string `json:"error,omitempty" xml:"error,omitempty" yaml:"error,omitempty"`
+}
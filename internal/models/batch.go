@@ -0,0 +1,27 @@
+package models
+
+// Batch operation kinds.
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// BatchOperation is a single create, update or delete within a batch request.
+type BatchOperation struct {
+	Op   string `json:"op" xml:"op" yaml:"op"`                                     // Operation kind: create, update or delete
+	ID   int    `json:"id,omitempty" xml:"id,omitempty" yaml:"id,omitempty"`       // Blog to update or delete
+	Blog *Blog  `json:"blog,omitempty" xml:"blog,omitempty" yaml:"blog,omitempty"` // Content of the blog to create, or the new content of the blog to update
+
+	KeepTimestamps bool `json:"-" xml:"-" yaml:"-"` // Create the blog with its timestamps, as when restoring an export; never set by clients
+}
+
+// BatchResult is the outcome of a single operation of a batch request.
+type BatchResult struct {
+	Index  int    `json:"index" xml:"index" yaml:"index"`                            // Position of the operation in the request
+	Op     string `json:"op" xml:"op" yaml:"op"`                                     // Operation kind
+	Status int    `json:"status" xml:"status" yaml:"status"`                         // HTTP status the operation would have had as a single request
+	ID     int    `json:"id,omitempty" xml:"id,omitempty" yaml:"id,omitempty"`       // Blog the operation applied to
+	Blog   *Blog  `json:"blog,omitempty" xml:"blog,omitempty" yaml:"blog,omitempty"` // Created or updated blog
+	Error  string `json:"error,omitempty" xml:"error,omitempty" yaml:"error,omitempty"`
+}
@@ -1,14 +1,20 @@
 package models
 
-import "time"
+import (
+	"encoding/xml"
+	"time"
+)
 
 // Blog represents a blog post with its metadata, content, and categorization.
+// Field tags name it the same way in JSON, XML, YAML and MessagePack (which follows the JSON tags).
 type Blog struct {
-	ID        int       `json:"id"`                          // Unique identifier for the blog
-	Title     string    `json:"title" binding:"required"`    // Title of the blog (required)
-	Content   string    `json:"content" binding:"required"`  // Content of the blog (required)
-	Category  string    `json:"category" binding:"required"` // Blog category (required)
-	Tags      []string  `json:"tags" binding:"required"`     // Tags associated with the blog (required)
-	CreatedAt time.Time `json:"createdAt"`                   // Timestamp when the blog was created
-	UpdatedAt time.Time `json:"updatedAt"`                   // Timestamp when the blog was last updated
+	XMLName     xml.Name  `json:"-" xml:"blog" yaml:"-"`                                                          // Element name of the blog in XML
+	ID          int       `json:"id" xml:"id" yaml:"id"`                                                          // Unique identifier for the blog
+	Title       string    `json:"title" xml:"title" yaml:"title" binding:"required"`                              // Title of the blog (required)
+	Content     string    `json:"content" xml:"content" yaml:"content" binding:"required"`                        // Content of the blog in Markdown (required)
+	ContentHTML string    `json:"contentHtml,omitempty" xml:"contentHtml,omitempty" yaml:"contentHtml,omitempty"` // Content rendered to sanitized HTML; set by the service on every write
+	Category    string    `json:"category" xml:"category" yaml:"category" binding:"required"`                     // Blog category (required)
+	Tags        []string  `json:"tags" xml:"tags>tag" yaml:"tags" binding:"required"`                             // Tags associated with the blog (required)
+	CreatedAt   time.Time `json:"createdAt" xml:"createdAt" yaml:"createdAt"`                                     // Timestamp when the blog was created
+	UpdatedAt   time.Time `json:"updatedAt" xml:"updatedAt" yaml:"updatedAt"`                                     // Timestamp when the blog was last updated
 }
@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestStore returns a MemoryStore whose clock is controlled by the returned pointer.
+func newTestStore() (*MemoryStore, *time.Time) {
+	now := time.Date(2024, 12, 25, 16, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	return store, &now
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	t.Parallel()
+
+	store, now := newTestStore()
+	limit := Limit{Rate: 1, Burst: 2}
+
+	for i := 1; i >= 0; i-- {
+		result, err := store.Take(context.Background(), "client", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, i, result.Remaining)
+	}
+
+	result, err := store.Take(context.Background(), "client", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 2*time.Second, result.Reset)
+
+	// Other clients have their own bucket.
+	result, err = store.Take(context.Background(), "other", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// One token is refilled after a second.
+	*now = now.Add(time.Second)
+	result, err = store.Take(context.Background(), "client", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+}
+
+func TestLimiter_Middleware(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	store, _ := newTestStore()
+	limiter := NewLimiter(store, map[string]Limit{GroupWrite: {Rate: 0.5, Burst: 1}})
+
+	router := gin.New()
+	router.POST("/blogs", limiter.Middleware(GroupWrite), func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.GET("/blogs", limiter.Middleware(GroupRead), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(method string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(method, "/blogs", nil))
+		return rec
+	}
+
+	rec := send(http.MethodPost)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2", rec.Header().Get("RateLimit-Reset"))
+
+	rec = send(http.MethodPost)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+
+	// The read group has no configured limit.
+	rec = send(http.MethodGet)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+
+	// Limits can be replaced at runtime.
+	limiter.SetLimits(nil)
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost).Code)
+}
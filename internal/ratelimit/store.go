@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit describes a token bucket that refills at Rate tokens per second up to Burst tokens.
+type Limit struct {
+	Rate  float64 // Tokens added per second
+	Burst int     // Maximum number of tokens, i.e. the largest allowed burst of requests
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	Allowed    bool          // Whether the request may proceed
+	Limit      int           // Bucket capacity
+	Remaining  int           // Whole tokens left after this request
+	Reset      time.Duration // Time until the bucket is full again
+	RetryAfter time.Duration // Time until a token is available, set when the request is rejected
+}
+
+// Store keeps token bucket state. Implementations must be safe for concurrent use.
+// The in-memory store only limits a single replica; a shared implementation (e.g. Redis)
+// can be plugged in to enforce limits across replicas.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// bucket is the state of a single token bucket.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// MemoryStore is an in-process Store backed by a map of token buckets.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	idleAfter time.Duration // Buckets untouched for this long are evicted
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates an empty in-memory Store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*bucket),
+		now:       time.Now,
+		idleAfter: 10 * time.Minute,
+	}
+}
+
+// Take removes a token from the bucket identified by key, refilling it for the time elapsed since its last use.
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = b
+	}
+
+	// Refill for the elapsed time, never exceeding the burst size.
+	elapsed := now.Sub(b.last).Seconds()
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+	b.last = now
+
+	result := Result{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = durationFor(1-b.tokens, limit.Rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = durationFor(float64(limit.Burst)-b.tokens, limit.Rate)
+	return result, nil
+}
+
+// sweep evicts idle buckets at most once per idle period so the map cannot grow without bound.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.idleAfter {
+		return
+	}
+	for key, b := range s.buckets {
+		if now.Sub(b.last) >= s.idleAfter {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// durationFor returns how long it takes to accumulate the given number of tokens at rate tokens per second.
+func durationFor(tokens, rate float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	if rate <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(tokens / rate * float64(time.Second))
+}
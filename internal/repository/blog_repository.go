@@ -1,147 +1,723 @@
 package repository
 
 import (
+	"bloggingplatformapi/internal/logging"
 	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/internal/tracing"
+	"bloggingplatformapi/pkg/db"
+	"context"
 	"database/sql"
-	log "github.com/sirupsen/logrus"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // BlogRepository defines the interfaces for blog-related database operations.
 type BlogRepository interface {
-	Create(blog *models.Blog) error             // Creates a new blog
-	GetByID(id int) (*models.Blog, error)       // Fetch a blog by its ID
-	GetAll(term string) ([]*models.Blog, error) // Fetch all blogs, optional filtered by a search term
-	Update(blog *models.Blog) error             // Update an existing blog
-	Delete(id int) error                        // Delete a blog by its ID
+	Create(ctx context.Context, blog *models.Blog) error                               // Creates a new blog
+	CreateMany(ctx context.Context, blogs []*models.Blog) error                        // Creates several blogs, fastest within a unit of work
+	GetByID(ctx context.Context, id int) (*models.Blog, error)                         // Fetch a blog by its ID
+	GetAll(ctx context.Context, term string, fields ...string) ([]*models.Blog, error) // Fetch all blogs, optional filtered by a search term, with only the given fields (all if none)
+	Search(ctx context.Context, opts SearchOptions) ([]*models.Blog, error)            // Fetch a page of the blogs matching opts, by ID
+	Count(ctx context.Context, opts SearchOptions) (int, error)                        // Count the blogs matching opts on every page
+	ListAfter(ctx context.Context, afterID, limit int) ([]*models.Blog, error)         // Fetch up to limit blogs with IDs above afterID, by ID
+	ListGroups(ctx context.Context, groups []ListOptions) ([]*models.Blog, error)      // Fetch the blogs List would for each group at once, newest first
+	List(ctx context.Context, opts ListOptions) ([]*models.Blog, error)                // Fetch the newest blogs matching opts
+	Stats(ctx context.Context) (Stats, error)                                          // Count the blogs and find the highest ID
+	Update(ctx context.Context, blog *models.Blog) error                               // Update an existing blog
+	Delete(ctx context.Context, id int) error                                          // Delete a blog by its ID
+}
+
+// ListOptions selects the blogs returned by List.
+type ListOptions struct {
+	Category string // Only blogs in this category, ignoring case; empty for any
+	Tag      string // Only blogs with this tag, ignoring case; empty for any
+	Limit    int    // Maximum number of blogs, 0 for no limit
+}
+
+// SearchOptions selects the blogs returned by Search and counted by Count. Fields, Limit and Offset only
+// apply to Search.
+type SearchOptions struct {
+	Term     string   // Only blogs whose title, content or category contain the term, ignoring case; empty for any
+	Category string   // Only blogs in this category, ignoring case; empty for any
+	Tag      string   // Only blogs with this tag, ignoring case; empty for any
+	AfterID  int      // Only blogs with a greater ID, to page by ID; 0 for any
+	Fields   []string // Fields to read, the others are left unset; empty for all
+	Limit    int      // Maximum number of blogs, 0 for no limit
+	Offset   int      // Number of matching blogs to skip
+}
+
+// Stats summarizes the stored blogs.
+type Stats struct {
+	Count int // Number of blogs
+	MaxID int // Highest blog ID, 0 if there are no blogs
 }
 
 // blogRepository is a concrete implementation of the BlogRepository interface.
 type blogRepository struct {
-	db *sql.DB // Database connection
+	router  *db.Router     // Routes writes to the primary and reads to replicas
+	dialect *dialect       // SQL dialect of the router's databases
+	tx      *sql.Tx        // Transaction all queries run in, nil outside a unit of work
+	retry   db.RetryPolicy // Retry policy for idempotent reads
+}
+
+// querier is the subset of *sql.DB and *sql.Tx used by the repository.
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// NewBlogRepository creates a new BlogRepository instance that sends every query to a single database.
+func NewBlogRepository(database *sql.DB) BlogRepository {
+	return NewRoutedBlogRepository(db.NewRouter(database, nil, db.RouterOptions{}))
+}
+
+// NewRoutedBlogRepository creates a new BlogRepository instance that reads from the replicas of router.
+// Queries are written in the SQL dialect of router.Driver, PostgreSQL or SQLite.
+// Reads that fail with a transient error, such as a dropped connection, are retried with db.DefaultRetryPolicy.
+func NewRoutedBlogRepository(router *db.Router) BlogRepository {
+	return &blogRepository{router: router, dialect: dialectFor(router.Driver()), retry: db.DefaultRetryPolicy}
 }
 
-// NewBlogRepository creates a new BlogRepository instance.
-func NewBlogRepository(db *sql.DB) BlogRepository {
-	return &blogRepository{db}
+// withTx returns a copy of the repository that runs every query in tx.
+// Failed reads are not retried because the error aborts the transaction; the unit of work retries it as a whole.
+func (r *blogRepository) withTx(tx *sql.Tx) *blogRepository {
+	return &blogRepository{router: r.router, dialect: r.dialect, tx: tx, retry: db.RetryPolicy{Attempts: 1}}
+}
+
+// writer returns where writes go: the transaction, or the primary.
+func (r *blogRepository) writer() querier {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.router.Primary()
+}
+
+// reader returns where reads go: the transaction, or a replica chosen by the router.
+func (r *blogRepository) reader(ctx context.Context) querier {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.router.Reader(ctx)
+}
+
+// recordWrite notes a committed write for read-your-writes routing.
+// Inside a transaction the unit of work does this once the transaction commits.
+func (r *blogRepository) recordWrite(ctx context.Context) {
+	if r.tx == nil {
+		r.router.RecordWrite(ctx)
+	}
 }
 
 // Create inserts a new blog into the database.
-func (r *blogRepository) Create(blog *models.Blog) error {
-	query := `
-		INSERT INTO blogs (title, content, category, tags, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, NOW(), NOW()) 
+// Its timestamps are kept if set, as when restoring a backup, and are the current time otherwise.
+func (r *blogRepository) Create(ctx context.Context, blog *models.Blog) error {
+	query := r.dialect.sql(`
+		INSERT INTO blogs (title, content, content_html, category, tags, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6, NOW()), COALESCE($7, NOW()))
 		RETURNING id, created_at, updated_at
-	`
-	tags := strings.Join(blog.Tags, ",") // Convert tags slice into a comma-seperated string
-	err := r.db.QueryRow(query, blog.Title, blog.Content, blog.Category, tags).Scan(&blog.ID, &blog.CreatedAt, &blog.UpdatedAt)
+	`)
+	ctx, span := startQuerySpan(ctx, r.dialect, "INSERT", query)
+	defer span.End()
+
+	err := r.writer().QueryRowContext(ctx, query, blog.Title, blog.Content, blog.ContentHTML, blog.Category, r.dialect.tags(&blog.Tags),
+		r.dialect.time(blog.CreatedAt), r.dialect.time(blog.UpdatedAt)).Scan(&blog.ID, &blog.CreatedAt, &blog.UpdatedAt)
 	if err != nil {
+		recordQueryError(span, err)
 		return err
 	}
+	r.recordWrite(ctx)
 	return nil
 }
 
+// CreateManyError reports the blog that CreateMany failed to store.
+type CreateManyError struct {
+	Index int   // Position of the blog in the blogs passed to CreateMany
+	Err   error // Why it could not be stored
+}
+
+// Error implements error.
+func (e *CreateManyError) Error() string {
+	return fmt.Sprintf("failed to create blog %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the error storing the blog.
+func (e *CreateManyError) Unwrap() error {
+	return e.Err
+}
+
+// copyThreshold is the number of blogs from which CreateMany loads them with COPY rather than one INSERT each.
+const copyThreshold = 50
+
+// CreateMany inserts blogs in order and sets their IDs and timestamps, keeping the timestamps already set like Create.
+// Inside a unit of work on PostgreSQL, copyThreshold or more blogs are loaded with a single COPY.
+// Outside one, each blog is inserted on its own, so a failure leaves the blogs before it created.
+// The error of an INSERT is a *CreateManyError naming its blog. So is the error of a COPY if PostgreSQL
+// reports the row that failed, e.g. for a violated constraint; otherwise, e.g. on a lost connection, it does
+// not belong to any one blog and is returned as it is.
+func (r *blogRepository) CreateMany(ctx context.Context, blogs []*models.Blog) error {
+	if r.tx == nil || r.dialect != postgresDialect || len(blogs) < copyThreshold {
+		for i, blog := range blogs {
+			if err := r.Create(ctx, blog); err != nil {
+				return &CreateManyError{Index: i, Err: err}
+			}
+		}
+		return nil
+	}
+
+	ids, now, err := r.reserveIDs(ctx, len(blogs))
+	if err != nil {
+		return err
+	}
+	if err := r.copyIn(ctx, blogs, ids, now); err != nil {
+		return copyRowError(err, len(blogs))
+	}
+	for i, blog := range blogs {
+		blog.ID, blog.CreatedAt, blog.UpdatedAt = ids[i], orNow(blog.CreatedAt, now), orNow(blog.UpdatedAt, now)
+	}
+	return nil
+}
+
+// reserveIDs draws n IDs from the blogs sequence, since COPY cannot return the IDs it assigns.
+// It also returns the transaction time, which NOW() would have given the rows.
+func (r *blogRepository) reserveIDs(ctx context.Context, n int) ([]int, time.Time, error) {
+	query := `SELECT nextval(pg_get_serial_sequence('blogs', 'id')), NOW() FROM generate_series(1, $1)`
+	ctx, span := startQuerySpan(ctx, r.dialect, "SELECT", query)
+	defer span.End()
+
+	rows, err := r.tx.QueryContext(ctx, query, n)
+	if err != nil {
+		recordQueryError(span, err)
+		return nil, time.Time{}, err
+	}
+	defer func(rows *sql.Rows) {
+		if err := rows.Close(); err != nil {
+			logging.FromContext(ctx).Errorf("error closing rows: %v", err)
+		}
+	}(rows)
+
+	ids := make([]int, 0, n)
+	var now time.Time
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id, &now); err != nil {
+			recordQueryError(span, err)
+			return nil, time.Time{}, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		recordQueryError(span, err)
+		return nil, time.Time{}, err
+	}
+	return ids, now, nil
+}
+
+// copyIn loads blogs with the given IDs into the blogs table with COPY. Timestamps that are not set become now.
+func (r *blogRepository) copyIn(ctx context.Context, blogs []*models.Blog, ids []int, now time.Time) error {
+	query := pq.CopyIn("blogs", "id", "title", "content", "content_html", "category", "tags", "created_at", "updated_at")
+	ctx, span := startQuerySpan(ctx, r.dialect, "COPY", query)
+	defer span.End()
+
+	stmt, err := r.tx.PrepareContext(ctx, query)
+	if err != nil {
+		recordQueryError(span, err)
+		return err
+	}
+	defer func(stmt *sql.Stmt) {
+		if err := stmt.Close(); err != nil {
+			logging.FromContext(ctx).Errorf("error closing COPY statement: %v", err)
+		}
+	}(stmt)
+
+	for i, blog := range blogs {
+		if _, err := stmt.ExecContext(ctx, ids[i], blog.Title, blog.Content, blog.ContentHTML, blog.Category, pq.Array(blog.Tags),
+			orNow(blog.CreatedAt, now), orNow(blog.UpdatedAt, now)); err != nil {
+			recordQueryError(span, err)
+			return err
+		}
+	}
+	if _, err := stmt.ExecContext(ctx); err != nil { // Flushes the buffered rows
+		recordQueryError(span, err)
+		return err
+	}
+	return nil
+}
+
+// orNow returns t, or now if t is not set.
+func orNow(t, now time.Time) time.Time {
+	if t.IsZero() {
+		return now
+	}
+	return t
+}
+
+// copyLine matches the context PostgreSQL gives the error of a row of a COPY into blogs, which names its line,
+// e.g. `COPY blogs, line 3: "..."`.
+var copyLine = regexp.MustCompile(`^COPY blogs, line (\d+)`)
+
+// copyRowError returns err, an error of the COPY of n blogs, as a *CreateManyError naming the blog whose row
+// failed if PostgreSQL reported its line. Lines are numbered from 1 in the order the blogs were sent.
+func copyRowError(err error, n int) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	match := copyLine.FindStringSubmatch(pqErr.Where)
+	if match == nil {
+		return err
+	}
+	line, convErr := strconv.Atoi(match[1])
+	if convErr != nil || line < 1 || line > n {
+		return err
+	}
+	return &CreateManyError{Index: line - 1, Err: err}
+}
+
 // GetByID retrieves a single blog by its ID.
-func (r *blogRepository) GetByID(id int) (*models.Blog, error) {
-	query := `
-		SELECT id, title, content, category, tags, created_at, updated_at 
+func (r *blogRepository) GetByID(ctx context.Context, id int) (*models.Blog, error) {
+	var blog *models.Blog
+	err := db.Retry(ctx, r.retry, func() (err error) {
+		blog, err = r.getByID(ctx, id)
+		return err
+	})
+	return blog, err
+}
+
+// getByID runs a single attempt of GetByID.
+func (r *blogRepository) getByID(ctx context.Context, id int) (*models.Blog, error) {
+	query := r.dialect.sql(`
+		SELECT id, title, content, content_html, category, tags, created_at, updated_at 
 		FROM blogs 
 		WHERE id = $1
-	`
+	`)
+	ctx, span := startQuerySpan(ctx, r.dialect, "SELECT", query)
+	defer span.End()
+
 	var blog models.Blog
-	var tags string
 
-	err := r.db.QueryRow(query, id).Scan(
-		&blog.ID, &blog.Title, &blog.Content, &blog.Category, &tags, &blog.CreatedAt, &blog.UpdatedAt,
+	err := r.reader(ctx).QueryRowContext(ctx, query, id).Scan(
+		&blog.ID, &blog.Title, &blog.Content, &blog.ContentHTML, &blog.Category, r.dialect.tags(&blog.Tags), &blog.CreatedAt, &blog.UpdatedAt,
 	)
 	if err != nil {
+		recordQueryError(span, err)
 		return nil, err
 	}
 
-	blog.Tags = strings.Split(tags, ",") // Convert the tags string back to a slice
 	return &blog, nil
 }
 
 // GetAll retrieves all blogs or filters them based on a search term.
-func (r *blogRepository) GetAll(term string) ([]*models.Blog, error) {
+// Only the columns holding fields are selected, so blogs have the other fields unset; no fields selects all.
+func (r *blogRepository) GetAll(ctx context.Context, term string, fields ...string) ([]*models.Blog, error) {
+	columns, err := selectColumns(fields)
+	if err != nil {
+		return nil, err
+	}
 	var blogs []*models.Blog
+	err = db.Retry(ctx, r.retry, func() (err error) {
+		blogs, err = r.getAll(ctx, term, columns, fields)
+		return err
+	})
+	return blogs, err
+}
+
+// getAll runs a single attempt of GetAll, selecting columns. The blogs are ordered by ID, which the
+// repositories agree on; without ORDER BY PostgreSQL returns them in whatever order it finds them, which
+// changes as rows are updated.
+func (r *blogRepository) getAll(ctx context.Context, term, columns string, fields []string) ([]*models.Blog, error) {
 	var rows *sql.Rows
 	var err error
+	var span trace.Span
 
 	if term != "" {
 		likeTerm := "%" + term + "%"
-		query := `
-			SELECT id, title, content, category, tags, created_at, updated_at
+		query := r.dialect.sql(`
+			SELECT ` + columns + `
 			From blogs
 			WHERE title ILIKE $1 OR content ILIKE $1 OR category ILIKE $1
-		`
-		rows, err = r.db.Query(query, likeTerm)
+			ORDER BY id
+		`)
+		ctx, span = startQuerySpan(ctx, r.dialect, "SELECT", query)
+		rows, err = r.reader(ctx).QueryContext(ctx, query, likeTerm)
 	} else {
-		query := `SELECT id, title, content, category, tags, created_at, updated_at FROM blogs`
-		rows, err = r.db.Query(query)
+		query := r.dialect.sql(`SELECT ` + columns + ` FROM blogs ORDER BY id`)
+		ctx, span = startQuerySpan(ctx, r.dialect, "SELECT", query)
+		rows, err = r.reader(ctx).QueryContext(ctx, query)
 	}
+	defer span.End()
 
 	if err != nil {
+		recordQueryError(span, err)
 		return nil, err
 	}
 
-	defer func(rows *sql.Rows) {
-		err := rows.Close()
-		if err != nil {
-			log.Errorf("error closing rows: %v", err)
+	blogs, err := r.scanBlogs(ctx, rows, fields...)
+	if err != nil {
+		recordQueryError(span, err)
+		return nil, err
+	}
+	return blogs, nil
+}
+
+// Search retrieves a page of the blogs matching opts, ordered by ID. Only the columns holding opts.Fields are
+// selected, as for GetAll, and the page is cut by the database, so only its rows are read.
+func (r *blogRepository) Search(ctx context.Context, opts SearchOptions) ([]*models.Blog, error) {
+	columns, err := selectColumns(opts.Fields)
+	if err != nil {
+		return nil, err
+	}
+	var blogs []*models.Blog
+	err = db.Retry(ctx, r.retry, func() (err error) {
+		blogs, err = r.search(ctx, opts, columns)
+		return err
+	})
+	return blogs, err
+}
+
+// search runs a single attempt of Search, selecting columns.
+func (r *blogRepository) search(ctx context.Context, opts SearchOptions, columns string) ([]*models.Blog, error) {
+	where, args := r.searchConditions(opts)
+	param := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+	query := `SELECT ` + columns + ` FROM blogs` + where + ` ORDER BY id`
+	switch {
+	case opts.Limit > 0:
+		query += " LIMIT " + param(opts.Limit)
+	case opts.Offset > 0:
+		query += " LIMIT " + r.dialect.noLimit // SQLite accepts OFFSET only after a LIMIT
+	}
+	if opts.Offset > 0 {
+		query += " OFFSET " + param(opts.Offset)
+	}
+	query = r.dialect.sql(query)
+	ctx, span := startQuerySpan(ctx, r.dialect, "SELECT", query)
+	defer span.End()
+
+	rows, err := r.reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		recordQueryError(span, err)
+		return nil, err
+	}
+	blogs, err := r.scanBlogs(ctx, rows, opts.Fields...)
+	if err != nil {
+		recordQueryError(span, err)
+		return nil, err
+	}
+	return blogs, nil
+}
+
+// Count counts the blogs matching opts, ignoring its fields, limit and offset.
+func (r *blogRepository) Count(ctx context.Context, opts SearchOptions) (int, error) {
+	var count int
+	err := db.Retry(ctx, r.retry, func() error {
+		where, args := r.searchConditions(opts)
+		query := r.dialect.sql(`SELECT COUNT(*) FROM blogs` + where)
+		ctx, span := startQuerySpan(ctx, r.dialect, "SELECT", query)
+		defer span.End()
+
+		if err := r.reader(ctx).QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+			recordQueryError(span, err)
+			return err
 		}
-	}(rows) // Ensure rows are properly closed
+		return nil
+	})
+	return count, err
+}
 
-	for rows.Next() {
-		var blog models.Blog
-		var tags string
-		if err := rows.Scan(&blog.ID, &blog.Title, &blog.Content, &blog.Category, &tags, &blog.CreatedAt, &blog.UpdatedAt); err != nil {
+// searchConditions returns the WHERE clause selecting the blogs matching opts, empty if it selects all, and its
+// arguments.
+func (r *blogRepository) searchConditions(opts SearchOptions) (string, []any) {
+	var conditions []string
+	var args []any
+	param := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+	if opts.Term != "" {
+		term := param("%" + opts.Term + "%")
+		conditions = append(conditions, "(title ILIKE "+term+" OR content ILIKE "+term+" OR category ILIKE "+term+")")
+	}
+	if opts.Category != "" {
+		conditions = append(conditions, "LOWER(category) = LOWER("+param(opts.Category)+")")
+	}
+	if opts.Tag != "" {
+		conditions = append(conditions, fmt.Sprintf(r.dialect.hasTag, param(opts.Tag)))
+	}
+	if opts.AfterID > 0 {
+		conditions = append(conditions, "id > "+param(opts.AfterID))
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// ListAfter retrieves up to limit blogs whose ID is greater than afterID, ordered by ID.
+// Paging by ID stays consistent while blogs are created or deleted between pages.
+func (r *blogRepository) ListAfter(ctx context.Context, afterID, limit int) ([]*models.Blog, error) {
+	var blogs []*models.Blog
+	err := db.Retry(ctx, r.retry, func() (err error) {
+		blogs, err = r.listAfter(ctx, afterID, limit)
+		return err
+	})
+	return blogs, err
+}
+
+// listAfter runs a single attempt of ListAfter.
+func (r *blogRepository) listAfter(ctx context.Context, afterID, limit int) ([]*models.Blog, error) {
+	query := r.dialect.sql(`
+		SELECT id, title, content, content_html, category, tags, created_at, updated_at
+		FROM blogs
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`)
+	ctx, span := startQuerySpan(ctx, r.dialect, "SELECT", query)
+	defer span.End()
+
+	rows, err := r.reader(ctx).QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		recordQueryError(span, err)
+		return nil, err
+	}
+	blogs, err := r.scanBlogs(ctx, rows)
+	if err != nil {
+		recordQueryError(span, err)
+		return nil, err
+	}
+	return blogs, nil
+}
+
+// List retrieves the blogs matching opts, newest first by creation time and then by ID.
+func (r *blogRepository) List(ctx context.Context, opts ListOptions) ([]*models.Blog, error) {
+	var blogs []*models.Blog
+	err := db.Retry(ctx, r.retry, func() (err error) {
+		blogs, err = r.list(ctx, opts)
+		return err
+	})
+	return blogs, err
+}
+
+// list runs a single attempt of List.
+func (r *blogRepository) list(ctx context.Context, opts ListOptions) ([]*models.Blog, error) {
+	var args []any
+	param := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+	return r.queryList(ctx, r.listSelect(opts, param), args)
+}
+
+// listGroupsChunk is the most groups ListGroups queries at once, keeping each UNION well within SQLite's limit
+// of 500 compound terms and PostgreSQL's of 65535 parameters.
+const listGroupsChunk = 100
+
+// ListGroups retrieves the blogs List would return for each of groups, e.g. the newest posts of several categories
+// and tags, each group limited on its own, with one query per listGroupsChunk groups. A blog in several groups is
+// returned once, and the blogs are ordered as by List, so the blogs of a group are those matching it, in order,
+// up to its limit.
+func (r *blogRepository) ListGroups(ctx context.Context, groups []ListOptions) ([]*models.Blog, error) {
+	var blogs []*models.Blog
+	for chunk := range slices.Chunk(groups, listGroupsChunk) {
+		var found []*models.Blog
+		err := db.Retry(ctx, r.retry, func() (err error) {
+			found, err = r.listGroups(ctx, chunk)
+			return err
+		})
+		if err != nil {
 			return nil, err
 		}
-		blog.Tags = strings.Split(tags, ",")
-		blogs = append(blogs, &blog)
+		blogs = append(blogs, found...)
 	}
 
-	// Check for errors during iteration
-	if err := rows.Err(); err != nil {
-		return nil, err
+	seen := make(map[int]bool, len(blogs))
+	unique := []*models.Blog{} // Empty rather than nil, like List
+	for _, blog := range blogs {
+		if !seen[blog.ID] {
+			seen[blog.ID] = true
+			unique = append(unique, blog)
+		}
 	}
+	slices.SortFunc(unique, newestFirst)
+	return unique, nil
+}
 
+// listGroups runs a single attempt of the query of ListGroups for a chunk of groups.
+func (r *blogRepository) listGroups(ctx context.Context, groups []ListOptions) ([]*models.Blog, error) {
+	var args []any
+	param := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+	// Each group is a subquery so it keeps its own ORDER BY and LIMIT, which SQLite does not allow on the
+	// members of a UNION
+	selects := make([]string, len(groups))
+	for i, opts := range groups {
+		selects[i] = fmt.Sprintf("SELECT * FROM (%s) AS group%d", r.listSelect(opts, param), i)
+	}
+	return r.queryList(ctx, strings.Join(selects, " UNION ALL "), args)
+}
+
+// listSelect returns the query of List for opts, adding its arguments with param.
+func (r *blogRepository) listSelect(opts ListOptions, param func(any) string) string {
+	var conditions []string
+	if opts.Category != "" {
+		conditions = append(conditions, "LOWER(category) = LOWER("+param(opts.Category)+")")
+	}
+	if opts.Tag != "" {
+		conditions = append(conditions, fmt.Sprintf(r.dialect.hasTag, param(opts.Tag)))
+	}
+
+	query := `SELECT id, title, content, content_html, category, tags, created_at, updated_at FROM blogs`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+	if opts.Limit > 0 {
+		query += " LIMIT " + param(opts.Limit)
+	}
+	return query
+}
+
+// queryList runs query, which selects every column, with args and scans the blogs it returns.
+func (r *blogRepository) queryList(ctx context.Context, query string, args []any) ([]*models.Blog, error) {
+	query = r.dialect.sql(query)
+	ctx, span := startQuerySpan(ctx, r.dialect, "SELECT", query)
+	defer span.End()
+
+	rows, err := r.reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		recordQueryError(span, err)
+		return nil, err
+	}
+	blogs, err := r.scanBlogs(ctx, rows)
+	if err != nil {
+		recordQueryError(span, err)
+		return nil, err
+	}
 	return blogs, nil
 }
 
+// Stats counts the blogs and finds the highest ID.
+func (r *blogRepository) Stats(ctx context.Context) (Stats, error) {
+	var stats Stats
+	err := db.Retry(ctx, r.retry, func() error {
+		query := `SELECT COUNT(*), COALESCE(MAX(id), 0) FROM blogs`
+		ctx, span := startQuerySpan(ctx, r.dialect, "SELECT", query)
+		defer span.End()
+
+		if err := r.reader(ctx).QueryRowContext(ctx, query).Scan(&stats.Count, &stats.MaxID); err != nil {
+			recordQueryError(span, err)
+			return err
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// scanBlogs reads every blog from rows, whose columns hold fields (all if none), and closes them.
+func (r *blogRepository) scanBlogs(ctx context.Context, rows *sql.Rows, fields ...string) ([]*models.Blog, error) {
+	defer func(rows *sql.Rows) {
+		if err := rows.Close(); err != nil {
+			logging.FromContext(ctx).Errorf("error closing rows: %v", err)
+		}
+	}(rows)
+
+	if len(fields) == 0 {
+		fields = BlogFields
+	}
+	blogs := []*models.Blog{} // Empty rather than nil, so no match encodes as [] rather than null
+	for rows.Next() {
+		var blog models.Blog
+		dest := make([]any, len(fields))
+		for i, field := range fields {
+			dest[i] = r.scanDest(&blog, field)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		blogs = append(blogs, &blog)
+	}
+	return blogs, rows.Err()
+}
+
 // Update modifies an existing blog in the database.
-func (r *blogRepository) Update(blog *models.Blog) error {
-	query := `
+func (r *blogRepository) Update(ctx context.Context, blog *models.Blog) error {
+	query := r.dialect.sql(`
 		UPDATE blogs
-		SET title = $1, content = $2, category = $3, tags = $4, updated_at = NOW()
-		WHERE id = $5 
+		SET title = $1, content = $2, content_html = $3, category = $4, tags = $5, updated_at = NOW()
+		WHERE id = $6
 		RETURNING updated_at
-	`
-	tags := strings.Join(blog.Tags, ",") // Convert tags slice to a comma-seperated string
-	err := r.db.QueryRow(query, blog.Title, blog.Content, blog.Category, tags, blog.ID).Scan(&blog.UpdatedAt)
+	`)
+	ctx, span := startQuerySpan(ctx, r.dialect, "UPDATE", query)
+	defer span.End()
+
+	err := r.writer().QueryRowContext(ctx, query, blog.Title, blog.Content, blog.ContentHTML, blog.Category, r.dialect.tags(&blog.Tags), blog.ID).Scan(&blog.UpdatedAt)
 	if err != nil {
+		recordQueryError(span, err)
 		return err
 	}
+	r.recordWrite(ctx)
 	return nil
 }
 
 // Delete removes a blog by its ID from the database.
-func (r *blogRepository) Delete(id int) error {
-	query := `DELETE FROM blogs WHERE id = $1`
-	res, err := r.db.Exec(query, id)
+func (r *blogRepository) Delete(ctx context.Context, id int) error {
+	query := r.dialect.sql(`DELETE FROM blogs WHERE id = $1`)
+	ctx, span := startQuerySpan(ctx, r.dialect, "DELETE", query)
+	defer span.End()
+
+	res, err := r.writer().ExecContext(ctx, query, id)
 	if err != nil {
+		recordQueryError(span, err)
 		return err
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
+		recordQueryError(span, err)
 		return err
 	}
 
 	if rowsAffected == 0 {
 		return sql.ErrNoRows // Return a specific error if no rows were deleted
 	}
+	r.recordWrite(ctx)
 
 	return nil
 }
+
+// startQuerySpan starts a client span describing a single SQL statement against the blogs table.
+func startQuerySpan(ctx context.Context, d *dialect, operation, query string) (context.Context, trace.Span) {
+	return otel.Tracer(tracing.InstrumentationName).Start(ctx, operation+" blogs",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			d.system,
+			semconv.DBOperationName(operation),
+			semconv.DBCollectionName("blogs"),
+			semconv.DBQueryText(strings.Join(strings.Fields(query), " ")),
+		),
+	)
+}
+
+// recordQueryError records a failed statement on the span.
+// sql.ErrNoRows is an expected outcome for lookups, so it is not reported as a failure.
+func recordQueryError(span trace.Span, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+	tracing.RecordError(span, err)
+}
@@ -3,7 +3,11 @@ package repository
 import (
 	"bloggingplatformapi/internal/models"
 	"bloggingplatformapi/pkg/mock/dbmock"
+	"context"
+	"database/sql"
+	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -31,18 +35,19 @@ func TestBlogRepository_GetAll(t *testing.T) {
 	defer (*mock).ExpectClose()
 
 	blog := &models.Blog{
-		Title:    "Test Title",
-		Content:  "Test Content",
-		Category: "Tech",
-		Tags:     []string{"Go", "Testing"},
+		Title:       "Test Title",
+		Content:     "Test Content",
+		ContentHTML: "<p>Test Content</p>",
+		Category:    "Tech",
+		Tags:        []string{"Go", "Testing"},
 	}
 
-	(*mock).ExpectQuery(`INSERT INTO blogs \(title, content, category, tags, created_at, updated_at\) VALUES \(\$1, \$2, \$3, \$4, NOW\(\), NOW\(\)\) RETURNING id, created_at, updated_at`).
-		WithArgs(blog.Title, blog.Content, blog.Category, "Go,Testing").
+	(*mock).ExpectQuery(`INSERT INTO blogs \(title, content, content_html, category, tags, created_at, updated_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, COALESCE\(\$6, NOW\(\)\), COALESCE\(\$7, NOW\(\)\)\) RETURNING id, created_at, updated_at`).
+		WithArgs(blog.Title, blog.Content, blog.ContentHTML, blog.Category, pq.Array([]string{"Go", "Testing"}), nil, nil).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).
 			AddRow(1, mockTimeNow(), mockTimeNow()))
 
-	err := repo.Create(blog)
+	err := repo.Create(context.Background(), blog)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, blog.ID)
 
@@ -58,28 +63,53 @@ func TestBlogRepository_GetByID(t *testing.T) {
 	now := mockTimeNow()
 	blogID := 1
 	expectedBlog := &models.Blog{
-		ID:        blogID,
-		Title:     "Test Title",
-		Content:   "Test Content",
-		Category:  "Tech",
-		Tags:      []string{"Go", "Testing"},
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:          blogID,
+		Title:       "Test Title",
+		Content:     "Test Content",
+		ContentHTML: "<p>Test Content</p>",
+		Category:    "Tech",
+		Tags:        []string{"Go", "Testing"},
+		CreatedAt:   now,
+		UpdatedAt:   now,
 	}
 
 	(*mock).ExpectQuery(
-		`SELECT id, title, content, category, tags, created_at, updated_at 
+		`SELECT id, title, content, content_html, category, tags, created_at, updated_at 
          FROM blogs WHERE id = \$1`,
-	).WithArgs(blogID).WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "category", "tags", "created_at", "updated_at"}).
-		AddRow(expectedBlog.ID, expectedBlog.Title, expectedBlog.Content, expectedBlog.Category, "Go,Testing", now, now))
+	).WithArgs(blogID).WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "content_html", "category", "tags", "created_at", "updated_at"}).
+		AddRow(expectedBlog.ID, expectedBlog.Title, expectedBlog.Content, expectedBlog.ContentHTML, expectedBlog.Category, "{Go,Testing}", now, now))
 
-	blog, err := repo.GetByID(blogID)
+	blog, err := repo.GetByID(context.Background(), blogID)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedBlog, blog)
 
 	assert.NoError(t, (*mock).ExpectationsWereMet())
 }
 
+func TestBlogRepository_TagsRoundTripAsTextArray(t *testing.T) {
+	t.Parallel()
+
+	mock, repo := setupTest(t)
+	defer (*mock).ExpectClose()
+
+	// Tags with commas and quotes survive, which they did not when stored joined with commas
+	tags := []string{"Go", "Unit, integration", `"Quoted"`}
+	blog := &models.Blog{Title: "Tags", Content: "Content", Category: "Tech", Tags: tags}
+	(*mock).ExpectQuery(`INSERT INTO blogs`).
+		WithArgs(blog.Title, blog.Content, blog.ContentHTML, blog.Category, pq.Array(tags), nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(1, mockTimeNow(), mockTimeNow()))
+	(*mock).ExpectQuery(`SELECT (.+) FROM blogs WHERE id = \$1`).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "content_html", "category", "tags", "created_at", "updated_at"}).
+			AddRow(1, blog.Title, blog.Content, "", blog.Category, `{Go,"Unit, integration","\"Quoted\""}`, mockTimeNow(), mockTimeNow()))
+
+	assert.NoError(t, repo.Create(context.Background(), blog))
+	stored, err := repo.GetByID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, tags, stored.Tags)
+
+	assert.NoError(t, (*mock).ExpectationsWereMet())
+}
+
 func TestBlogRepository_Update(t *testing.T) {
 	t.Parallel()
 
@@ -97,12 +127,12 @@ func TestBlogRepository_Update(t *testing.T) {
 
 	(*mock).ExpectQuery(
 		`UPDATE blogs 
-         SET title = \$1, content = \$2, category = \$3, tags = \$4, updated_at = NOW\(\) 
-         WHERE id = \$5 RETURNING updated_at`,
-	).WithArgs(blog.Title, blog.Content, blog.Category, "Go,GORM", blog.ID).
+         SET title = \$1, content = \$2, content_html = \$3, category = \$4, tags = \$5, updated_at = NOW\(\) 
+         WHERE id = \$6 RETURNING updated_at`,
+	).WithArgs(blog.Title, blog.Content, blog.ContentHTML, blog.Category, pq.Array([]string{"Go", "GORM"}), blog.ID).
 		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(now))
 
-	err := repo.Update(blog)
+	err := repo.Update(context.Background(), blog)
 	assert.NoError(t, err)
 
 	assert.NoError(t, (*mock).ExpectationsWereMet())
@@ -120,8 +150,148 @@ func TestBlogRepository_Delete(t *testing.T) {
 		`DELETE FROM blogs WHERE id = \$1`,
 	).WithArgs(blogID).WillReturnResult(sqlmock.NewResult(0, 1))
 
-	err := repo.Delete(blogID)
+	err := repo.Delete(context.Background(), blogID)
+	assert.NoError(t, err)
+
+	assert.NoError(t, (*mock).ExpectationsWereMet())
+}
+
+func TestBlogRepository_GetByID_RetriesTransientErrors(t *testing.T) {
+	t.Parallel()
+
+	mock, repo := setupTest(t)
+	defer (*mock).ExpectClose()
+
+	now := mockTimeNow()
+	query := `SELECT id, title, content, content_html, category, tags, created_at, updated_at FROM blogs WHERE id = \$1`
+
+	(*mock).ExpectQuery(query).WithArgs(1).WillReturnError(&pq.Error{Code: "57P01"}) // admin_shutdown
+	(*mock).ExpectQuery(query).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "content_html", "category", "tags", "created_at", "updated_at"}).
+		AddRow(1, "Test Title", "Test Content", "<p>Test Content</p>", "Tech", "{Go}", now, now))
+
+	blog, err := repo.GetByID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Test Title", blog.Title)
+
+	// Permanent errors are not retried.
+	(*mock).ExpectQuery(query).WithArgs(2).WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByID(context.Background(), 2)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	assert.NoError(t, (*mock).ExpectationsWereMet())
+}
+
+func TestBlogRepository_CreateManyUsesCopyInTransaction(t *testing.T) {
+	t.Parallel()
+
+	mock, uow := setupUnitOfWork(t)
+	blogs := make([]*models.Blog, copyThreshold)
+	reserved := sqlmock.NewRows([]string{"nextval", "now"})
+	for i := range blogs {
+		blogs[i] = &models.Blog{Title: "Bulk", Content: "Content", Category: "Tech", Tags: []string{"Go"}}
+		reserved.AddRow(100+i, mockTimeNow())
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT nextval\(pg_get_serial_sequence\('blogs', 'id'\)\), NOW\(\) FROM generate_series\(1, \$1\)`).
+		WithArgs(copyThreshold).WillReturnRows(reserved)
+	copyIn := mock.ExpectPrepare(`COPY "blogs" \("id", "title", "content", "content_html", "category", "tags", "created_at", "updated_at"\) FROM STDIN`)
+	for i := range blogs {
+		copyIn.ExpectExec().WithArgs(100+i, "Bulk", "Content", "", "Tech", pq.Array([]string{"Go"}), mockTimeNow(), mockTimeNow()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	copyIn.ExpectExec().WithoutArgs().WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := uow.WithTx(context.Background(), func(repos Repositories) error {
+		return repos.Blogs.CreateMany(context.Background(), blogs)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 100, blogs[0].ID)
+	assert.Equal(t, 100+copyThreshold-1, blogs[copyThreshold-1].ID)
+	assert.Equal(t, mockTimeNow(), blogs[0].CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBlogRepository_CreateManyNamesFailedCopyRow(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		err       error
+		wantIndex int // -1 if no blog is named
+	}{
+		{"row named", &pq.Error{Code: "23514", Message: "violates check constraint", Where: `COPY blogs, line 8: "107	Bulk"`}, 7},
+		{"no row", &pq.Error{Code: "57P01", Message: "terminating connection"}, -1},
+		{"line out of range", &pq.Error{Code: "23514", Where: "COPY blogs, line 99"}, -1},
+		{"not from PostgreSQL", errors.New("connection reset"), -1},
+	}
+	for _, tt := range tests {
+		mock, uow := setupUnitOfWork(t)
+		blogs := make([]*models.Blog, copyThreshold)
+		reserved := sqlmock.NewRows([]string{"nextval", "now"})
+		for i := range blogs {
+			blogs[i] = &models.Blog{Title: "Bulk", Content: "Content", Category: "Tech", Tags: []string{"Go"}}
+			reserved.AddRow(100+i, mockTimeNow())
+		}
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT nextval`).WithArgs(copyThreshold).WillReturnRows(reserved)
+		copyIn := mock.ExpectPrepare(`COPY "blogs"`)
+		for range blogs {
+			copyIn.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		copyIn.ExpectExec().WithoutArgs().WillReturnError(tt.err) // PostgreSQL checks the rows as the COPY ends
+		mock.ExpectRollback()
+
+		err := uow.WithTx(context.Background(), func(repos Repositories) error {
+			return repos.Blogs.CreateMany(context.Background(), blogs)
+		})
+		assert.ErrorIs(t, err, tt.err, tt.name)
+		var createErr *CreateManyError
+		if tt.wantIndex < 0 {
+			assert.False(t, errors.As(err, &createErr), tt.name)
+		} else if assert.True(t, errors.As(err, &createErr), tt.name) {
+			assert.Equal(t, tt.wantIndex, createErr.Index, tt.name)
+		}
+	}
+}
+
+func TestBlogRepository_ListFiltersByCategoryAndTag(t *testing.T) {
+	t.Parallel()
+
+	mock, repo := setupTest(t)
+	defer (*mock).ExpectClose()
+
+	now := mockTimeNow()
+	(*mock).ExpectQuery(
+		`SELECT id, title, content, content_html, category, tags, created_at, updated_at FROM blogs `+
+			`WHERE LOWER\(category\) = LOWER\(\$1\) AND EXISTS \(SELECT 1 FROM unnest\(tags\) AS tag WHERE LOWER\(tag\) = LOWER\(\$2\)\) `+
+			`ORDER BY created_at DESC, id DESC LIMIT \$3`,
+	).WithArgs("Tech", "Go", 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "content_html", "category", "tags", "created_at", "updated_at"}).
+			AddRow(1, "Test Title", "Test Content", "<p>Test Content</p>", "Tech", "{Go}", now, now))
+
+	blogs, err := repo.List(context.Background(), ListOptions{Category: "Tech", Tag: "Go", Limit: 20})
+	assert.NoError(t, err)
+	assert.Len(t, blogs, 1)
+
+	assert.NoError(t, (*mock).ExpectationsWereMet())
+}
+
+func TestBlogRepository_GetAllSelectsOnlyProjectedColumns(t *testing.T) {
+	t.Parallel()
+
+	mock, repo := setupTest(t)
+	defer (*mock).ExpectClose()
+
+	(*mock).ExpectQuery(`SELECT id, title, tags From blogs WHERE title ILIKE \$1 OR content ILIKE \$1 OR category ILIKE \$1 ORDER BY id`).
+		WithArgs("%go%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "tags"}).AddRow(1, "Test Title", "{Go}"))
+
+	blogs, err := repo.GetAll(context.Background(), "go", FieldID, FieldTitle, FieldTags)
 	assert.NoError(t, err)
+	assert.Equal(t, []*models.Blog{{ID: 1, Title: "Test Title", Tags: []string{"Go"}}}, blogs)
 
 	assert.NoError(t, (*mock).ExpectationsWereMet())
 }
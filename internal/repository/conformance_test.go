@@ -0,0 +1,56 @@
+package repository_test
+
+import (
+	"bloggingplatformapi/internal/repository"
+	"bloggingplatformapi/internal/repository/repositorytest"
+	"bloggingplatformapi/migrations"
+	"bloggingplatformapi/pkg/db"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testDatabaseURLEnv names a disposable PostgreSQL database to run the conformance suite against.
+// Its blogs table is emptied before every test.
+const testDatabaseURLEnv = "TEST_DATABASE_URL"
+
+func TestMemoryBlogRepository_Conformance(t *testing.T) {
+	repositorytest.RunBlogRepositoryTests(t, func(t *testing.T) (repository.BlogRepository, repository.UnitOfWork) {
+		repo := repository.NewMemoryBlogRepository()
+		return repo, repo.UnitOfWork()
+	})
+}
+
+func TestPostgresBlogRepository_Conformance(t *testing.T) {
+	url := os.Getenv(testDatabaseURLEnv)
+	if url == "" {
+		t.Skipf("%s is not set", testDatabaseURLEnv)
+	}
+
+	database, err := db.InitDB(context.Background(), url, db.DefaultOptions())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = database.Close() })
+	require.NoError(t, migrations.Apply(context.Background(), database, migrations.Files))
+
+	router := db.NewRouter(database, nil, db.RouterOptions{})
+	repositorytest.RunBlogRepositoryTests(t, func(t *testing.T) (repository.BlogRepository, repository.UnitOfWork) {
+		_, err := database.Exec(`TRUNCATE blogs RESTART IDENTITY`)
+		require.NoError(t, err)
+		return repository.NewRoutedBlogRepository(router), repository.NewUnitOfWork(router)
+	})
+}
+
+func TestSQLiteBlogRepository_Conformance(t *testing.T) {
+	repositorytest.RunBlogRepositoryTests(t, func(t *testing.T) (repository.BlogRepository, repository.UnitOfWork) {
+		database, err := db.InitDB(context.Background(), "sqlite://"+filepath.Join(t.TempDir(), "blog.db"), db.DefaultOptions())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = database.Close() })
+		require.NoError(t, migrations.Apply(context.Background(), database, migrations.SQLiteFiles))
+
+		router := db.NewRouter(database, nil, db.RouterOptions{Driver: db.DriverSQLite})
+		return repository.NewRoutedBlogRepository(router), repository.NewUnitOfWork(router)
+	})
+}
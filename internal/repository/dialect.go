@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"bloggingplatformapi/pkg/db"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// dialect adapts the repository's queries, which are written for PostgreSQL, to a database engine.
+type dialect struct {
+	system  attribute.KeyValue       // db.system.name recorded on query spans
+	rewrite *strings.Replacer        // Rewrites PostgreSQL-specific SQL, nil if none is needed
+	tags    func(tags *[]string) any // Wraps tags as both a query argument and a scan destination
+	hasTag  string                   // Condition that the tags column holds the tag in %s, ignoring case
+	noLimit string                   // Argument of LIMIT selecting every row
+	time    func(t time.Time) any    // Encodes a timestamp as a query argument, NULL if it is zero
+}
+
+// postgresDialect runs the queries unchanged and stores tags in a TEXT[] column, encoded as an array. Joining
+// them with commas instead, as the repository first did, is rejected by PostgreSQL as a malformed array
+// literal, and splitting the column's text form on commas reads back "{Go" and "Testing}".
+var postgresDialect = &dialect{
+	system:  semconv.DBSystemNamePostgreSQL,
+	tags:    func(tags *[]string) any { return pq.Array(tags) },
+	hasTag:  `EXISTS (SELECT 1 FROM unnest(tags) AS tag WHERE LOWER(tag) = LOWER(%s))`,
+	noLimit: "ALL",
+	time:    postgresTime,
+}
+
+// sqliteDialect stores timestamps as ISO 8601 text in UTC and tags as a JSON array.
+// SQLite's LIKE ignores case for ASCII letters only, unlike ILIKE which ignores it for all of Unicode.
+var sqliteDialect = &dialect{
+	system: semconv.DBSystemNameSQLite,
+	rewrite: strings.NewReplacer(
+		"NOW()", "strftime('%Y-%m-%dT%H:%M:%fZ', 'now')",
+		"ILIKE", "LIKE",
+	),
+	tags:    func(tags *[]string) any { return jsonTags{tags: tags} },
+	hasTag:  `EXISTS (SELECT 1 FROM json_each(tags) WHERE LOWER(json_each.value) = LOWER(%s))`,
+	noLimit: "-1",
+	time:    sqliteTime,
+}
+
+// dialectFor returns the dialect for a driver, see db.ParseDataSource.
+func dialectFor(driverName string) *dialect {
+	if driverName == db.DriverSQLite {
+		return sqliteDialect
+	}
+	return postgresDialect
+}
+
+// sql returns query rewritten for the database engine.
+func (d *dialect) sql(query string) string {
+	if d.rewrite == nil {
+		return query
+	}
+	return d.rewrite.Replace(query)
+}
+
+// postgresTime passes a timestamp to PostgreSQL as it is, and a zero one as NULL.
+func postgresTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+// sqliteTimeLayout is the layout of the timestamps SQLite stores, as produced by NOW() rewritten.
+const sqliteTimeLayout = "2006-01-02T15:04:05.000Z"
+
+// sqliteTime formats a timestamp like the ones SQLite stores, so they sort as text, and a zero one as NULL.
+func sqliteTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(sqliteTimeLayout)
+}
+
+// jsonTags stores tags in a TEXT column as a JSON array of strings.
+type jsonTags struct {
+	tags *[]string
+}
+
+// Value implements driver.Valuer. Nil tags are stored as an empty array.
+func (j jsonTags) Value() (driver.Value, error) {
+	tags := *j.tags
+	if tags == nil {
+		tags = []string{}
+	}
+	encoded, err := json.Marshal(tags)
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+// Scan implements sql.Scanner.
+func (j jsonTags) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*j.tags = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), j.tags)
+	case []byte:
+		return json.Unmarshal(src, j.tags)
+	default:
+		return fmt.Errorf("cannot scan %T into tags", src)
+	}
+}
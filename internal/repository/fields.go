@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"bloggingplatformapi/internal/models"
+	"fmt"
+	"strings"
+)
+
+// Blog fields a projection can select, named as in JSON.
+const (
+	FieldID          = "id"
+	FieldTitle       = "title"
+	FieldContent     = "content"
+	FieldContentHTML = "contentHtml"
+	FieldCategory    = "category"
+	FieldTags        = "tags"
+	FieldCreatedAt   = "createdAt"
+	FieldUpdatedAt   = "updatedAt"
+)
+
+// BlogFields lists every field of a blog in column order.
+var BlogFields = []string{
+	FieldID, FieldTitle, FieldContent, FieldContentHTML, FieldCategory, FieldTags, FieldCreatedAt, FieldUpdatedAt,
+}
+
+// fieldColumns maps each field to the column holding it.
+var fieldColumns = map[string]string{
+	FieldID:          "id",
+	FieldTitle:       "title",
+	FieldContent:     "content",
+	FieldContentHTML: "content_html",
+	FieldCategory:    "category",
+	FieldTags:        "tags",
+	FieldCreatedAt:   "created_at",
+	FieldUpdatedAt:   "updated_at",
+}
+
+// ValidField reports whether name is a field a projection can select.
+func ValidField(name string) bool {
+	_, ok := fieldColumns[name]
+	return ok
+}
+
+// selectColumns returns the select list of the columns holding fields, or of every column if fields is empty.
+func selectColumns(fields []string) (string, error) {
+	if len(fields) == 0 {
+		fields = BlogFields
+	}
+	columns := make([]string, len(fields))
+	for i, field := range fields {
+		column, ok := fieldColumns[field]
+		if !ok {
+			return "", fmt.Errorf("unknown blog field %q", field)
+		}
+		columns[i] = column
+	}
+	return strings.Join(columns, ", "), nil
+}
+
+// scanDest returns the scan destination of field in blog.
+func (r *blogRepository) scanDest(blog *models.Blog, field string) any {
+	switch field {
+	case FieldID:
+		return &blog.ID
+	case FieldTitle:
+		return &blog.Title
+	case FieldContent:
+		return &blog.Content
+	case FieldContentHTML:
+		return &blog.ContentHTML
+	case FieldCategory:
+		return &blog.Category
+	case FieldTags:
+		return r.dialect.tags(&blog.Tags)
+	case FieldCreatedAt:
+		return &blog.CreatedAt
+	default:
+		return &blog.UpdatedAt
+	}
+}
+
+// project returns a copy of blog with only fields set, or a full copy if fields is empty.
+func project(blog *models.Blog, fields []string) *models.Blog {
+	if len(fields) == 0 {
+		return cloneBlog(blog)
+	}
+	projected := &models.Blog{}
+	for _, field := range fields {
+		switch field {
+		case FieldID:
+			projected.ID = blog.ID
+		case FieldTitle:
+			projected.Title = blog.Title
+		case FieldContent:
+			projected.Content = blog.Content
+		case FieldContentHTML:
+			projected.ContentHTML = blog.ContentHTML
+		case FieldCategory:
+			projected.Category = blog.Category
+		case FieldTags:
+			projected.Tags = append([]string{}, blog.Tags...)
+		case FieldCreatedAt:
+			projected.CreatedAt = blog.CreatedAt
+		case FieldUpdatedAt:
+			projected.UpdatedAt = blog.UpdatedAt
+		}
+	}
+	return projected
+}
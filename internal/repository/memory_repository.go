@@ -0,0 +1,439 @@
+package repository
+
+import (
+	"bloggingplatformapi/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryBlogRepository is a BlogRepository that keeps blogs in memory, for tests and local development.
+// It behaves like the PostgreSQL repository: IDs are assigned in sequence starting at 1, timestamps are
+// set on create and update, missing IDs return sql.ErrNoRows and search is a case-insensitive substring
+// match on title, content and category. It is safe for concurrent use.
+type MemoryBlogRepository struct {
+	txMu sync.RWMutex // Held exclusively by a running transaction, shared by every other operation
+
+	mu     sync.Mutex           // Guards the fields below
+	blogs  map[int]*models.Blog // Stored blogs by ID
+	nextID int                  // ID assigned to the next created blog
+
+	now func() time.Time // Clock, replaceable in tests
+}
+
+// NewMemoryBlogRepository creates an empty MemoryBlogRepository.
+func NewMemoryBlogRepository() *MemoryBlogRepository {
+	return &MemoryBlogRepository{blogs: make(map[int]*models.Blog), nextID: 1, now: time.Now}
+}
+
+// UnitOfWork returns a UnitOfWork whose transactions run against the repository.
+// Transactions are serialized with every other operation and their changes are discarded if they fail.
+func (r *MemoryBlogRepository) UnitOfWork() UnitOfWork {
+	return &memoryUnitOfWork{repo: r}
+}
+
+// Create implements BlogRepository.
+func (r *MemoryBlogRepository) Create(ctx context.Context, blog *models.Blog) error {
+	return r.view(false).Create(ctx, blog)
+}
+
+// CreateMany implements BlogRepository.
+func (r *MemoryBlogRepository) CreateMany(ctx context.Context, blogs []*models.Blog) error {
+	return r.view(false).CreateMany(ctx, blogs)
+}
+
+// GetByID implements BlogRepository.
+func (r *MemoryBlogRepository) GetByID(ctx context.Context, id int) (*models.Blog, error) {
+	return r.view(false).GetByID(ctx, id)
+}
+
+// GetAll implements BlogRepository.
+func (r *MemoryBlogRepository) GetAll(ctx context.Context, term string, fields ...string) ([]*models.Blog, error) {
+	return r.view(false).GetAll(ctx, term, fields...)
+}
+
+// Search implements BlogRepository.
+func (r *MemoryBlogRepository) Search(ctx context.Context, opts SearchOptions) ([]*models.Blog, error) {
+	return r.view(false).Search(ctx, opts)
+}
+
+// Count implements BlogRepository.
+func (r *MemoryBlogRepository) Count(ctx context.Context, opts SearchOptions) (int, error) {
+	return r.view(false).Count(ctx, opts)
+}
+
+// ListAfter implements BlogRepository.
+func (r *MemoryBlogRepository) ListAfter(ctx context.Context, afterID, limit int) ([]*models.Blog, error) {
+	return r.view(false).ListAfter(ctx, afterID, limit)
+}
+
+// ListGroups implements BlogRepository.
+func (r *MemoryBlogRepository) ListGroups(ctx context.Context, groups []ListOptions) ([]*models.Blog, error) {
+	return r.view(false).ListGroups(ctx, groups)
+}
+
+// List implements BlogRepository.
+func (r *MemoryBlogRepository) List(ctx context.Context, opts ListOptions) ([]*models.Blog, error) {
+	return r.view(false).List(ctx, opts)
+}
+
+// Stats implements BlogRepository.
+func (r *MemoryBlogRepository) Stats(ctx context.Context) (Stats, error) {
+	return r.view(false).Stats(ctx)
+}
+
+// Update implements BlogRepository.
+func (r *MemoryBlogRepository) Update(ctx context.Context, blog *models.Blog) error {
+	return r.view(false).Update(ctx, blog)
+}
+
+// Delete implements BlogRepository.
+func (r *MemoryBlogRepository) Delete(ctx context.Context, id int) error {
+	return r.view(false).Delete(ctx, id)
+}
+
+// view returns the repository's operations either outside a transaction or within the running one.
+func (r *MemoryBlogRepository) view(inTx bool) *memoryBlogView {
+	return &memoryBlogView{repo: r, inTx: inTx}
+}
+
+// memoryBlogView implements BlogRepository on a MemoryBlogRepository.
+type memoryBlogView struct {
+	repo *MemoryBlogRepository
+	inTx bool // The caller's transaction already holds txMu exclusively
+}
+
+// lock acquires the locks an operation needs and returns the function releasing them.
+func (v *memoryBlogView) lock() func() {
+	if !v.inTx {
+		v.repo.txMu.RLock()
+	}
+	v.repo.mu.Lock()
+	return func() {
+		v.repo.mu.Unlock()
+		if !v.inTx {
+			v.repo.txMu.RUnlock()
+		}
+	}
+}
+
+// Create stores a copy of blog and sets its ID and the timestamps that are not already set.
+func (v *memoryBlogView) Create(ctx context.Context, blog *models.Blog) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := checkConstraints(blog); err != nil {
+		return err
+	}
+	defer v.lock()()
+
+	now := v.repo.timestamp()
+	blog.ID = v.repo.nextID
+	blog.CreatedAt = orNow(blog.CreatedAt, now).UTC().Truncate(time.Microsecond)
+	blog.UpdatedAt = orNow(blog.UpdatedAt, now).UTC().Truncate(time.Microsecond)
+	v.repo.nextID++
+	v.repo.blogs[blog.ID] = cloneBlog(blog)
+	return nil
+}
+
+// CreateMany stores copies of blogs in order, like Create. The error storing a blog is a *CreateManyError
+// naming it.
+func (v *memoryBlogView) CreateMany(ctx context.Context, blogs []*models.Blog) error {
+	for i, blog := range blogs {
+		if err := v.Create(ctx, blog); err != nil {
+			return &CreateManyError{Index: i, Err: err}
+		}
+	}
+	return nil
+}
+
+// GetByID returns a copy of the blog with the given ID.
+func (v *memoryBlogView) GetByID(ctx context.Context, id int) (*models.Blog, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer v.lock()()
+
+	blog, ok := v.repo.blogs[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return cloneBlog(blog), nil
+}
+
+// GetAll returns copies of the blogs matching term, ordered by ID, with only fields set (all if none).
+func (v *memoryBlogView) GetAll(ctx context.Context, term string, fields ...string) ([]*models.Blog, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if _, err := selectColumns(fields); err != nil {
+		return nil, err
+	}
+	defer v.lock()()
+
+	ids := slices.Collect(maps.Keys(v.repo.blogs))
+	sort.Ints(ids)
+
+	blogs := []*models.Blog{} // Empty rather than nil, like the SQL repository
+	for _, id := range ids {
+		if blog := v.repo.blogs[id]; matches(blog, term) {
+			blogs = append(blogs, project(blog, fields))
+		}
+	}
+	return blogs, nil
+}
+
+// Search returns copies of the page of blogs matching opts, ordered by ID, with only opts.Fields set.
+func (v *memoryBlogView) Search(ctx context.Context, opts SearchOptions) ([]*models.Blog, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if _, err := selectColumns(opts.Fields); err != nil {
+		return nil, err
+	}
+	defer v.lock()()
+
+	ids := v.search(opts)
+	ids = ids[min(opts.Offset, len(ids)):]
+	if opts.Limit > 0 {
+		ids = ids[:min(opts.Limit, len(ids))]
+	}
+	blogs := []*models.Blog{}
+	for _, id := range ids {
+		blogs = append(blogs, project(v.repo.blogs[id], opts.Fields))
+	}
+	return blogs, nil
+}
+
+// Count counts the blogs matching opts, ignoring its fields, limit and offset.
+func (v *memoryBlogView) Count(ctx context.Context, opts SearchOptions) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	defer v.lock()()
+
+	return len(v.search(opts)), nil
+}
+
+// search returns the sorted IDs of the blogs matching opts, ignoring its fields, limit and offset. The lock must
+// be held.
+func (v *memoryBlogView) search(opts SearchOptions) []int {
+	var ids []int
+	for id, blog := range v.repo.blogs {
+		if id <= opts.AfterID || !matches(blog, opts.Term) {
+			continue
+		}
+		if opts.Category != "" && !strings.EqualFold(blog.Category, opts.Category) {
+			continue
+		}
+		if opts.Tag != "" && !slices.ContainsFunc(blog.Tags, func(tag string) bool { return strings.EqualFold(tag, opts.Tag) }) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// ListAfter returns copies of up to limit blogs with IDs above afterID, ordered by ID.
+func (v *memoryBlogView) ListAfter(ctx context.Context, afterID, limit int) ([]*models.Blog, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer v.lock()()
+
+	var ids []int
+	for id := range v.repo.blogs {
+		if id > afterID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	blogs := []*models.Blog{}
+	for _, id := range ids[:min(limit, len(ids))] {
+		blogs = append(blogs, cloneBlog(v.repo.blogs[id]))
+	}
+	return blogs, nil
+}
+
+// List returns copies of the blogs matching opts, newest first by creation time and then by ID.
+func (v *memoryBlogView) List(ctx context.Context, opts ListOptions) ([]*models.Blog, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer v.lock()()
+
+	blogs := []*models.Blog{}
+	for _, blog := range v.repo.blogs {
+		if opts.Category != "" && !strings.EqualFold(blog.Category, opts.Category) {
+			continue
+		}
+		if opts.Tag != "" && !slices.ContainsFunc(blog.Tags, func(tag string) bool { return strings.EqualFold(tag, opts.Tag) }) {
+			continue
+		}
+		blogs = append(blogs, cloneBlog(blog))
+	}
+	slices.SortFunc(blogs, newestFirst)
+	if opts.Limit > 0 && len(blogs) > opts.Limit {
+		blogs = blogs[:opts.Limit]
+	}
+	return blogs, nil
+}
+
+// ListGroups returns copies of the blogs List returns for each of groups, each once, newest first.
+func (v *memoryBlogView) ListGroups(ctx context.Context, groups []ListOptions) ([]*models.Blog, error) {
+	blogs := []*models.Blog{}
+	seen := make(map[int]bool)
+	for _, opts := range groups {
+		group, err := v.List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, blog := range group {
+			if !seen[blog.ID] {
+				seen[blog.ID] = true
+				blogs = append(blogs, blog)
+			}
+		}
+	}
+	slices.SortFunc(blogs, newestFirst)
+	return blogs, nil
+}
+
+// Stats counts the stored blogs and finds the highest ID.
+func (v *memoryBlogView) Stats(ctx context.Context) (Stats, error) {
+	if err := ctx.Err(); err != nil {
+		return Stats{}, err
+	}
+	defer v.lock()()
+
+	stats := Stats{Count: len(v.repo.blogs)}
+	for id := range v.repo.blogs {
+		stats.MaxID = max(stats.MaxID, id)
+	}
+	return stats, nil
+}
+
+// Update replaces the stored blog with the same ID and sets its update time.
+func (v *memoryBlogView) Update(ctx context.Context, blog *models.Blog) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer v.lock()()
+
+	stored, ok := v.repo.blogs[blog.ID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if err := checkConstraints(blog); err != nil {
+		return err
+	}
+
+	updated := cloneBlog(blog)
+	updated.CreatedAt = stored.CreatedAt
+	updated.UpdatedAt = v.repo.timestamp()
+	v.repo.blogs[blog.ID] = updated
+
+	blog.UpdatedAt = updated.UpdatedAt // Only the update time is returned, like UPDATE ... RETURNING updated_at
+	return nil
+}
+
+// Delete removes the blog with the given ID.
+func (v *memoryBlogView) Delete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer v.lock()()
+
+	if _, ok := v.repo.blogs[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(v.repo.blogs, id)
+	return nil
+}
+
+// timestamp returns the current time at the microsecond precision PostgreSQL stores.
+func (r *MemoryBlogRepository) timestamp() time.Time {
+	return r.now().UTC().Truncate(time.Microsecond)
+}
+
+// checkConstraints enforces the CHECK constraints of the blogs table.
+func checkConstraints(blog *models.Blog) error {
+	if blog.Title == "" {
+		return errors.New("new row for relation \"blogs\" violates check constraint \"chk_title_length\"")
+	}
+	if blog.Category == "" {
+		return errors.New("new row for relation \"blogs\" violates check constraint \"chk_category_length\"")
+	}
+	return nil
+}
+
+// matches reports whether blog's title, content or category contains term, ignoring case.
+// An empty term matches every blog.
+func matches(blog *models.Blog, term string) bool {
+	if term == "" {
+		return true
+	}
+	term = strings.ToLower(term)
+	return strings.Contains(strings.ToLower(blog.Title), term) ||
+		strings.Contains(strings.ToLower(blog.Content), term) ||
+		strings.Contains(strings.ToLower(blog.Category), term)
+}
+
+// newestFirst orders blogs by creation time and then by ID, both descending, as List returns them.
+func newestFirst(a, b *models.Blog) int {
+	if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+		return c
+	}
+	return b.ID - a.ID
+}
+
+// cloneBlog copies blog so callers cannot modify stored state.
+func cloneBlog(blog *models.Blog) *models.Blog {
+	clone := *blog
+	clone.Tags = slices.Clone(blog.Tags)
+	return &clone
+}
+
+// memoryUnitOfWork implements UnitOfWork for a MemoryBlogRepository.
+type memoryUnitOfWork struct {
+	repo *MemoryBlogRepository
+}
+
+// WithTx runs fn with exclusive access to the repository and restores its previous blogs if fn fails.
+// Like a PostgreSQL sequence, the next ID is not rolled back.
+func (u *memoryUnitOfWork) WithTx(ctx context.Context, fn func(repos Repositories) error) (err error) {
+	u.repo.txMu.Lock()
+	defer u.repo.txMu.Unlock()
+
+	// Stored blogs are replaced rather than modified, so a shallow copy of the map is a full snapshot
+	u.repo.mu.Lock()
+	snapshot := maps.Clone(u.repo.blogs)
+	u.repo.mu.Unlock()
+
+	defer func() {
+		if p := recover(); p != nil {
+			u.restore(snapshot)
+			panic(p)
+		}
+		if err != nil {
+			u.restore(snapshot)
+		}
+	}()
+
+	return fn(Repositories{Blogs: u.repo.view(true)})
+}
+
+// restore resets the repository to a snapshot taken at the start of a transaction.
+func (u *memoryUnitOfWork) restore(blogs map[int]*models.Blog) {
+	u.repo.mu.Lock()
+	defer u.repo.mu.Unlock()
+	u.repo.blogs = blogs
+}
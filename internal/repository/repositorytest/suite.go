@@ -0,0 +1,518 @@
+// Package repositorytest provides a conformance test suite that every repository implementation must pass,
+// so in-memory and database-backed repositories can be used interchangeably.
+package repositorytest
+
+import (
+	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/internal/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Factory returns an empty blog repository and a unit of work on the same storage, for a single test.
+type Factory func(t *testing.T) (repository.BlogRepository, repository.UnitOfWork)
+
+// RunBlogRepositoryTests runs the conformance suite against the repositories created by newRepos.
+// Subtests run one after another, so a factory may reuse a shared database as long as it empties it.
+func RunBlogRepositoryTests(t *testing.T, newRepos Factory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, repo repository.BlogRepository, uow repository.UnitOfWork)
+	}{
+		{"CreateAssignsIDAndTimestamps", testCreate},
+		{"CreateKeepsSetTimestamps", testCreateKeepsTimestamps},
+		{"CreateManyKeepsOrder", testCreateMany},
+		{"CreateManyNamesFailedBlog", testCreateManyFailure},
+		{"GetByIDReturnsStoredBlog", testGetByID},
+		{"MissingIDsReturnErrNoRows", testMissingIDs},
+		{"GetAllSearchesIgnoringCase", testGetAll},
+		{"ListAfterPagesByID", testListAfter},
+		{"ListFiltersNewestFirst", testList},
+		{"ListGroupsLimitsEachGroup", testListGroups},
+		{"SearchPagesAndCounts", testSearch},
+		{"StatsCountsBlogs", testStats},
+		{"GetAllProjectsFields", testGetAllProjects},
+		{"UpdateKeepsCreationTime", testUpdate},
+		{"DeleteRemovesBlog", testDelete},
+		{"UnitOfWorkRollsBackOnError", testUnitOfWorkRollback},
+		{"ConcurrentCreatesGetDistinctIDs", testConcurrentCreates},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, uow := newRepos(t)
+			tt.run(t, repo, uow)
+		})
+	}
+}
+
+// newBlog returns a valid blog that has not been stored yet.
+func newBlog(title string) *models.Blog {
+	return &models.Blog{
+		Title: title, Content: "Content of " + title, ContentHTML: "<p>Content of " + title + "</p>",
+		Category: "Tech", Tags: []string{"Go", "Testing"},
+	}
+}
+
+// create stores a blog with the given title and fails the test on error.
+func create(t *testing.T, repo repository.BlogRepository, title string) *models.Blog {
+	t.Helper()
+	blog := newBlog(title)
+	require.NoError(t, repo.Create(context.Background(), blog))
+	return blog
+}
+
+func testCreate(t *testing.T, repo repository.BlogRepository, _ repository.UnitOfWork) {
+	before := time.Now().Add(-time.Second) // Allow for clock differences with a database server
+
+	first := create(t, repo, "First")
+	second := create(t, repo, "Second")
+
+	assert.Positive(t, first.ID)
+	assert.Greater(t, second.ID, first.ID)
+	assert.WithinRange(t, first.CreatedAt, before, time.Now().Add(time.Second))
+	assert.True(t, first.CreatedAt.Equal(first.UpdatedAt))
+}
+
+func testCreateKeepsTimestamps(t *testing.T, repo repository.BlogRepository, uow repository.UnitOfWork) {
+	ctx := context.Background()
+	created := time.Date(2021, 3, 4, 5, 6, 7, 8e6, time.FixedZone("CET", 3600)) // Milliseconds survive every engine
+	updated := created.Add(48 * time.Hour)
+
+	restored := newBlog("Restored")
+	restored.CreatedAt, restored.UpdatedAt = created, updated
+	require.NoError(t, repo.Create(ctx, restored))
+	fresh := create(t, repo, "Fresh")
+
+	// Enough blogs for a bulk load, where the repository supports one, every other one with timestamps
+	bulk := make([]*models.Blog, 60)
+	for i := range bulk {
+		bulk[i] = newBlog(fmt.Sprintf("Bulk %d", i))
+		if i%2 == 0 {
+			bulk[i].CreatedAt, bulk[i].UpdatedAt = created, updated
+		}
+	}
+	require.NoError(t, uow.WithTx(ctx, func(repos repository.Repositories) error {
+		return repos.Blogs.CreateMany(ctx, bulk)
+	}))
+
+	for i, blog := range append([]*models.Blog{restored, fresh}, bulk...) {
+		stored, err := repo.GetByID(ctx, blog.ID)
+		require.NoError(t, err)
+		assert.True(t, blog.CreatedAt.Equal(stored.CreatedAt), blog.Title)
+		assert.True(t, blog.UpdatedAt.Equal(stored.UpdatedAt), blog.Title)
+		if i%2 == 0 { // The restored blog and every other bulk blog
+			assert.True(t, created.Equal(stored.CreatedAt), "%s created at %v", blog.Title, stored.CreatedAt)
+			assert.True(t, updated.Equal(stored.UpdatedAt), "%s updated at %v", blog.Title, stored.UpdatedAt)
+		} else {
+			assert.True(t, stored.CreatedAt.After(updated), "%s created at %v", blog.Title, stored.CreatedAt)
+		}
+	}
+
+	all, err := repo.List(ctx, repository.ListOptions{Limit: 100})
+	require.NoError(t, err)
+	require.Len(t, all, len(bulk)+2)
+	// The 31 blogs created now come first, then the ones with the older creation time, newest ID first.
+	assert.Equal(t, bulk[58].ID, all[len(bulk)/2+1].ID, "blogs are ordered by the creation time they were given")
+}
+
+func testCreateMany(t *testing.T, repo repository.BlogRepository, uow repository.UnitOfWork) {
+	ctx := context.Background()
+
+	// Enough blogs for a bulk load, where the repository supports one, in a unit of work and a few outside one.
+	inTx := make([]*models.Blog, 60)
+	for i := range inTx {
+		inTx[i] = newBlog(fmt.Sprintf("Bulk %d", i))
+	}
+	inTx[1].Tags = []string{}
+	require.NoError(t, uow.WithTx(ctx, func(repos repository.Repositories) error {
+		return repos.Blogs.CreateMany(ctx, inTx)
+	}))
+	outsideTx := []*models.Blog{newBlog("Single 1"), newBlog("Single 2")}
+	require.NoError(t, repo.CreateMany(ctx, outsideTx))
+
+	all, err := repo.GetAll(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, all, len(inTx)+len(outsideTx))
+	for i, blog := range append(inTx, outsideTx...) {
+		assert.Positive(t, blog.ID)
+		assert.False(t, blog.CreatedAt.IsZero())
+		assert.Equal(t, blog.ID, all[i].ID) // IDs follow the order of the blogs
+		assert.Equal(t, blog.Title, all[i].Title)
+		assert.Equal(t, blog.Tags, all[i].Tags)
+		assert.True(t, blog.CreatedAt.Equal(all[i].CreatedAt))
+	}
+}
+
+func testCreateManyFailure(t *testing.T, repo repository.BlogRepository, uow repository.UnitOfWork) {
+	ctx := context.Background()
+
+	// Enough blogs for a bulk load, where the repository supports one, with an invalid blog past the first
+	blogs := make([]*models.Blog, 60)
+	for i := range blogs {
+		blogs[i] = newBlog(fmt.Sprintf("Bulk %d", i))
+	}
+	blogs[37].Title = ""
+	err := uow.WithTx(ctx, func(repos repository.Repositories) error {
+		return repos.Blogs.CreateMany(ctx, blogs)
+	})
+	var createErr *repository.CreateManyError
+	require.ErrorAs(t, err, &createErr)
+	assert.Equal(t, 37, createErr.Index)
+	assert.ErrorContains(t, createErr, "chk_title_length")
+
+	all, err := repo.GetAll(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, all, "the unit of work is rolled back")
+}
+
+func testGetByID(t *testing.T, repo repository.BlogRepository, _ repository.UnitOfWork) {
+	ctx := context.Background()
+	created := create(t, repo, "Stored")
+
+	blog, err := repo.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, blog.ID)
+	assert.Equal(t, "Stored", blog.Title)
+	assert.Equal(t, "Content of Stored", blog.Content)
+	assert.Equal(t, "Tech", blog.Category)
+	assert.Equal(t, []string{"Go", "Testing"}, blog.Tags)
+	assert.True(t, created.CreatedAt.Equal(blog.CreatedAt))
+
+	// Empty tags stay empty rather than becoming a single empty tag.
+	untagged := newBlog("Untagged")
+	untagged.Tags = []string{}
+	require.NoError(t, repo.Create(ctx, untagged))
+	blog, err = repo.GetByID(ctx, untagged.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{}, blog.Tags)
+
+	// The returned blog is a copy.
+	blog.Title = "Changed"
+	blog, err = repo.GetByID(ctx, untagged.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Untagged", blog.Title)
+}
+
+func testMissingIDs(t *testing.T, repo repository.BlogRepository, _ repository.UnitOfWork) {
+	ctx := context.Background()
+
+	_, err := repo.GetByID(ctx, 4242)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	missing := newBlog("Missing")
+	missing.ID = 4242
+	assert.ErrorIs(t, repo.Update(ctx, missing), sql.ErrNoRows)
+	assert.ErrorIs(t, repo.Delete(ctx, 4242), sql.ErrNoRows)
+}
+
+func testGetAll(t *testing.T, repo repository.BlogRepository, _ repository.UnitOfWork) {
+	ctx := context.Background()
+
+	golang := create(t, repo, "Learning Go")
+	rust := newBlog("Learning Rust")
+	rust.Category = "Systems"
+	require.NoError(t, repo.Create(ctx, rust))
+	cooking := newBlog("Pasta")
+	cooking.Content = "Boil water"
+	cooking.Category = "Food"
+	require.NoError(t, repo.Create(ctx, cooking))
+
+	all, err := repo.GetAll(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []int{golang.ID, rust.ID, cooking.ID}, ids(all)) // Ordered by ID
+
+	byTitle, err := repo.GetAll(ctx, "learning")
+	require.NoError(t, err)
+	assert.Equal(t, []int{golang.ID, rust.ID}, ids(byTitle))
+
+	byContent, err := repo.GetAll(ctx, "WATER")
+	require.NoError(t, err)
+	assert.Equal(t, []int{cooking.ID}, ids(byContent))
+
+	byCategory, err := repo.GetAll(ctx, "system")
+	require.NoError(t, err)
+	assert.Equal(t, []int{rust.ID}, ids(byCategory))
+
+	none, err := repo.GetAll(ctx, "nothing matches this")
+	require.NoError(t, err)
+	assert.NotNil(t, none, "no match is an empty list, not nil")
+	assert.Empty(t, none)
+}
+
+func testListAfter(t *testing.T, repo repository.BlogRepository, _ repository.UnitOfWork) {
+	ctx := context.Background()
+	var created []int
+	for i := 0; i < 5; i++ {
+		created = append(created, create(t, repo, fmt.Sprintf("Page %d", i)).ID)
+	}
+	require.NoError(t, repo.Delete(ctx, created[1]))
+
+	first, err := repo.ListAfter(ctx, 0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{created[0], created[2]}, ids(first))
+
+	rest, err := repo.ListAfter(ctx, created[2], 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int{created[3], created[4]}, ids(rest))
+
+	none, err := repo.ListAfter(ctx, created[4], 10)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func testList(t *testing.T, repo repository.BlogRepository, _ repository.UnitOfWork) {
+	ctx := context.Background()
+	store := func(title, category string, tags ...string) int {
+		blog := newBlog(title)
+		blog.Category, blog.Tags = category, tags
+		require.NoError(t, repo.Create(ctx, blog))
+		return blog.ID
+	}
+	goTech := store("Go", "Tech", "Go")
+	rustNews := store("Rust", "News", "Go", "Rust")
+	rustTech := store("More Rust", "tech", "rust")
+
+	tests := []struct {
+		name string
+		opts repository.ListOptions
+		want []int
+	}{
+		{"all", repository.ListOptions{}, []int{rustTech, rustNews, goTech}},
+		{"limit", repository.ListOptions{Limit: 2}, []int{rustTech, rustNews}},
+		{"category ignoring case", repository.ListOptions{Category: "TECH"}, []int{rustTech, goTech}},
+		{"tag ignoring case", repository.ListOptions{Tag: "Rust"}, []int{rustTech, rustNews}},
+		{"category and tag", repository.ListOptions{Category: "tech", Tag: "go"}, []int{goTech}},
+		{"no match", repository.ListOptions{Tag: "Python"}, []int{}},
+	}
+	for _, tt := range tests {
+		blogs, err := repo.List(ctx, tt.opts)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, ids(blogs), tt.name)
+	}
+}
+
+func testListGroups(t *testing.T, repo repository.BlogRepository, _ repository.UnitOfWork) {
+	ctx := context.Background()
+	store := func(title, category string, tags ...string) int {
+		blog := newBlog(title)
+		blog.Category, blog.Tags = category, tags
+		require.NoError(t, repo.Create(ctx, blog))
+		return blog.ID
+	}
+	goTech := store("Go", "Tech", "Go")
+	rustNews := store("Rust", "News", "Go", "Rust")
+	rustTech := store("More Rust", "tech", "rust")
+	python := store("Python", "Life", "Python")
+
+	blogs, err := repo.ListGroups(ctx, []repository.ListOptions{
+		{Category: "TECH", Limit: 1},
+		{Tag: "go", Limit: 5},
+		{Tag: "Elixir", Limit: 5},
+	})
+	require.NoError(t, err)
+	// The older Tech blog is beyond its limit, but is listed once as it is tagged Go
+	assert.Equal(t, []int{rustTech, rustNews, goTech}, ids(blogs))
+
+	blogs, err = repo.ListGroups(ctx, []repository.ListOptions{{Category: "tech", Limit: 1}, {Category: "News", Limit: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, []int{rustTech, rustNews}, ids(blogs))
+
+	// More groups than SQLite allows terms in a compound SELECT, with the ones matching blogs at either end
+	many := []repository.ListOptions{{Tag: "Python", Limit: 1}}
+	for i := range 600 {
+		many = append(many, repository.ListOptions{Tag: fmt.Sprintf("Unused %d", i), Limit: 1})
+	}
+	many = append(many, repository.ListOptions{Category: "News", Limit: 1})
+	blogs, err = repo.ListGroups(ctx, many)
+	require.NoError(t, err)
+	assert.Equal(t, []int{python, rustNews}, ids(blogs))
+
+	blogs, err = repo.ListGroups(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, blogs)
+}
+
+func testSearch(t *testing.T, repo repository.BlogRepository, _ repository.UnitOfWork) {
+	ctx := context.Background()
+	store := func(title, category string, tags ...string) int {
+		blog := newBlog(title)
+		blog.Category, blog.Tags = category, tags
+		require.NoError(t, repo.Create(ctx, blog))
+		return blog.ID
+	}
+	goTech := store("Go", "Tech", "Go")
+	rustNews := store("Rust", "News", "Go", "Rust")
+	rustTech := store("More Rust", "tech", "rust")
+	goNews := store("Go again", "News", "Go")
+
+	tests := []struct {
+		name  string
+		opts  repository.SearchOptions
+		want  []int
+		count int
+	}{
+		{"all", repository.SearchOptions{}, []int{goTech, rustNews, rustTech, goNews}, 4},
+		{"limit", repository.SearchOptions{Limit: 2}, []int{goTech, rustNews}, 4},
+		{"offset", repository.SearchOptions{Offset: 3}, []int{goNews}, 4},
+		{"limit and offset", repository.SearchOptions{Limit: 2, Offset: 1}, []int{rustNews, rustTech}, 4},
+		{"offset past the end", repository.SearchOptions{Limit: 2, Offset: 9}, []int{}, 4},
+		{"after ID", repository.SearchOptions{AfterID: rustNews, Limit: 1}, []int{rustTech}, 2},
+		{"term ignoring case", repository.SearchOptions{Term: "RUST"}, []int{rustNews, rustTech}, 2},
+		{"category and tag", repository.SearchOptions{Category: "NEWS", Tag: "go"}, []int{rustNews, goNews}, 2},
+		{"term and tag", repository.SearchOptions{Term: "go", Tag: "Go", Offset: 1}, []int{goNews}, 2},
+	}
+	for _, tt := range tests {
+		blogs, err := repo.Search(ctx, tt.opts)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, ids(blogs), tt.name)
+
+		count, err := repo.Count(ctx, tt.opts)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.count, count, tt.name)
+	}
+
+	blogs, err := repo.Search(ctx, repository.SearchOptions{Term: "again", Fields: []string{repository.FieldTitle}})
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Blog{{Title: "Go again"}}, blogs, "only the selected fields are set")
+}
+
+func testGetAllProjects(t *testing.T, repo repository.BlogRepository, _ repository.UnitOfWork) {
+	ctx := context.Background()
+	blog := create(t, repo, "Projected")
+
+	blogs, err := repo.GetAll(ctx, "projected", repository.FieldTitle, repository.FieldTags)
+	require.NoError(t, err)
+	require.Len(t, blogs, 1)
+	assert.Equal(t, &models.Blog{Title: blog.Title, Tags: blog.Tags}, blogs[0], "only the selected fields are set")
+
+	_, err = repo.GetAll(ctx, "", "author")
+	assert.ErrorContains(t, err, `unknown blog field "author"`)
+}
+
+func testStats(t *testing.T, repo repository.BlogRepository, _ repository.UnitOfWork) {
+	ctx := context.Background()
+	stats, err := repo.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, repository.Stats{}, stats)
+
+	first := create(t, repo, "First")
+	last := create(t, repo, "Last")
+	require.NoError(t, repo.Delete(ctx, first.ID))
+
+	stats, err = repo.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, repository.Stats{Count: 1, MaxID: last.ID}, stats)
+}
+
+func testUpdate(t *testing.T, repo repository.BlogRepository, _ repository.UnitOfWork) {
+	ctx := context.Background()
+	created := create(t, repo, "Original")
+
+	update := &models.Blog{
+		ID: created.ID, Title: "Updated", Content: "New content", ContentHTML: "<p>New content</p>",
+		Category: "News", Tags: []string{"Update"},
+	}
+	require.NoError(t, repo.Update(ctx, update))
+	assert.False(t, update.UpdatedAt.Before(created.UpdatedAt))
+
+	blog, err := repo.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", blog.Title)
+	assert.Equal(t, "New content", blog.Content)
+	assert.Equal(t, "<p>New content</p>", blog.ContentHTML)
+	assert.Equal(t, "News", blog.Category)
+	assert.Equal(t, []string{"Update"}, blog.Tags)
+	assert.True(t, created.CreatedAt.Equal(blog.CreatedAt))
+	assert.True(t, update.UpdatedAt.Equal(blog.UpdatedAt))
+}
+
+func testDelete(t *testing.T, repo repository.BlogRepository, _ repository.UnitOfWork) {
+	ctx := context.Background()
+	kept := create(t, repo, "Kept")
+	deleted := create(t, repo, "Deleted")
+
+	require.NoError(t, repo.Delete(ctx, deleted.ID))
+
+	_, err := repo.GetByID(ctx, deleted.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.ErrorIs(t, repo.Delete(ctx, deleted.ID), sql.ErrNoRows)
+
+	all, err := repo.GetAll(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []int{kept.ID}, ids(all))
+}
+
+func testUnitOfWorkRollback(t *testing.T, repo repository.BlogRepository, uow repository.UnitOfWork) {
+	ctx := context.Background()
+	existing := create(t, repo, "Existing")
+	errAbort := errors.New("abort")
+
+	var created *models.Blog
+	err := uow.WithTx(ctx, func(repos repository.Repositories) error {
+		created = newBlog("Rolled back")
+		if err := repos.Blogs.Create(ctx, created); err != nil {
+			return err
+		}
+		if err := repos.Blogs.Delete(ctx, existing.ID); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+
+	_, err = repo.GetByID(ctx, created.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = repo.GetByID(ctx, existing.ID)
+	assert.NoError(t, err)
+
+	// A successful unit of work commits every change.
+	err = uow.WithTx(ctx, func(repos repository.Repositories) error {
+		return repos.Blogs.Delete(ctx, existing.ID)
+	})
+	require.NoError(t, err)
+	_, err = repo.GetByID(ctx, existing.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testConcurrentCreates(t *testing.T, repo repository.BlogRepository, _ repository.UnitOfWork) {
+	const writers = 20
+
+	var wg sync.WaitGroup
+	blogs := make([]*models.Blog, writers)
+	for i := range blogs {
+		blogs[i] = newBlog("Concurrent")
+		wg.Add(1)
+		go func(blog *models.Blog) {
+			defer wg.Done()
+			assert.NoError(t, repo.Create(context.Background(), blog))
+		}(blogs[i])
+	}
+	wg.Wait()
+
+	seen := make(map[int]bool)
+	for _, blog := range blogs {
+		assert.False(t, seen[blog.ID], "duplicate ID %d", blog.ID)
+		seen[blog.ID] = true
+	}
+
+	all, err := repo.GetAll(context.Background(), "concurrent")
+	require.NoError(t, err)
+	assert.Len(t, all, writers)
+}
+
+// ids returns the IDs of blogs in order.
+func ids(blogs []*models.Blog) []int {
+	result := make([]int, len(blogs))
+	for i, blog := range blogs {
+		result[i] = blog.ID
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"bloggingplatformapi/internal/logging"
+	"bloggingplatformapi/pkg/db"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Repositories groups the repositories that take part in a unit of work.
+// Inside UnitOfWork.WithTx every repository runs its queries in the same transaction.
+type Repositories struct {
+	Blogs BlogRepository // Blog operations
+}
+
+// UnitOfWork runs several repository operations atomically.
+type UnitOfWork interface {
+	// WithTx calls fn with repositories bound to a new transaction, which is committed if fn returns nil
+	// and rolled back otherwise. If the transaction fails because it conflicted with a concurrent one,
+	// fn is called again in a fresh transaction, so it must not have side effects outside the database.
+	WithTx(ctx context.Context, fn func(repos Repositories) error) error
+}
+
+// DefaultTxRetryPolicy runs a conflicting transaction up to five times.
+var DefaultTxRetryPolicy = db.RetryPolicy{
+	Attempts: 5,
+	Backoff:  db.Backoff{Initial: 10 * time.Millisecond, Max: 200 * time.Millisecond},
+}
+
+// unitOfWork is a concrete implementation of the UnitOfWork interface.
+type unitOfWork struct {
+	router    *db.Router         // Transactions run on the primary
+	blogs     *blogRepository    // Template for the transactional blog repository
+	retry     db.RetryPolicy     // Retry policy for serialization failures
+	isolation sql.IsolationLevel // Isolation level of every transaction
+}
+
+// NewUnitOfWork creates a UnitOfWork whose transactions run on the primary of router at the serializable
+// isolation level, retried with DefaultTxRetryPolicy on serialization failures and deadlocks.
+func NewUnitOfWork(router *db.Router) UnitOfWork {
+	return &unitOfWork{
+		router:    router,
+		blogs:     &blogRepository{router: router, dialect: dialectFor(router.Driver())},
+		retry:     DefaultTxRetryPolicy,
+		isolation: sql.LevelSerializable,
+	}
+}
+
+// WithTx implements UnitOfWork.
+func (u *unitOfWork) WithTx(ctx context.Context, fn func(repos Repositories) error) error {
+	for attempt := 1; ; attempt++ {
+		err := u.run(ctx, fn)
+		if err == nil {
+			u.router.RecordWrite(ctx)
+			return nil
+		}
+		if !db.IsSerializationFailure(err) || attempt >= u.retry.Attempts {
+			return err
+		}
+
+		logging.FromContext(ctx).Debugf("Retrying transaction after serialization failure (attempt %d): %v", attempt, err)
+		select {
+		case <-time.After(u.retry.Backoff.Delay(attempt)):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// run executes fn in a single transaction.
+func (u *unitOfWork) run(ctx context.Context, fn func(repos Repositories) error) (err error) {
+	tx, err := u.router.Primary().BeginTx(ctx, &sql.TxOptions{Isolation: u.isolation})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// Roll back on errors and panics; a panic is re-raised once the transaction is closed
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil && !errors.Is(rollbackErr, sql.ErrTxDone) {
+				logging.FromContext(ctx).Errorf("error rolling back transaction: %v", rollbackErr)
+			}
+		}
+	}()
+
+	if err = fn(Repositories{Blogs: u.blogs.withTx(tx)}); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/pkg/db"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupUnitOfWork initializes a unit of work on a mock database with fast retries.
+func setupUnitOfWork(t *testing.T) (sqlmock.Sqlmock, UnitOfWork) {
+	t.Helper()
+	database, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = database.Close() })
+
+	uow := NewUnitOfWork(db.NewRouter(database, nil, db.RouterOptions{})).(*unitOfWork)
+	uow.retry.Backoff = db.Backoff{Initial: time.Millisecond}
+	return mock, uow
+}
+
+// expectUpdateAndSelect expects the statements of an update followed by a lookup of the same blog.
+func expectUpdateAndSelect(mock sqlmock.Sqlmock, updateErr error) {
+	update := mock.ExpectQuery(`UPDATE blogs SET (.+) WHERE id = \$6 RETURNING updated_at`).
+		WithArgs("Updated Title", "Updated Content", "", "Tech", pq.Array([]string{"Go"}), 1)
+	if updateErr != nil {
+		update.WillReturnError(updateErr)
+		return
+	}
+	update.WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(mockTimeNow()))
+
+	mock.ExpectQuery(`SELECT (.+) FROM blogs WHERE id = \$1`).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "content_html", "category", "tags", "created_at", "updated_at"}).
+			AddRow(1, "Updated Title", "Updated Content", "", "Tech", "{Go}", mockTimeNow(), mockTimeNow()))
+}
+
+// updateAndGet updates the test blog and reads it back within repos.
+func updateAndGet(ctx context.Context, repos Repositories) (*models.Blog, error) {
+	blog := &models.Blog{ID: 1, Title: "Updated Title", Content: "Updated Content", Category: "Tech", Tags: []string{"Go"}}
+	if err := repos.Blogs.Update(ctx, blog); err != nil {
+		return nil, err
+	}
+	return repos.Blogs.GetByID(ctx, blog.ID)
+}
+
+func TestUnitOfWork_Commit(t *testing.T) {
+	t.Parallel()
+
+	mock, uow := setupUnitOfWork(t)
+	mock.ExpectBegin()
+	expectUpdateAndSelect(mock, nil)
+	mock.ExpectCommit()
+
+	var updated *models.Blog
+	err := uow.WithTx(context.Background(), func(repos Repositories) (err error) {
+		updated, err = updateAndGet(context.Background(), repos)
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Updated Title", updated.Title)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitOfWork_RollbackOnError(t *testing.T) {
+	t.Parallel()
+
+	mock, uow := setupUnitOfWork(t)
+	mock.ExpectBegin()
+	expectUpdateAndSelect(mock, errors.New("constraint violated"))
+	mock.ExpectRollback()
+
+	err := uow.WithTx(context.Background(), func(repos Repositories) error {
+		_, err := updateAndGet(context.Background(), repos)
+		return err
+	})
+	assert.EqualError(t, err, "constraint violated")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitOfWork_RetriesSerializationFailures(t *testing.T) {
+	t.Parallel()
+
+	mock, uow := setupUnitOfWork(t)
+
+	// The first attempt conflicts on update, the second on commit, the third succeeds.
+	mock.ExpectBegin()
+	expectUpdateAndSelect(mock, &pq.Error{Code: "40001"})
+	mock.ExpectRollback()
+
+	mock.ExpectBegin()
+	expectUpdateAndSelect(mock, nil)
+	mock.ExpectCommit().WillReturnError(&pq.Error{Code: "40P01"})
+
+	mock.ExpectBegin()
+	expectUpdateAndSelect(mock, nil)
+	mock.ExpectCommit()
+
+	calls := 0
+	err := uow.WithTx(context.Background(), func(repos Repositories) error {
+		calls++
+		_, err := updateAndGet(context.Background(), repos)
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitOfWork_GivesUpAfterRetries(t *testing.T) {
+	t.Parallel()
+
+	mock, uow := setupUnitOfWork(t)
+	uow.(*unitOfWork).retry.Attempts = 2
+	for i := 0; i < 2; i++ {
+		mock.ExpectBegin()
+		expectUpdateAndSelect(mock, &pq.Error{Code: "40001"})
+		mock.ExpectRollback()
+	}
+
+	err := uow.WithTx(context.Background(), func(repos Repositories) error {
+		_, err := updateAndGet(context.Background(), repos)
+		return err
+	})
+	assert.True(t, db.IsSerializationFailure(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitOfWork_RollbackOnPanic(t *testing.T) {
+	t.Parallel()
+
+	mock, uow := setupUnitOfWork(t)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = uow.WithTx(context.Background(), func(Repositories) error { panic("boom") })
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
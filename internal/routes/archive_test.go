@@ -0,0 +1,186 @@
+package routes
+
+import (
+	"archive/zip"
+	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/internal/services"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// importResponse is the body of a POST /blogs/import response.
+type importResponse struct {
+	DryRun   bool                  `json:"dryRun"`
+	Imported int                   `json:"imported"`
+	Results  []models.ImportResult `json:"results"`
+}
+
+// postImport sends an import request and decodes the response.
+func postImport(t *testing.T, router *gin.Engine, query, contentType string, body []byte) (int, importResponse) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/blogs/import"+query, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var response importResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response), rec.Body.String())
+	return rec.Code, response
+}
+
+// importStatuses returns the status of every import result in order.
+func importStatuses(results []models.ImportResult) []int {
+	codes := make([]int, len(results))
+	for i, result := range results {
+		codes[i] = result.Status
+	}
+	return codes
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{"jsonl", "zip"} {
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			source, _ := newTestRouter(t, Dependencies{}, existingBlog())
+			rec := httptest.NewRecorder()
+			source.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/blogs/export?format="+format, nil))
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Header().Get("Content-Disposition"), `filename="blogs.`+format+`"`)
+
+			target, blogs := newTestRouter(t, Dependencies{}, existingBlog())
+			code, response := postImport(t, target, "?format="+format, rec.Header().Get("Content-Type"), rec.Body.Bytes())
+			assert.Equal(t, http.StatusOK, code)
+			assert.Equal(t, 1, response.Imported)
+			assert.Equal(t, []int{http.StatusCreated}, importStatuses(response.Results))
+
+			imported, err := blogs.GetByID(context.Background(), response.Results[0].ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Existing", imported.Title)
+			assert.Equal(t, []string{"Go"}, imported.Tags)
+		})
+	}
+}
+
+func TestExport_RejectsUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	router, _ := newTestRouter(t, Dependencies{}, existingBlog())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/blogs/export?format=csv", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestImport_DryRunReportsInvalidItems(t *testing.T) {
+	t.Parallel()
+
+	router, blogs := newTestRouter(t, Dependencies{}, existingBlog())
+	body := newBlogJSON + "\n" + `{"title":"No tags","content":"Content","category":"Tech"}` + "\n{\n"
+	code, response := postImport(t, router, "?dry_run=true&mode=best-effort", "application/x-ndjson", []byte(body))
+
+	assert.Equal(t, http.StatusMultiStatus, code)
+	assert.True(t, response.DryRun)
+	assert.Zero(t, response.Imported)
+	assert.Equal(t, []int{http.StatusOK, http.StatusBadRequest, http.StatusBadRequest}, importStatuses(response.Results))
+	assert.Equal(t, "line 2", response.Results[1].Source)
+	assert.Equal(t, "at least one tag is required", response.Results[1].Error)
+
+	all, err := blogs.GetAll(context.Background(), "")
+	require.NoError(t, err)
+	assert.Len(t, all, 1, "a dry run must not store anything")
+}
+
+func TestImport_AtomicRejectsInvalidArchive(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"blogs/valid.md":   "---\ntitle: Valid\ncategory: Tech\ntags: [Go]\n---\n\nBody\n",
+		"blogs/invalid.md": "No front matter",
+	} {
+		f, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	router, blogs := newTestRouter(t, Dependencies{}, existingBlog())
+	code, response := postImport(t, router, "", "application/zip", buf.Bytes())
+
+	assert.Equal(t, http.StatusBadRequest, code)
+	require.Len(t, response.Results, 2)
+	assert.ElementsMatch(t, []int{http.StatusFailedDependency, http.StatusBadRequest}, importStatuses(response.Results))
+
+	all, err := blogs.GetAll(context.Background(), "")
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
+func TestImport_RejectsEmptyArchive(t *testing.T) {
+	t.Parallel()
+
+	router, _ := newTestRouter(t, Dependencies{}, existingBlog())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/blogs/import", strings.NewReader("\n\n")))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestImport_RejectsArchiveWithTooManyItems(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i := range services.MaxBatchOperations + 1 {
+		f, err := zw.Create(fmt.Sprintf("blogs/%d.md", i))
+		require.NoError(t, err)
+		_, err = f.Write([]byte("---\ntitle: Valid\ncategory: Tech\ntags: [Go]\n---\n\nBody\n"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	router, blogs := newTestRouter(t, Dependencies{}, existingBlog())
+	code, _ := postImport(t, router, "", "application/zip", buf.Bytes())
+	assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+
+	all, err := blogs.GetAll(context.Background(), "")
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
+func TestImport_KeepsTimestampsOnlyWhenAsked(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	body := `{"title":"Old","content":"Content","category":"Tech","tags":["Go"],` +
+		`"createdAt":"2020-01-02T03:04:05Z","updatedAt":"2020-01-03T03:04:05Z"}` + "\n"
+
+	for _, keep := range []bool{false, true} {
+		t.Run(fmt.Sprint(keep), func(t *testing.T) {
+			t.Parallel()
+
+			router, blogs := newTestRouter(t, Dependencies{}, existingBlog())
+			code, response := postImport(t, router, fmt.Sprintf("?keep_timestamps=%t", keep), "application/x-ndjson", []byte(body))
+			require.Equal(t, http.StatusOK, code)
+
+			imported, err := blogs.GetByID(context.Background(), response.Results[0].ID)
+			require.NoError(t, err)
+			assert.Equal(t, keep, created.Equal(imported.CreatedAt))
+			assert.Equal(t, keep, created.Add(24*time.Hour).Equal(imported.UpdatedAt))
+		})
+	}
+}
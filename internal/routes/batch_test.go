@@ -0,0 +1,140 @@
+package routes
+
+import (
+	"bloggingplatformapi/internal/models"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postBatch sends a batch request and decodes the per-operation results.
+func postBatch(t *testing.T, router *gin.Engine, query, body string) (int, []models.BatchResult) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/blogs:batch"+query, strings.NewReader(body)))
+
+	var response struct {
+		Results []models.BatchResult `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response), rec.Body.String())
+	return rec.Code, response.Results
+}
+
+// statuses returns the status of every result in order.
+func statuses(results []models.BatchResult) []int {
+	codes := make([]int, len(results))
+	for i, result := range results {
+		codes[i] = result.Status
+	}
+	return codes
+}
+
+const newBlogJSON = `{"title":"New","content":"Content","category":"Tech","tags":["Go"]}`
+
+func TestBatch_Atomic(t *testing.T) {
+	t.Parallel()
+
+	existing := existingBlog()
+	router, blogs := newTestRouter(t, Dependencies{}, existing)
+	code, results := postBatch(t, router, "", `[
+		{"op":"create","blog":`+newBlogJSON+`},
+		{"op":"create","blog":`+newBlogJSON+`},
+		{"op":"update","id":1,"blog":{"title":"Updated","content":"Content","category":"Tech","tags":["Go"]}},
+		{"op":"delete","id":1}
+	]`)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []int{http.StatusCreated, http.StatusCreated, http.StatusOK, http.StatusNoContent}, statuses(results))
+	assert.Equal(t, 2, results[0].ID)
+	assert.Equal(t, "Updated", results[2].Blog.Title)
+
+	_, err := blogs.GetByID(context.Background(), existing.ID)
+	assert.Error(t, err)
+	all, err := blogs.GetAll(context.Background(), "")
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
+
+func TestBatch_AtomicRollsBackOnFailure(t *testing.T) {
+	t.Parallel()
+
+	router, blogs := newTestRouter(t, Dependencies{}, existingBlog())
+	code, results := postBatch(t, router, "?mode=atomic", `[
+		{"op":"create","blog":`+newBlogJSON+`},
+		{"op":"delete","id":1},
+		{"op":"delete","id":42}
+	]`)
+
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, []int{http.StatusFailedDependency, http.StatusFailedDependency, http.StatusNotFound}, statuses(results))
+
+	all, err := blogs.GetAll(context.Background(), "")
+	require.NoError(t, err)
+	assert.Len(t, all, 1, "nothing may be applied")
+}
+
+func TestBatch_AtomicRejectsInvalidOperations(t *testing.T) {
+	t.Parallel()
+
+	router, blogs := newTestRouter(t, Dependencies{}, existingBlog())
+	code, results := postBatch(t, router, "", `[
+		{"op":"create","blog":`+newBlogJSON+`},
+		{"op":"create","blog":{"title":"No content","category":"Tech","tags":["Go"]}},
+		{"op":"rename","id":1}
+	]`)
+
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, []int{http.StatusFailedDependency, http.StatusBadRequest, http.StatusBadRequest}, statuses(results))
+	assert.Equal(t, "content is required", results[1].Error)
+
+	all, err := blogs.GetAll(context.Background(), "")
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
+func TestBatch_BestEffort(t *testing.T) {
+	t.Parallel()
+
+	router, blogs := newTestRouter(t, Dependencies{}, existingBlog())
+	code, results := postBatch(t, router, "?mode=best-effort", `[
+		{"op":"create","blog":`+newBlogJSON+`},
+		{"op":"update","id":1},
+		{"op":"delete","id":42},
+		{"op":"delete","id":1}
+	]`)
+
+	assert.Equal(t, http.StatusMultiStatus, code)
+	assert.Equal(t, []int{http.StatusCreated, http.StatusBadRequest, http.StatusNotFound, http.StatusNoContent}, statuses(results))
+
+	all, err := blogs.GetAll(context.Background(), "")
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, results[0].ID, all[0].ID)
+}
+
+func TestBatch_RejectsUnknownMethods(t *testing.T) {
+	t.Parallel()
+
+	router, _ := newTestRouter(t, Dependencies{}, existingBlog())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/blogs:purge", strings.NewReader(`[]`)))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestBatch_IgnoresTimestampsOfCreates(t *testing.T) {
+	t.Parallel()
+
+	router, _ := newTestRouter(t, Dependencies{}, existingBlog())
+	code, results := postBatch(t, router, "",
+		`[{"op":"create","blog":{"title":"New","content":"Content","category":"Tech","tags":["Go"],"createdAt":"2020-01-02T03:04:05Z"}}]`)
+	require.Equal(t, http.StatusOK, code)
+	assert.True(t, results[0].Blog.CreatedAt.After(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)))
+}
@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"bloggingplatformapi/internal/config"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/cors"
+)
+
+// CORSHandler applies the configured cross-origin policy to every request.
+// The policy is held behind an atomic pointer so it can be replaced without restarting the server.
+type CORSHandler struct {
+	policy atomic.Pointer[cors.Cors]
+}
+
+// NewCORSHandler creates a CORSHandler enforcing the CORS settings in cfg.
+func NewCORSHandler(cfg *config.Config) *CORSHandler {
+	h := &CORSHandler{}
+	h.Update(cfg)
+	return h
+}
+
+// Update atomically replaces the policy with the CORS settings in cfg.
+func (h *CORSHandler) Update(cfg *config.Config) {
+	options := cors.Options{
+		AllowedOrigins:   cfg.CORSAllowedOrigins,
+		AllowedMethods:   cfg.CORSAllowedMethods,
+		AllowedHeaders:   cfg.CORSAllowedHeaders,
+		ExposedHeaders:   cfg.CORSExposedHeaders,
+		MaxAge:           int(cfg.CORSMaxAge.Seconds()),
+		AllowCredentials: cfg.CORSAllowCredentials,
+	}
+	// rs/cors treats an empty origin list as "allow all"; an empty list here means no cross-origin access.
+	if len(cfg.CORSAllowedOrigins) == 0 {
+		options.AllowOriginFunc = func(string) bool { return false }
+	}
+	h.policy.Store(cors.New(options))
+}
+
+// Middleware returns a Gin-compatible CORS middleware.
+// Preflight requests are answered here and never reach the route handlers.
+func (h *CORSHandler) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy := h.policy.Load()
+		if isPreflight(c.Request) {
+			policy.HandlerFunc(c.Writer, c.Request) // Writes the preflight headers and status
+			c.Abort()
+			return
+		}
+
+		policy.HandlerFunc(c.Writer, c.Request)
+		c.Next()
+	}
+}
+
+// isPreflight reports whether r is a CORS preflight request.
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+}
@@ -0,0 +1,109 @@
+package routes
+
+import (
+	"bloggingplatformapi/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupCORSRouter creates a router with a single route behind the CORS middleware.
+// The returned counter records how many requests reached the route handler.
+func setupCORSRouter(cfg *config.Config) (*gin.Engine, *int) {
+	gin.SetMode(gin.TestMode)
+	handled := 0
+
+	router := gin.New()
+	router.Use(NewCORSHandler(cfg).Middleware())
+	router.PUT("/api/v1/blogs/:blogId", func(c *gin.Context) {
+		handled++
+		c.Status(http.StatusOK)
+	})
+	return router, &handled
+}
+
+// testCORSConfig returns a policy allowing a single origin.
+func testCORSConfig() *config.Config {
+	return &config.Config{
+		CORSAllowedOrigins: []string{"https://blog.example.com"},
+		CORSAllowedMethods: []string{"GET", "PUT", "PATCH"},
+		CORSAllowedHeaders: []string{"Content-Type", "Authorization"},
+		CORSExposedHeaders: []string{"X-Request-ID"},
+		CORSMaxAge:         10 * time.Minute,
+	}
+}
+
+func preflight(origin, method string) *http.Request {
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/blogs/1", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	req.Header.Set("Access-Control-Request-Headers", "authorization") // Browsers send lowercase names
+	return req
+}
+
+func TestCORS_PreflightAllowedOrigin(t *testing.T) {
+	t.Parallel()
+
+	router, handled := setupCORSRouter(testCORSConfig())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, preflight("https://blog.example.com", http.MethodPatch))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://blog.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "PATCH", rec.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "authorization", rec.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+	assert.Zero(t, *handled, "preflight must not reach the route handler")
+}
+
+func TestCORS_PreflightDeniedOrigin(t *testing.T) {
+	t.Parallel()
+
+	router, handled := setupCORSRouter(testCORSConfig())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, preflight("https://evil.example.com", http.MethodPut))
+
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Zero(t, *handled)
+}
+
+func TestCORS_ActualRequest(t *testing.T) {
+	t.Parallel()
+
+	router, handled := setupCORSRouter(testCORSConfig())
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/blogs/1", nil)
+	req.Header.Set("Origin", "https://blog.example.com")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "https://blog.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Request-Id", rec.Header().Get("Access-Control-Expose-Headers"))
+	assert.Equal(t, 1, *handled)
+
+	// Requests from other origins are served without CORS headers, so browsers block the response.
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/blogs/1", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_NoOriginsConfigured(t *testing.T) {
+	t.Parallel()
+
+	cfg := testCORSConfig()
+	cfg.CORSAllowedOrigins = nil
+	router, _ := setupCORSRouter(cfg)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, preflight("https://blog.example.com", http.MethodPut))
+
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
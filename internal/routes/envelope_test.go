@@ -0,0 +1,112 @@
+package routes
+
+import (
+	"bloggingplatformapi/internal/models"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// envelopeBody is an enveloped response with blogs as its data.
+type envelopeBody struct {
+	Data []models.Blog `json:"data"`
+	Meta struct {
+		Version    string `json:"version"`
+		Pagination *struct {
+			Total, Count, Limit, Offset int
+		} `json:"pagination"`
+	} `json:"meta"`
+	Links struct {
+		Self, Next, Prev string
+	} `json:"links"`
+	Errors []struct {
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// getEnvelope requests path and decodes the enveloped response.
+func getEnvelope(t *testing.T, path string, status int) (envelopeBody, string) {
+	t.Helper()
+	router, blogs := newTestRouter(t, Dependencies{}, existingBlog())
+	for _, title := range []string{"Second", "Third"} {
+		require.NoError(t, blogs.Create(context.Background(), &models.Blog{Title: title, Content: "Content", Category: "Tech", Tags: []string{"Go"}}))
+	}
+
+	rec := send(router, http.MethodGet, path, "", "", nil)
+	require.Equal(t, status, rec.Code, rec.Body.String())
+	var body envelopeBody
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body, rec.Body.String()
+}
+
+func TestEnvelope_PaginatesWithLinks(t *testing.T) {
+	t.Parallel()
+
+	first, _ := getEnvelope(t, "/api/v2/blogs?limit=2", http.StatusOK)
+	assert.Equal(t, "2", first.Meta.Version)
+	require.Len(t, first.Data, 2)
+	assert.Equal(t, "Existing", first.Data[0].Title)
+	require.NotNil(t, first.Meta.Pagination)
+	assert.Equal(t, 3, first.Meta.Pagination.Total)
+	assert.Equal(t, 2, first.Meta.Pagination.Count)
+	assert.Equal(t, "/api/v2/blogs?limit=2", first.Links.Self)
+	assert.Equal(t, "/api/v2/blogs?limit=2&offset=2", first.Links.Next)
+	assert.Empty(t, first.Links.Prev)
+
+	last, _ := getEnvelope(t, "/api/v2/blogs?limit=2&offset=2", http.StatusOK)
+	require.Len(t, last.Data, 1)
+	assert.Equal(t, "Third", last.Data[0].Title)
+	assert.Empty(t, last.Links.Next)
+	assert.Equal(t, "/api/v2/blogs?limit=2&offset=0", last.Links.Prev)
+}
+
+func TestEnvelope_PaginatesAfterID(t *testing.T) {
+	t.Parallel()
+
+	page, _ := getEnvelope(t, "/api/v2/blogs?after=1&limit=1", http.StatusOK)
+	require.Len(t, page.Data, 1)
+	assert.Equal(t, "Second", page.Data[0].Title)
+	require.NotNil(t, page.Meta.Pagination)
+	assert.Equal(t, 2, page.Meta.Pagination.Total, "the total counts the blogs after the ID")
+	assert.Equal(t, "/api/v2/blogs?after=1&limit=1&offset=1", page.Links.Next)
+
+	invalid, _ := getEnvelope(t, "/api/v2/blogs?after=-1", http.StatusBadRequest)
+	require.Len(t, invalid.Errors, 1)
+	assert.Equal(t, "after must be a non-negative integer", invalid.Errors[0].Message)
+}
+
+func TestEnvelope_EmptyListsAndErrors(t *testing.T) {
+	t.Parallel()
+
+	_, raw := getEnvelope(t, "/api/v2/blogs?term=nothing", http.StatusOK)
+	assert.Contains(t, raw, `"data":[]`)
+
+	router, _ := newTestRouter(t, Dependencies{}, existingBlog())
+	rec := send(router, http.MethodGet, "/api/v1/blogs?term=nothing", "", "", nil)
+	assert.Equal(t, "[]", rec.Body.String(), "version 1 sends an empty array rather than null")
+
+	missing, raw := getEnvelope(t, "/api/v2/blogs/999", http.StatusNotFound)
+	assert.NotContains(t, raw, `"data"`)
+	require.Len(t, missing.Errors, 1)
+	assert.Equal(t, http.StatusNotFound, missing.Errors[0].Status)
+	assert.Equal(t, "Blog not found", missing.Errors[0].Message)
+	assert.Equal(t, "/api/v2/blogs/999", missing.Links.Self)
+
+	invalid, _ := getEnvelope(t, "/api/v2/blogs?limit=-1", http.StatusBadRequest)
+	assert.Contains(t, invalid.Errors[0].Message, "limit must be between 1 and 1000")
+}
+
+func TestEnvelope_XML(t *testing.T) {
+	t.Parallel()
+
+	router, _ := newTestRouter(t, Dependencies{}, existingBlog())
+	rec := send(router, http.MethodGet, "/api/v2/blogs", "", "application/xml", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "<response><items><blog><id>1</id>")
+	assert.Contains(t, rec.Body.String(), "<meta><version>2</version>")
+}
@@ -0,0 +1,123 @@
+package routes
+
+import (
+	"bloggingplatformapi/internal/feed"
+	"bloggingplatformapi/internal/models"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// feedDeps configures the feeds of the routes under test.
+var feedDeps = Dependencies{Feed: feed.Site{Title: "Blog", URL: "https://example.com"}}
+
+// feedBlogs returns blogs in two categories to seed the routes under test with.
+func feedBlogs() []*models.Blog {
+	return []*models.Blog{
+		{Title: "Go", Content: "About *Go*", Category: "Tech", Tags: []string{"Go"}},
+		{Title: "Rust", Content: "About Rust", Category: "Tech", Tags: []string{"Rust"}},
+		{Title: "Elections", Content: "News", Category: "News", Tags: []string{"Politics"}},
+	}
+}
+
+// getFeed requests a feed with the given request headers.
+func getFeed(router *gin.Engine, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// itemTitles returns the titles of the items of an RSS document.
+func itemTitles(t *testing.T, body []byte) []string {
+	t.Helper()
+	var doc struct {
+		Titles []string `xml:"channel>item>title"`
+	}
+	require.NoError(t, xml.Unmarshal(body, &doc))
+	return doc.Titles
+}
+
+func TestFeeds_FilterNewestFirst(t *testing.T) {
+	t.Parallel()
+
+	router, _ := newTestRouter(t, feedDeps, feedBlogs()...)
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/feeds/rss.xml", []string{"Elections", "Rust", "Go"}},
+		{"/feeds/categories/tech/rss.xml", []string{"Rust", "Go"}},
+		{"/feeds/tags/go/rss.xml", []string{"Go"}},
+		{"/feeds/tags/unknown/rss.xml", nil},
+	}
+	for _, tt := range tests {
+		rec := getFeed(router, tt.path, nil)
+		require.Equal(t, http.StatusOK, rec.Code, tt.path)
+		assert.Equal(t, feed.RSSContentType, rec.Header().Get("Content-Type"))
+		assert.Equal(t, tt.want, itemTitles(t, rec.Body.Bytes()), tt.path)
+	}
+
+	rec := getFeed(router, "/feeds/categories/Tech/atom.xml", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, feed.AtomContentType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `<link href="https://example.com/feeds/categories/Tech/atom.xml" rel="self"`)
+	assert.Contains(t, rec.Body.String(), `&lt;p&gt;About &lt;em&gt;Go&lt;/em&gt;&lt;/p&gt;`)
+}
+
+func TestFeeds_JSONFeed(t *testing.T) {
+	t.Parallel()
+
+	router, _ := newTestRouter(t, feedDeps, feedBlogs()...)
+	rec := getFeed(router, "/feeds/tags/rust/feed.json", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, feed.JSONContentType, rec.Header().Get("Content-Type"))
+	assert.NotEmpty(t, rec.Header().Get("ETag"))
+
+	var doc struct {
+		Title   string `json:"title"`
+		FeedURL string `json:"feed_url"`
+		Items   []struct {
+			URL         string   `json:"url"`
+			ContentHTML string   `json:"content_html"`
+			Tags        []string `json:"tags"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "Blog: posts tagged rust", doc.Title)
+	assert.Equal(t, "https://example.com/feeds/tags/rust/feed.json", doc.FeedURL)
+	require.Len(t, doc.Items, 1)
+	assert.Equal(t, "https://example.com/api/v1/blogs/2", doc.Items[0].URL)
+	assert.Equal(t, "<p>About Rust</p>\n", doc.Items[0].ContentHTML)
+	assert.Equal(t, []string{"Tech", "Rust"}, doc.Items[0].Tags)
+}
+
+func TestFeeds_ConditionalGet(t *testing.T) {
+	t.Parallel()
+
+	router, _ := newTestRouter(t, feedDeps, feedBlogs()...)
+	first := getFeed(router, "/feeds/atom.xml", nil)
+	require.Equal(t, http.StatusOK, first.Code)
+	etag, lastModified := first.Header().Get("ETag"), first.Header().Get("Last-Modified")
+	require.NotEmpty(t, etag)
+	require.NotEmpty(t, lastModified)
+
+	rec := getFeed(router, "/feeds/atom.xml", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	rec = getFeed(router, "/feeds/atom.xml", map[string]string{"If-Modified-Since": lastModified})
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	rec = getFeed(router, "/feeds/atom.xml", map[string]string{"If-None-Match": `"stale"`})
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,58 @@
+package routes
+
+import (
+	"bloggingplatformapi/internal/models"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFields_ListsOnlyRequestedFields(t *testing.T) {
+	t.Parallel()
+	existing := existingBlog()
+	router, _ := newTestRouter(t, Dependencies{}, existing)
+
+	rec := send(router, http.MethodGet, "/api/v1/blogs?fields=id,title,tags", "", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var blogs []map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &blogs))
+	require.Len(t, blogs, 1)
+	assert.Equal(t, map[string]any{"id": float64(existing.ID), "title": "Existing", "tags": []any{"Go"}}, blogs[0])
+}
+
+func TestFields_Excerpt(t *testing.T) {
+	t.Parallel()
+	router, _ := newTestRouter(t, Dependencies{ExcerptLength: 20},
+		&models.Blog{Title: "Long", Content: "# Heading\n\n" + strings.Repeat("word ", 10), Category: "Tech"})
+
+	rec := send(router, http.MethodGet, "/api/v1/blogs/1?fields=excerpt", "", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, map[string]any{"excerpt": "Heading word word…"}, body)
+}
+
+func TestFields_PaginatesSparseListsInEnvelope(t *testing.T) {
+	t.Parallel()
+	body, _ := getEnvelope(t, "/api/v2/blogs?fields=title&limit=1&offset=1", http.StatusOK)
+	require.Len(t, body.Data, 1)
+	assert.Equal(t, "Second", body.Data[0].Title)
+	assert.Zero(t, body.Data[0].ID)
+	require.NotNil(t, body.Meta.Pagination)
+	assert.Equal(t, 3, body.Meta.Pagination.Total)
+}
+
+func TestFields_RejectsUnknownFields(t *testing.T) {
+	t.Parallel()
+	router, _ := newTestRouter(t, Dependencies{}, existingBlog())
+
+	for _, fields := range []string{"id,author", ""} {
+		rec := send(router, http.MethodGet, "/api/v1/blogs?fields="+fields, "", "", nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, fields)
+		assert.Contains(t, rec.Body.String(), "fields must be", fields)
+	}
+}
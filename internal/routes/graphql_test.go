@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQL_QueriesAndMutatesOverPOST(t *testing.T) {
+	t.Parallel()
+	existing := existingBlog()
+	router, blogs := newTestRouter(t, Dependencies{}, existing)
+
+	rec := send(router, http.MethodPost, "/graphql", "application/json", "",
+		strings.NewReader(`{"query":"mutation($id: Int!) { updateBlog(id: $id, input: {title: \"Renamed\", content: \"Content\", category: \"Tech\", tags: [\"Go\"]}) { title } }","variables":{"id":1}}`))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.JSONEq(t, `{"data":{"updateBlog":{"title":"Renamed"}}}`, rec.Body.String())
+
+	stored, err := blogs.GetByID(context.Background(), existing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", stored.Title)
+}
+
+func TestGraphQL_QueriesOverGET(t *testing.T) {
+	t.Parallel()
+	router, _ := newTestRouter(t, Dependencies{}, existingBlog())
+
+	query := url.Values{"query": {`query($tag: String) { blogs(tag: $tag) { total items { title excerpt } } }`}, "variables": {`{"tag":"go"}`}}
+	rec := send(router, http.MethodGet, "/graphql?"+query.Encode(), "", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.JSONEq(t, `{"data":{"blogs":{"total":1,"items":[{"title":"Existing","excerpt":"Content"}]}}}`, rec.Body.String())
+
+	mutation := url.Values{"query": {`mutation { deleteBlog(id: 1) }`}}
+	rec = send(router, http.MethodGet, "/graphql?"+mutation.Encode(), "", "", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, http.MethodPost, rec.Header().Get("Allow"))
+}
+
+func TestGraphQL_RejectsMalformedRequests(t *testing.T) {
+	t.Parallel()
+	router, _ := newTestRouter(t, Dependencies{}, existingBlog())
+
+	for _, body := range []string{`{"query":`, `{"variables":{}}`} {
+		rec := send(router, http.MethodPost, "/graphql", "application/json", "", strings.NewReader(body))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+}
@@ -0,0 +1,142 @@
+package routes
+
+import (
+	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/internal/utils"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+	"gopkg.in/yaml.v3"
+)
+
+// send makes a request with the given Content-Type and Accept headers; empty ones are left out.
+func send(router *gin.Engine, method, path, contentType, accept string, body io.Reader) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, body)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNegotiation_ResponseFormats(t *testing.T) {
+	t.Parallel()
+
+	existing := existingBlog()
+	router, _ := newTestRouter(t, Dependencies{}, existing)
+	decoders := map[string]func([]byte, any) error{
+		utils.MIMEJSON: json.Unmarshal,
+		utils.MIMEXML:  xml.Unmarshal,
+		utils.MIMEYAML: yaml.Unmarshal,
+		utils.MIMEMsgPack: func(data []byte, v any) error {
+			return codec.NewDecoderBytes(data, &codec.MsgpackHandle{}).Decode(v)
+		},
+	}
+	tests := []struct {
+		accept string
+		format string
+	}{
+		{"", utils.MIMEJSON},
+		{"*/*", utils.MIMEJSON},
+		{"text/html, application/xml;q=0.9", utils.MIMEXML},
+		{"text/xml", utils.MIMEXML},
+		{"application/yaml", utils.MIMEYAML},
+		{"application/x-msgpack", utils.MIMEMsgPack},
+	}
+	for _, tt := range tests {
+		rec := send(router, http.MethodGet, "/api/v1/blogs/1", "", tt.accept, nil)
+		require.Equal(t, http.StatusOK, rec.Code, tt.accept)
+		assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), tt.format), tt.accept)
+		assert.Contains(t, rec.Header().Values("Vary"), "Accept")
+
+		var blog models.Blog
+		require.NoError(t, decoders[tt.format](rec.Body.Bytes(), &blog), tt.accept)
+		assert.Equal(t, existing.Title, blog.Title, tt.accept)
+		assert.Equal(t, []string{"Go"}, blog.Tags, tt.accept)
+		assert.True(t, existing.CreatedAt.Equal(blog.CreatedAt), tt.accept)
+	}
+}
+
+func TestNegotiation_XMLListsHaveRootElement(t *testing.T) {
+	t.Parallel()
+
+	router, _ := newTestRouter(t, Dependencies{}, existingBlog())
+	rec := send(router, http.MethodGet, "/api/v1/blogs", "", utils.MIMEXML, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var doc struct {
+		XMLName xml.Name      `xml:"items"`
+		Blogs   []models.Blog `xml:"blog"`
+	}
+	require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &doc), rec.Body.String())
+	require.Len(t, doc.Blogs, 1)
+	assert.Equal(t, "Existing", doc.Blogs[0].Title)
+}
+
+func TestNegotiation_RejectsUnacceptableTypes(t *testing.T) {
+	t.Parallel()
+
+	router, _ := newTestRouter(t, Dependencies{}, existingBlog())
+	rec := send(router, http.MethodGet, "/api/v1/blogs/1", "", "text/html", nil)
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), utils.MIMEJSON)
+	assert.Contains(t, rec.Body.String(), "Accept must allow one of")
+
+	// The export chooses its format from a query parameter, not the Accept header.
+	rec = send(router, http.MethodGet, "/api/v1/blogs/export", "", "application/x-ndjson", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestNegotiation_DecodesRequestBodies(t *testing.T) {
+	t.Parallel()
+
+	var msgpack []byte
+	require.NoError(t, codec.NewEncoderBytes(&msgpack, &codec.MsgpackHandle{WriteExt: true}).Encode(map[string]any{
+		"title": "MessagePack", "content": "Binary", "category": "Tech", "tags": []string{"Mobile"},
+	}))
+	tests := []struct {
+		contentType string
+		body        string
+		title       string
+	}{
+		{"", `{"title":"JSON","content":"Text","category":"Tech","tags":["Go"]}`, "JSON"},
+		{"application/xml", `<blog><title>XML</title><content>Text</content><category>Tech</category><tags><tag>Legacy</tag></tags></blog>`, "XML"},
+		{"application/x-yaml; charset=utf-8", "title: YAML\ncontent: Text\ncategory: Tech\ntags: [Config]\n", "YAML"},
+		{"application/msgpack", string(msgpack), "MessagePack"},
+	}
+	router, _ := newTestRouter(t, Dependencies{}, existingBlog())
+	for _, tt := range tests {
+		rec := send(router, http.MethodPost, "/api/v1/blogs", tt.contentType, "", strings.NewReader(tt.body))
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		var created models.Blog
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+		assert.Equal(t, tt.title, created.Title)
+		assert.Len(t, created.Tags, 1)
+	}
+
+	batch := `<operations><operation><op>delete</op><id>1</id></operation>` +
+		`<operation><op>create</op><blog><title>New</title><content>Text</content><category>Tech</category><tags><tag>Go</tag></tags></blog></operation></operations>`
+	rec := send(router, http.MethodPost, "/api/v1/blogs:batch", utils.MIMEXML, utils.MIMEXML, strings.NewReader(batch))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "<status>204</status>")
+	assert.Contains(t, rec.Body.String(), "<title>New</title>")
+
+	rec = send(router, http.MethodPost, "/api/v1/blogs", "text/plain", "", bytes.NewBufferString("title: Plain"))
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Content-Type must be one of")
+}
@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_HTMLOnRequest(t *testing.T) {
+	t.Parallel()
+
+	router, _ := newTestRouter(t, Dependencies{}, existingBlog())
+	body := `{"title":"Post","content":"Hello *world*<script>alert(1)</script>","category":"Tech","tags":["Go"]}`
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/blogs?render=html", strings.NewReader(body)))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var created map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "<p>Hello <em>world</em></p>\n", created["contentHtml"])
+	assert.Equal(t, "Hello *world*<script>alert(1)</script>", created["content"], "content is returned as written")
+
+	// Without render=html the rendering is left out.
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/blogs/2", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "contentHtml")
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/blogs?render=html", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var listed []map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Len(t, listed, 2)
+	assert.Equal(t, "<p>Content</p>\n", listed[0]["contentHtml"])
+}
+
+func TestRender_RejectsUnknownMode(t *testing.T) {
+	t.Parallel()
+
+	router, _ := newTestRouter(t, Dependencies{}, existingBlog())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/blogs/1?render=pdf", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
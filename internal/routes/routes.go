@@ -1,59 +1,169 @@
 package routes
 
 import (
+	"bloggingplatformapi/internal/cache"
 	"bloggingplatformapi/internal/controllers"
+	"bloggingplatformapi/internal/feed"
+	"bloggingplatformapi/internal/graph"
+	"bloggingplatformapi/internal/health"
+	"bloggingplatformapi/internal/ratelimit"
 	"bloggingplatformapi/internal/repository"
 	"bloggingplatformapi/internal/services"
+	"bloggingplatformapi/internal/utils"
+	"bloggingplatformapi/pkg/db"
 	"database/sql"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/rs/cors"
 )
 
+// Dependencies groups the components the application routes are built from.
+type Dependencies struct {
+	DB       *sql.DB    // Database connection
+	DBRouter *db.Router // Optional read replica routing; without it every query uses DB
+
+	BlogService    services.BlogService      // Optional service shared with other servers; built by NewBlogService without it
+	BlogRepository repository.BlogRepository // Optional storage used instead of DB, e.g. in memory
+	UnitOfWork     repository.UnitOfWork     // Transactions on BlogRepository; required with it
+	CORS           *CORSHandler              // Optional cross-origin policy
+	RateLimiter    *ratelimit.Limiter        // Optional per-client rate limiter
+	Cache          cache.Cache               // Optional cache for blog lookups and listings
+	CacheTTL       time.Duration             // Lifetime of cached entries (0 keeps them until evicted)
+	Feed           feed.Site                 // Site metadata shown in the feeds and sitemaps
+	FeedMaxItems   int                       // Number of posts in each feed (0 for feed.DefaultMaxItems)
+	SitemapMaxURLs int                       // Number of posts in each sitemap (0 for sitemap.MaxURLs)
+	ExcerptLength  int                       // Characters in sparse fieldset excerpts (0 for markdown.DefaultExcerptLength)
+
+	GraphQLMaxDepth      int // Deepest GraphQL query accepted (0 for graph.DefaultMaxDepth)
+	GraphQLMaxComplexity int // Most complex GraphQL query accepted (0 for graph.DefaultMaxComplexity)
+}
+
 // SetupRoutes initializes all application routes.
-func SetupRoutes(router *gin.Engine, db *sql.DB) {
+func SetupRoutes(router *gin.Engine, deps Dependencies) {
 	// Apply CORS middleware
-	router.Use(createCORSHandler())
+	if deps.CORS != nil {
+		router.Use(deps.CORS.Middleware())
+	}
 
 	// Setup blog module dependencies
-	blogController := initializeBlogController(db)
+	blogService := deps.BlogService
+	if blogService == nil {
+		blogService = NewBlogService(deps)
+	}
+	blogController := controllers.NewBlogController(blogService, deps.ExcerptLength)
+	feedController := controllers.NewFeedController(blogService, deps.Feed, deps.FeedMaxItems)
+	sitemapController := controllers.NewSitemapController(blogService, deps.Feed, deps.SitemapMaxURLs)
+	graphQLController := controllers.NewGraphQLController(
+		graph.NewServer(blogService, deps.ExcerptLength, deps.GraphQLMaxDepth, deps.GraphQLMaxComplexity))
+	limits := newRouteLimits(deps.RateLimiter)
+
+	// Define API routes; queries are tagged with the client so it reads its own writes.
+	// Version 2 serves the same routes with every response wrapped in an envelope.
+	setupBlogRoutes(router.Group("/api/v1", readYourWrites()), blogController, limits)
+	setupBlogRoutes(router.Group("/api/v2", readYourWrites(), utils.WithEnvelope()), blogController, limits)
+
+	// Feeds are served at the root path, where feed readers expect them
+	setupFeedRoutes(router.Group("/feeds"), feedController, limits)
+	setupSitemapRoutes(router, sitemapController, limits)
 
-	// Define API routes
-	api := router.Group("/api/v1")
-	setupBlogRoutes(api, blogController)
+	// GraphQL over the same service, so it shares the cache with the REST API
+	setupGraphQLRoutes(router.Group("/graphql", readYourWrites()), graphQLController, limits)
 }
 
-// createCORSHandler creates and returns a Gin-compatible CORS middleware.
-func createCORSHandler() gin.HandlerFunc {
-	corsMiddleware := cors.New(cors.Options{
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE"},
-		AllowedHeaders:   []string{"Origin", "Content-Type"},
-		AllowCredentials: true,
-	})
+// SetupHealthRoutes registers the liveness, readiness and detailed health endpoints at the root path.
+func SetupHealthRoutes(router *gin.Engine, checker *health.Checker) {
+	healthController := controllers.NewHealthController(checker)
+
+	router.GET("/healthz", healthController.Liveness) // Process is alive
+	router.GET("/readyz", healthController.Readiness) // Ready to serve traffic
+	router.GET("/health", healthController.Health)    // Detailed per-check status
+}
+
+// NewBlogService sets up the blog service shared by the controllers from the storage and cache in deps.
+// With a cache, the service is wrapped so lookups and listings are served from it. Servers other than the
+// router, such as the gRPC server, share the service by passing it back in deps.BlogService.
+func NewBlogService(deps Dependencies) services.BlogService {
+	blogRepo, unitOfWork := deps.BlogRepository, deps.UnitOfWork
+	var routing services.ReplicaRouting // Only routed storage reads from replicas
+	if blogRepo == nil {
+		dbRouter := deps.DBRouter
+		if dbRouter == nil {
+			dbRouter = db.NewRouter(deps.DB, nil, db.RouterOptions{})
+		}
+		blogRepo = repository.NewRoutedBlogRepository(dbRouter)
+		unitOfWork = repository.NewUnitOfWork(dbRouter)
+		routing = dbRouter
+	}
+	blogService := services.NewBlogService(blogRepo, unitOfWork)
+	if deps.Cache != nil {
+		blogService = services.NewCachedBlogService(blogService, deps.Cache, deps.CacheTTL, routing)
+	}
+	return blogService
+}
+
+// readYourWrites identifies the client in the request context, so the database router can send its reads
+// to the primary for a short time after it writes.
+func readYourWrites() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		corsMiddleware.HandlerFunc(c.Writer, c.Request)
+		c.Request = c.Request.WithContext(db.WithClient(c.Request.Context(), utils.ClientKey(c)))
 		c.Next()
 	}
 }
 
-// initializeBlogController sets up the blog controller with its dependencies.
-func initializeBlogController(db *sql.DB) *controllers.BlogController {
-	blogRepo := repository.NewBlogRepository(db)      // Initialize the repository
-	blogService := services.NewBlogService(blogRepo)  // Initialize the service
-	return controllers.NewBlogController(blogService) // Initialize the controller
+// customMethods dispatches a custom method such as POST /blogs:batch to its handler.
+// Gin cannot route a literal colon, so the route captures it in the method parameter.
+func customMethods(handlers map[string]gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handler, ok := handlers[c.Param("method")]
+		if !ok {
+			utils.RespondWithError(c, http.StatusNotFound, "Not found")
+			return
+		}
+		handler(c)
+	}
+}
+
+// routeLimits holds the rate limiting middleware for each route group.
+type routeLimits struct {
+	read  gin.HandlerFunc
+	write gin.HandlerFunc
+}
+
+// newRouteLimits creates the per-group rate limiting middleware; without a limiter requests pass straight through.
+func newRouteLimits(limiter *ratelimit.Limiter) routeLimits {
+	if limiter == nil {
+		next := func(c *gin.Context) { c.Next() }
+		return routeLimits{read: next, write: next}
+	}
+	return routeLimits{
+		read:  limiter.Middleware(ratelimit.GroupRead),
+		write: limiter.Middleware(ratelimit.GroupWrite),
+	}
 }
 
 // setupBlogRoutes configures routes for blog-related operations.
-func setupBlogRoutes(api *gin.RouterGroup, blogController *controllers.BlogController) {
+func setupBlogRoutes(api *gin.RouterGroup, blogController *controllers.BlogController, limits routeLimits) {
+	// The export is an archive whose format is chosen by a query parameter, so it is not negotiated
+	api.GET("/blogs/export", limits.read, blogController.ExportBlogs) // Export all blogs as an archive
+
+	// Other responses are JSON, XML, YAML or MessagePack, as the Accept header asks
+	api = api.Group("", utils.Negotiate())
 	blogs := api.Group("/blogs")
 	{
-		blogs.GET("", blogController.GetAllBlogs)           // List all blogs
-		blogs.POST("", blogController.CreateBlog)           // Create a new blog
-		blogs.GET("/:blogId", blogController.GetBlog)       // Get a specific blog
-		blogs.PUT("/:blogId", blogController.UpdateBlog)    // Update a specific blog
-		blogs.DELETE("/:blogId", blogController.DeleteBlog) // Delete a specific blog
+		blogs.GET("", limits.read, blogController.GetAllBlogs)            // List all blogs
+		blogs.POST("", limits.write, blogController.CreateBlog)           // Create a new blog
+		blogs.POST("/import", limits.write, blogController.ImportBlogs)   // Import blogs from an archive
+		blogs.GET("/:blogId", limits.read, blogController.GetBlog)        // Get a specific blog
+		blogs.PUT("/:blogId", limits.write, blogController.UpdateBlog)    // Update a specific blog
+		blogs.DELETE("/:blogId", limits.write, blogController.DeleteBlog) // Delete a specific blog
 	}
 
+	// Custom methods on the collection, e.g. POST /blogs:batch
+	api.POST("/blogs:method", limits.write, customMethods(map[string]gin.HandlerFunc{
+		":batch": blogController.Batch, // Create, update and delete several blogs at once
+	}))
+
 	// Additional routes for future scalability (example: tags)
 	//api.GET("/tags", blogController.GetAllTags)                   // List all tags
 	//api.GET("/tags/:tagName/blogs", blogController.GetBlogsByTag) // Get all blogs for a specific tag
@@ -63,3 +173,28 @@ func setupBlogRoutes(api *gin.RouterGroup, blogController *controllers.BlogContr
 	//api.GET("/authors/:authorId", blogController.GetAuthorDetails)       // Get author details
 	//api.GET("/authors/:authorId/blogs", blogController.GetBlogsByAuthor) // Get all blogs by an author
 }
+
+// setupFeedRoutes configures the RSS, Atom and JSON feeds of all posts and of each category and tag.
+func setupFeedRoutes(feeds *gin.RouterGroup, feedController *controllers.FeedController, limits routeLimits) {
+	feeds.GET("/rss.xml", limits.read, feedController.RSS)                         // All posts as RSS 2.0
+	feeds.GET("/atom.xml", limits.read, feedController.Atom)                       // All posts as Atom 1.0
+	feeds.GET("/feed.json", limits.read, feedController.JSON)                      // All posts as JSON Feed 1.1
+	feeds.GET("/categories/:category/rss.xml", limits.read, feedController.RSS)    // Posts in a category as RSS 2.0
+	feeds.GET("/categories/:category/atom.xml", limits.read, feedController.Atom)  // Posts in a category as Atom 1.0
+	feeds.GET("/categories/:category/feed.json", limits.read, feedController.JSON) // Posts in a category as JSON Feed 1.1
+	feeds.GET("/tags/:tag/rss.xml", limits.read, feedController.RSS)               // Posts with a tag as RSS 2.0
+	feeds.GET("/tags/:tag/atom.xml", limits.read, feedController.Atom)             // Posts with a tag as Atom 1.0
+	feeds.GET("/tags/:tag/feed.json", limits.read, feedController.JSON)            // Posts with a tag as JSON Feed 1.1
+}
+
+// setupSitemapRoutes configures the sitemap of every post at the root path, where search engines expect it.
+func setupSitemapRoutes(router *gin.Engine, sitemapController *controllers.SitemapController, limits routeLimits) {
+	router.GET("/sitemap.xml", limits.read, sitemapController.Sitemap)        // Sitemap, or sitemap index beyond SitemapMaxURLs posts
+	router.GET("/sitemaps/:name", limits.read, sitemapController.SitemapPage) // Page of the sitemap index, e.g. blogs-2.xml
+}
+
+// setupGraphQLRoutes configures the GraphQL endpoint. A POST may hold a mutation, so it counts as a write.
+func setupGraphQLRoutes(graphql *gin.RouterGroup, graphQLController *controllers.GraphQLController, limits routeLimits) {
+	graphql.GET("", limits.read, graphQLController.Query)     // Queries in the query string
+	graphql.POST("", limits.write, graphQLController.Execute) // Queries and mutations in a JSON body
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/internal/repository"
+	"context"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRouter creates the application routes with deps over an in-memory repository holding seed, created in
+// order so the first gets ID 1. The repository, which replaces any in deps, is returned for tests to inspect.
+func newTestRouter(t *testing.T, deps Dependencies, seed ...*models.Blog) (*gin.Engine, *repository.MemoryBlogRepository) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	blogs := repository.NewMemoryBlogRepository()
+	for _, blog := range seed {
+		require.NoError(t, blogs.Create(context.Background(), blog))
+	}
+	deps.BlogRepository, deps.UnitOfWork = blogs, blogs.UnitOfWork()
+
+	router := gin.New()
+	SetupRoutes(router, deps)
+	return router, blogs
+}
+
+// existingBlog returns the blog most tests seed their router with.
+func existingBlog() *models.Blog {
+	return &models.Blog{Title: "Existing", Content: "Content", Category: "Tech", Tags: []string{"Go"}}
+}
@@ -0,0 +1,91 @@
+package routes
+
+import (
+	"bloggingplatformapi/internal/feed"
+	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/internal/sitemap"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sitemapDeps configures the routes under test to split sitemaps beyond maxURLs posts.
+func sitemapDeps(maxURLs int) Dependencies {
+	return Dependencies{
+		Feed:           feed.Site{Title: "Blog", URL: "https://example.com", PostURL: "https://example.com/posts/{id}"},
+		SitemapMaxURLs: maxURLs,
+	}
+}
+
+// sitemapBlogs returns five blogs to seed the routes under test with; the tests delete the second.
+func sitemapBlogs() []*models.Blog {
+	blogs := make([]*models.Blog, 5)
+	for i := range blogs {
+		blogs[i] = &models.Blog{Title: fmt.Sprintf("Post %d", i+1), Content: "Content", Category: "Tech", Tags: []string{}}
+	}
+	return blogs
+}
+
+// sitemapDoc holds the locations listed by a sitemap or sitemap index.
+type sitemapDoc struct {
+	XMLName  xml.Name
+	URLs     []string `xml:"url>loc"`
+	LastMods []string `xml:"url>lastmod"`
+	Sitemaps []string `xml:"sitemap>loc"`
+}
+
+// getSitemap requests a sitemap and decodes it.
+func getSitemap(t *testing.T, router *gin.Engine, path string) sitemapDoc {
+	t.Helper()
+	rec := getFeed(router, path, nil)
+	require.Equal(t, http.StatusOK, rec.Code, path)
+	assert.Equal(t, sitemap.ContentType, rec.Header().Get("Content-Type"))
+
+	var doc sitemapDoc
+	require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &doc), path)
+	return doc
+}
+
+func TestSitemap_ListsEveryPost(t *testing.T) {
+	t.Parallel()
+
+	router, blogs := newTestRouter(t, sitemapDeps(0), sitemapBlogs()...)
+	require.NoError(t, blogs.Delete(context.Background(), 2))
+	doc := getSitemap(t, router, "/sitemap.xml")
+	assert.Equal(t, "urlset", doc.XMLName.Local)
+	assert.Equal(t, []string{
+		"https://example.com/posts/1", "https://example.com/posts/3",
+		"https://example.com/posts/4", "https://example.com/posts/5",
+	}, doc.URLs)
+	assert.Len(t, doc.LastMods, 4)
+}
+
+func TestSitemap_SplitsIntoIndex(t *testing.T) {
+	t.Parallel()
+
+	router, blogs := newTestRouter(t, sitemapDeps(2), sitemapBlogs()...)
+	require.NoError(t, blogs.Delete(context.Background(), 2))
+	index := getSitemap(t, router, "/sitemap.xml")
+	assert.Equal(t, "sitemapindex", index.XMLName.Local)
+	assert.Equal(t, []string{
+		"https://example.com/sitemaps/blogs-1.xml",
+		"https://example.com/sitemaps/blogs-2.xml",
+		"https://example.com/sitemaps/blogs-3.xml",
+	}, index.Sitemaps)
+
+	// Pages cover fixed ID ranges, so deleting post 2 leaves page 1 short rather than shifting later posts.
+	assert.Equal(t, []string{"https://example.com/posts/1"}, getSitemap(t, router, "/sitemaps/blogs-1.xml").URLs)
+	assert.Equal(t, []string{"https://example.com/posts/3", "https://example.com/posts/4"},
+		getSitemap(t, router, "/sitemaps/blogs-2.xml").URLs)
+	assert.Equal(t, []string{"https://example.com/posts/5"}, getSitemap(t, router, "/sitemaps/blogs-3.xml").URLs)
+
+	for _, path := range []string{"/sitemaps/blogs-4.xml", "/sitemaps/blogs-0.xml", "/sitemaps/blogs-01.xml", "/sitemaps/posts.xml"} {
+		assert.Equal(t, http.StatusNotFound, getFeed(router, path, nil).Code, path)
+	}
+}
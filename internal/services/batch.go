@@ -0,0 +1,295 @@
+package services
+
+import (
+	"bloggingplatformapi/internal/logging"
+	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/internal/repository"
+	"bloggingplatformapi/internal/tracing"
+	"bloggingplatformapi/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrBatchAborted is the outcome of operations that were not applied because another operation of
+// the same atomic batch failed.
+var ErrBatchAborted = errors.New("not applied because another operation in the batch failed")
+
+// BatchOutcome is the result of a single batch operation.
+type BatchOutcome struct {
+	Blog *models.Blog // Created or updated blog as stored; nil for deletes and failures
+	Err  error        // Why the operation failed, nil if it succeeded
+}
+
+// BatchError reports the operations that made an atomic batch fail: a single operation, unless a bulk insert
+// of consecutive creates failed without the database saying which of them caused it.
+type BatchError struct {
+	Index int   // Position of the failed operation, or of the first of the failed creates
+	Count int   // Number of operations from Index that failed; 1 if the failed operation is known
+	Err   error // Why they failed
+}
+
+// Error implements error.
+func (e *BatchError) Error() string {
+	if e.Count > 1 {
+		return fmt.Sprintf("batch operations %d to %d failed: %v", e.Index, e.Index+e.Count-1, e.Err)
+	}
+	return fmt.Sprintf("batch operation %d failed: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the error of the failed operation.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// MaxBatchOperations is the most operations RunBatch accepts in a single batch.
+const MaxBatchOperations = 1000
+
+// Errors of RunBatch for batches it rejects as a whole.
+var (
+	ErrEmptyBatch    = errors.New("at least one operation is required")
+	ErrBatchTooLarge = fmt.Errorf("a batch may contain at most %d operations", MaxBatchOperations)
+	ErrInvalidBatch  = errors.New("invalid batch operations")
+)
+
+// InvalidOperationError is the outcome of a batch operation that failed validation.
+type InvalidOperationError struct {
+	Err error // Why the operation is invalid
+}
+
+// Error implements error.
+func (e *InvalidOperationError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the validation error.
+func (e *InvalidOperationError) Unwrap() error {
+	return e.Err
+}
+
+// RunBatch validates ops like the matching single writes, applies the valid ones with service.ApplyBatch and
+// returns one outcome per operation, in order; invalid operations fail with an *InvalidOperationError. Batches
+// that are empty or hold more than MaxBatchOperations fail with ErrEmptyBatch or ErrBatchTooLarge. An atomic
+// batch with an invalid operation is not applied: it fails with ErrInvalidBatch and every valid operation with
+// ErrBatchAborted. Otherwise the error is that of ApplyBatch, whose *BatchError names positions in ops.
+func RunBatch(ctx context.Context, service BlogService, ops []models.BatchOperation, atomic bool) ([]BatchOutcome, error) {
+	if len(ops) == 0 {
+		return nil, ErrEmptyBatch
+	}
+	if len(ops) > MaxBatchOperations {
+		return nil, ErrBatchTooLarge
+	}
+
+	outcomes := make([]BatchOutcome, len(ops))
+	var valid []models.BatchOperation
+	var positions []int // Index in ops of each valid operation
+	for i := range ops {
+		if err := utils.ValidateBatchOperation(&ops[i]); err != nil {
+			outcomes[i].Err = &InvalidOperationError{Err: err}
+			continue
+		}
+		valid = append(valid, ops[i])
+		positions = append(positions, i)
+	}
+	if atomic && len(valid) < len(ops) {
+		for _, i := range positions {
+			outcomes[i].Err = ErrBatchAborted
+		}
+		return outcomes, ErrInvalidBatch
+	}
+	if len(valid) == 0 {
+		return outcomes, nil
+	}
+
+	applied, err := service.ApplyBatch(ctx, valid, atomic)
+	var batchErr *BatchError
+	if err != nil && !errors.As(err, &batchErr) {
+		return nil, err
+	}
+	for k, outcome := range applied {
+		outcomes[positions[k]] = outcome
+	}
+	// Only atomic batches fail with a *BatchError, and they are applied only if every operation is valid, so its
+	// positions in valid are also those in ops.
+	return outcomes, err
+}
+
+// ApplyBatch applies ops, which must be valid, in order and returns one outcome per operation.
+// An atomic batch runs in a single transaction: if an operation fails, nothing is applied, the
+// returned error is a *BatchError naming it and every other outcome is ErrBatchAborted. If a bulk insert fails
+// without the database naming the failing create, every create it held fails with the error instead. Any other
+// error means the transaction itself failed. A best-effort batch applies each operation on its own
+// and reports failures only in the outcomes. Consecutive creates are inserted together. The content of
+// every created or updated blog is rendered first, as for single writes.
+func (s *blogService) ApplyBatch(ctx context.Context, ops []models.BatchOperation, atomic bool) ([]BatchOutcome, error) {
+	ctx, span := tracing.Start(ctx, "BlogService.ApplyBatch",
+		attribute.Int("batch.size", len(ops)), attribute.Bool("batch.atomic", atomic))
+	defer span.End()
+
+	for _, op := range ops {
+		if op.Blog != nil {
+			if err := renderContent(op.Blog); err != nil {
+				return nil, tracing.RecordError(span, err)
+			}
+		}
+	}
+
+	if !atomic {
+		outcomes := s.applyBestEffort(ctx, ops)
+		logging.FromContext(ctx).WithField("operations", len(ops)).Debug("Batch applied")
+		return outcomes, nil
+	}
+
+	var outcomes []BatchOutcome
+	err := s.uow.WithTx(ctx, func(repos repository.Repositories) error {
+		outcomes = make([]BatchOutcome, len(ops)) // Reset when a conflicting transaction is retried
+		return applyAtomic(ctx, repos.Blogs, ops, outcomes)
+	})
+	if err != nil {
+		var batchErr *BatchError
+		if !errors.As(err, &batchErr) {
+			return nil, tracing.RecordError(span, err)
+		}
+		for i := range outcomes {
+			outcomes[i] = BatchOutcome{Err: ErrBatchAborted}
+		}
+		for i := batchErr.Index; i < batchErr.Index+batchErr.Count; i++ {
+			outcomes[i].Err = batchErr.Err
+		}
+		return outcomes, tracing.RecordError(span, err)
+	}
+	logging.FromContext(ctx).WithField("operations", len(ops)).Debug("Batch applied")
+	return outcomes, nil
+}
+
+// applyAtomic applies ops with repo, which runs in a transaction, stopping at the first failure.
+func applyAtomic(ctx context.Context, repo repository.BlogRepository, ops []models.BatchOperation, outcomes []BatchOutcome) error {
+	for i := 0; i < len(ops); {
+		if ops[i].Op == models.BatchCreate {
+			end := createRunEnd(ops, i)
+			blogs := blogsToCreate(ops[i:end])
+			if err := repo.CreateMany(ctx, blogs); err != nil {
+				var createErr *repository.CreateManyError
+				if errors.As(err, &createErr) {
+					return &BatchError{Index: i + createErr.Index, Count: 1, Err: createErr.Err}
+				}
+				return &BatchError{Index: i, Count: end - i, Err: err}
+			}
+			for k, blog := range blogs {
+				outcomes[i+k].Blog = blog
+			}
+			i = end
+			continue
+		}
+
+		blog, err := applyOne(ctx, repo, ops[i])
+		if err != nil {
+			return &BatchError{Index: i, Count: 1, Err: err}
+		}
+		outcomes[i].Blog = blog
+		i++
+	}
+	return nil
+}
+
+// applyBestEffort applies each operation independently. Consecutive creates are first inserted in
+// a single transaction; if that fails, they are retried one by one so only the failing ones are lost.
+func (s *blogService) applyBestEffort(ctx context.Context, ops []models.BatchOperation) []BatchOutcome {
+	outcomes := make([]BatchOutcome, len(ops))
+	for i := 0; i < len(ops); {
+		if ops[i].Op != models.BatchCreate {
+			outcomes[i] = s.applyAlone(ctx, ops[i])
+			i++
+			continue
+		}
+
+		end := createRunEnd(ops, i)
+		var blogs []*models.Blog
+		err := s.uow.WithTx(ctx, func(repos repository.Repositories) error {
+			blogs = blogsToCreate(ops[i:end])
+			return repos.Blogs.CreateMany(ctx, blogs)
+		})
+		if err != nil {
+			logging.FromContext(ctx).Warnf("Bulk insert of batch operations %d to %d failed, inserting one by one: %v", i, end-1, err)
+			for k := i; k < end; k++ {
+				outcomes[k] = s.applyAlone(ctx, ops[k])
+			}
+		} else {
+			for k, blog := range blogs {
+				outcomes[i+k].Blog = blog
+			}
+		}
+		i = end
+	}
+	return outcomes
+}
+
+// applyAlone applies a single operation through the service, in its own transaction where needed.
+func (s *blogService) applyAlone(ctx context.Context, op models.BatchOperation) BatchOutcome {
+	var blog models.Blog
+	var err error
+	switch op.Op {
+	case models.BatchCreate:
+		blog = *blogToCreate(op)
+		err = s.create(ctx, &blog)
+	case models.BatchUpdate:
+		blog = *op.Blog
+		blog.ID = op.ID
+		err = s.UpdateBlog(ctx, &blog)
+	case models.BatchDelete:
+		return BatchOutcome{Err: s.DeleteBlog(ctx, op.ID)}
+	default:
+		err = fmt.Errorf("unknown operation %q", op.Op)
+	}
+	if err != nil {
+		return BatchOutcome{Err: err}
+	}
+	return BatchOutcome{Blog: &blog}
+}
+
+// applyOne applies an update or delete with repo and returns the updated blog as stored.
+func applyOne(ctx context.Context, repo repository.BlogRepository, op models.BatchOperation) (*models.Blog, error) {
+	switch op.Op {
+	case models.BatchUpdate:
+		blog := *op.Blog
+		blog.ID = op.ID
+		if err := repo.Update(ctx, &blog); err != nil {
+			return nil, err
+		}
+		return repo.GetByID(ctx, blog.ID)
+	case models.BatchDelete:
+		return nil, repo.Delete(ctx, op.ID)
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+// createRunEnd returns the index just past the run of create operations starting at start.
+func createRunEnd(ops []models.BatchOperation, start int) int {
+	end := start
+	for end < len(ops) && ops[end].Op == models.BatchCreate {
+		end++
+	}
+	return end
+}
+
+// blogsToCreate returns copies of the blogs of create operations, so a retried transaction starts afresh.
+func blogsToCreate(ops []models.BatchOperation) []*models.Blog {
+	blogs := make([]*models.Blog, len(ops))
+	for i, op := range ops {
+		blogs[i] = blogToCreate(op)
+	}
+	return blogs
+}
+
+// blogToCreate returns a copy of the blog of a create operation, without timestamps unless it keeps them.
+func blogToCreate(op models.BatchOperation) *models.Blog {
+	blog := *op.Blog
+	if !op.KeepTimestamps {
+		blog.CreatedAt, blog.UpdatedAt = time.Time{}, time.Time{}
+	}
+	return &blog
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/internal/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bulkFailingUnitOfWork runs transactions whose CreateMany fails with err, as a bulk insert does when it cannot
+// tell which blog caused the failure.
+type bulkFailingUnitOfWork struct {
+	repository.UnitOfWork
+	err error
+}
+
+func (u bulkFailingUnitOfWork) WithTx(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	return u.UnitOfWork.WithTx(ctx, func(repos repository.Repositories) error {
+		repos.Blogs = bulkFailingRepository{BlogRepository: repos.Blogs, err: u.err}
+		return fn(repos)
+	})
+}
+
+// bulkFailingRepository is a repository whose CreateMany fails with err.
+type bulkFailingRepository struct {
+	repository.BlogRepository
+	err error
+}
+
+func (r bulkFailingRepository) CreateMany(context.Context, []*models.Blog) error {
+	return r.err
+}
+
+// batchOps returns an update of blog 1 followed by creates of blogs with the given titles.
+func batchOps(titles ...string) []models.BatchOperation {
+	ops := []models.BatchOperation{{Op: models.BatchUpdate, ID: 1, Blog: &models.Blog{Title: "Updated", Content: "Content", Category: "Tech"}}}
+	for _, title := range titles {
+		ops = append(ops, models.BatchOperation{Op: models.BatchCreate, Blog: &models.Blog{Title: title, Content: "Content", Category: "Tech"}})
+	}
+	return ops
+}
+
+func TestApplyBatch_NamesFailedCreate(t *testing.T) {
+	t.Parallel()
+	repo := repository.NewMemoryBlogRepository()
+	require.NoError(t, repo.Create(context.Background(), &models.Blog{Title: "Existing", Content: "Content", Category: "Tech"}))
+	service := NewBlogService(repo, repo.UnitOfWork())
+
+	outcomes, err := service.ApplyBatch(context.Background(), batchOps("First", "", "Third"), true)
+
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 2, batchErr.Index, "the second create, after the update")
+	assert.Equal(t, 1, batchErr.Count)
+	for i, outcome := range outcomes {
+		if i == 2 {
+			assert.ErrorContains(t, outcome.Err, "chk_title_length")
+		} else {
+			assert.ErrorIs(t, outcome.Err, ErrBatchAborted, fmt.Sprint(i))
+		}
+	}
+}
+
+func TestApplyBatch_FailsEveryCreateOfAnUnattributedBulkInsert(t *testing.T) {
+	t.Parallel()
+	repo := repository.NewMemoryBlogRepository()
+	require.NoError(t, repo.Create(context.Background(), &models.Blog{Title: "Existing", Content: "Content", Category: "Tech"}))
+	lost := errors.New("connection lost")
+	service := NewBlogService(repo, bulkFailingUnitOfWork{UnitOfWork: repo.UnitOfWork(), err: lost})
+
+	outcomes, err := service.ApplyBatch(context.Background(), batchOps("First", "Second"), true)
+
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, "batch operations 1 to 2 failed: connection lost", batchErr.Error())
+	require.Len(t, outcomes, 3)
+	assert.ErrorIs(t, outcomes[0].Err, ErrBatchAborted)
+	assert.ErrorIs(t, outcomes[1].Err, lost)
+	assert.ErrorIs(t, outcomes[2].Err, lost)
+}
+
+func TestRunBatch_ValidatesBeforeApplying(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	newOps := func() []models.BatchOperation {
+		return []models.BatchOperation{
+			{Op: models.BatchUpdate, ID: 1, Blog: &models.Blog{Title: "Updated", Content: "Content", Category: "Tech", Tags: []string{"Go"}}},
+			{Op: models.BatchCreate, Blog: &models.Blog{Title: "New", Content: "Content", Category: "Tech", Tags: []string{"Go"}}},
+			{Op: models.BatchCreate, Blog: &models.Blog{Title: "Untagged", Content: "Content", Category: "Tech"}},
+			{Op: models.BatchDelete, ID: 99},
+		}
+	}
+	repo := repository.NewMemoryBlogRepository()
+	require.NoError(t, repo.Create(ctx, &models.Blog{Title: "Existing", Content: "Content", Category: "Tech"}))
+	service := NewBlogService(repo, repo.UnitOfWork())
+
+	outcomes, err := RunBatch(ctx, service, newOps(), true)
+	require.ErrorIs(t, err, ErrInvalidBatch)
+	require.Len(t, outcomes, 4)
+	assert.ErrorAs(t, outcomes[2].Err, new(*InvalidOperationError))
+	assert.EqualError(t, outcomes[2].Err, "at least one tag is required")
+	for _, i := range []int{0, 1, 3} {
+		assert.ErrorIs(t, outcomes[i].Err, ErrBatchAborted, fmt.Sprint(i))
+	}
+	stats, err := repo.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Count, "an atomic batch with an invalid operation applies nothing")
+
+	outcomes, err = RunBatch(ctx, service, newOps(), false)
+	require.NoError(t, err)
+	require.Len(t, outcomes, 4)
+	assert.Equal(t, "Updated", outcomes[0].Blog.Title)
+	assert.Equal(t, "New", outcomes[1].Blog.Title)
+	assert.ErrorAs(t, outcomes[2].Err, new(*InvalidOperationError))
+	assert.ErrorIs(t, outcomes[3].Err, sql.ErrNoRows)
+
+	_, err = RunBatch(ctx, service, nil, true)
+	assert.ErrorIs(t, err, ErrEmptyBatch)
+	_, err = RunBatch(ctx, service, make([]models.BatchOperation, MaxBatchOperations+1), true)
+	assert.ErrorIs(t, err, ErrBatchTooLarge)
+}
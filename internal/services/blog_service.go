@@ -1,50 +1,272 @@
 package services
 
 import (
+	"bloggingplatformapi/internal/logging"
+	"bloggingplatformapi/internal/markdown"
 	"bloggingplatformapi/internal/models"
 	"bloggingplatformapi/internal/repository"
+	"bloggingplatformapi/internal/tracing"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // BlogService defines the contract for blog-related operations.
 type BlogService interface {
-	CreateBlog(blog *models.Blog) error
-	GetBlogByID(id int) (*models.Blog, error)
-	GetAllBlogs(term string) ([]*models.Blog, error)
-	UpdateBlog(blog *models.Blog) error
-	DeleteBlog(id int) error
+	CreateBlog(ctx context.Context, blog *models.Blog) error
+	GetBlogByID(ctx context.Context, id int) (*models.Blog, error)
+	GetAllBlogs(ctx context.Context, term string, fields ...string) ([]*models.Blog, error)      // Fetches the blogs matching term with at least the given fields (all if none)
+	SearchBlogs(ctx context.Context, opts repository.SearchOptions) ([]*models.Blog, int, error) // Fetches a page of the blogs matching opts and their total
+	ListBlogs(ctx context.Context, opts repository.ListOptions) ([]*models.Blog, error)          // Fetches the newest blogs matching opts
+	ListBlogGroups(ctx context.Context, groups []repository.ListOptions) ([]*models.Blog, error) // Fetches the newest blogs of several groups at once
+	UpdateBlog(ctx context.Context, blog *models.Blog) error                                     // Updates the blog and refreshes it with the stored state
+	DeleteBlog(ctx context.Context, id int) error
+	ApplyBatch(ctx context.Context, ops []models.BatchOperation, atomic bool) ([]BatchOutcome, error) // Applies several valid operations at once
+	ExportBlogs(ctx context.Context, afterID, lastID int, fn func(*models.Blog) error) error          // Calls fn with the blogs in an ID range in ID order
+	BlogStats(ctx context.Context) (repository.Stats, error)                                          // Counts the blogs and finds the highest ID
 }
 
+// exportPageSize is the number of blogs ExportBlogs reads from the repository at a time.
+const exportPageSize = 500
+
 // blogService implements the BlogService interface.
 type blogService struct {
 	repo repository.BlogRepository
+	uow  repository.UnitOfWork
 }
 
 // NewBlogService creates a new instance of BlogService with the provided repository.
-func NewBlogService(repo repository.BlogRepository) BlogService {
-	return &blogService{repo}
+// Operations made of several statements run atomically through uow.
+func NewBlogService(repo repository.BlogRepository, uow repository.UnitOfWork) BlogService {
+	return &blogService{repo: repo, uow: uow}
+}
+
+// CreateBlog renders the blog's content and delegates its creation to the repository layer.
+// Any timestamps the blog has are replaced by the creation time.
+func (s *blogService) CreateBlog(ctx context.Context, blog *models.Blog) error {
+	blog.CreatedAt, blog.UpdatedAt = time.Time{}, time.Time{} // Assigned by the repository
+	return s.create(ctx, blog)
 }
 
-// CreateBlog delegates the creation of a blog to the repository layer.
-func (s *blogService) CreateBlog(blog *models.Blog) error {
-	return s.repo.Create(blog)
+// create stores a new blog, keeping any timestamps it already has.
+func (s *blogService) create(ctx context.Context, blog *models.Blog) error {
+	ctx, span := tracing.Start(ctx, "BlogService.CreateBlog")
+	defer span.End()
+
+	if err := renderContent(blog); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	if err := s.repo.Create(ctx, blog); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	logging.FromContext(ctx).WithField("blogId", blog.ID).Debug("Blog created")
+	return nil
 }
 
 // GetBlogByID retrieves a single blog by its ID from the repository layer.
-func (s *blogService) GetBlogByID(id int) (*models.Blog, error) {
-	return s.repo.GetByID(id)
+func (s *blogService) GetBlogByID(ctx context.Context, id int) (*models.Blog, error) {
+	ctx, span := tracing.Start(ctx, "BlogService.GetBlogByID", attribute.Int("blog.id", id))
+	defer span.End()
+
+	blog, err := s.repo.GetByID(ctx, id)
+	if err == nil {
+		err = renderMissing(blog)
+	}
+	return blog, tracing.RecordError(span, err)
 }
 
 // GetAllBlogs retrieves all blogs matching the search term from the repository.
-func (s *blogService) GetAllBlogs(term string) ([]*models.Blog, error) {
-	return s.repo.GetAll(term)
+// Only the repository columns holding fields are read; see projection.
+func (s *blogService) GetAllBlogs(ctx context.Context, term string, fields ...string) ([]*models.Blog, error) {
+	fields = projection(fields)
+	ctx, span := tracing.Start(ctx, "BlogService.GetAllBlogs",
+		attribute.String("blog.search_term", term), attribute.StringSlice("blog.fields", fields))
+	defer span.End()
+
+	blogs, err := s.repo.GetAll(ctx, term, fields...)
+	if err == nil && (len(fields) == 0 || slices.Contains(fields, repository.FieldContentHTML)) {
+		err = renderMissing(blogs...)
+	}
+	return blogs, tracing.RecordError(span, err)
+}
+
+// SearchBlogs retrieves a page of the blogs matching opts from the repository, along with the number of matching
+// blogs on every page. The total is counted by a separate query unless the page shows it: a page that is not
+// full and not past the end holds the last matching blogs. As for GetAllBlogs, only the columns holding
+// opts.Fields are read.
+func (s *blogService) SearchBlogs(ctx context.Context, opts repository.SearchOptions) ([]*models.Blog, int, error) {
+	opts.Fields = projection(opts.Fields)
+	ctx, span := tracing.Start(ctx, "BlogService.SearchBlogs",
+		attribute.String("blog.search_term", opts.Term), attribute.StringSlice("blog.fields", opts.Fields),
+		attribute.Int("blog.limit", opts.Limit), attribute.Int("blog.offset", opts.Offset))
+	defer span.End()
+
+	blogs, err := s.repo.Search(ctx, opts)
+	if err != nil {
+		return nil, 0, tracing.RecordError(span, err)
+	}
+	if len(opts.Fields) == 0 || slices.Contains(opts.Fields, repository.FieldContentHTML) {
+		if err := renderMissing(blogs...); err != nil {
+			return nil, 0, tracing.RecordError(span, err)
+		}
+	}
+
+	total := opts.Offset + len(blogs)
+	if (opts.Limit > 0 && len(blogs) == opts.Limit) || (len(blogs) == 0 && opts.Offset > 0) {
+		if total, err = s.repo.Count(ctx, opts); err != nil {
+			return nil, 0, tracing.RecordError(span, err)
+		}
+	}
+	return blogs, total, nil
+}
+
+// ListBlogs retrieves the newest blogs matching opts from the repository.
+func (s *blogService) ListBlogs(ctx context.Context, opts repository.ListOptions) ([]*models.Blog, error) {
+	ctx, span := tracing.Start(ctx, "BlogService.ListBlogs",
+		attribute.String("blog.category", opts.Category), attribute.String("blog.tag", opts.Tag), attribute.Int("blog.limit", opts.Limit))
+	defer span.End()
+
+	blogs, err := s.repo.List(ctx, opts)
+	if err == nil {
+		err = renderMissing(blogs...)
+	}
+	return blogs, tracing.RecordError(span, err)
 }
 
-// UpdateBlog updates an existing blog via the repository layer.
-func (s *blogService) UpdateBlog(blog *models.Blog) error {
-	return s.repo.Update(blog)
+// ListBlogGroups retrieves the newest blogs of each of groups from the repository in few queries, each blog
+// once and newest first; see repository.BlogRepository.ListGroups.
+func (s *blogService) ListBlogGroups(ctx context.Context, groups []repository.ListOptions) ([]*models.Blog, error) {
+	ctx, span := tracing.Start(ctx, "BlogService.ListBlogGroups", attribute.Int("blog.groups", len(groups)))
+	defer span.End()
+
+	blogs, err := s.repo.ListGroups(ctx, groups)
+	if err == nil {
+		err = renderMissing(blogs...)
+	}
+	return blogs, tracing.RecordError(span, err)
+}
+
+// UpdateBlog renders the blog's content, updates the blog via the repository layer and reads it back in
+// the same transaction, so blog reflects exactly what was stored.
+func (s *blogService) UpdateBlog(ctx context.Context, blog *models.Blog) error {
+	ctx, span := tracing.Start(ctx, "BlogService.UpdateBlog", attribute.Int("blog.id", blog.ID))
+	defer span.End()
+
+	if err := renderContent(blog); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	err := s.uow.WithTx(ctx, func(repos repository.Repositories) error {
+		if err := repos.Blogs.Update(ctx, blog); err != nil {
+			return err
+		}
+		updated, err := repos.Blogs.GetByID(ctx, blog.ID)
+		if err != nil {
+			return err
+		}
+		*blog = *updated
+		return nil
+	})
+	if err != nil {
+		return tracing.RecordError(span, err)
+	}
+	logging.FromContext(ctx).WithField("blogId", blog.ID).Debug("Blog updated")
+	return nil
 }
 
 // DeleteBlog removes a blog by its ID using the repository layer.
-func (s *blogService) DeleteBlog(id int) error {
-	return s.repo.Delete(id)
+func (s *blogService) DeleteBlog(ctx context.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "BlogService.DeleteBlog", attribute.Int("blog.id", id))
+	defer span.End()
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return tracing.RecordError(span, err)
+	}
+	logging.FromContext(ctx).WithField("blogId", id).Debug("Blog deleted")
+	return nil
+}
+
+// ExportBlogs calls fn in ascending ID order with every blog whose ID is greater than afterID and, unless lastID
+// is 0, at most lastID. Blogs are read from the repository a page at a time, so memory use does not grow with
+// the number of blogs. It stops at the first error returned by fn.
+func (s *blogService) ExportBlogs(ctx context.Context, afterID, lastID int, fn func(*models.Blog) error) error {
+	ctx, span := tracing.Start(ctx, "BlogService.ExportBlogs",
+		attribute.Int("blog.after_id", afterID), attribute.Int("blog.last_id", lastID))
+	defer span.End()
+
+	exported := 0
+	for {
+		page, err := s.repo.ListAfter(ctx, afterID, exportPageSize)
+		if err != nil {
+			return tracing.RecordError(span, err)
+		}
+		for _, blog := range page {
+			if lastID > 0 && blog.ID > lastID {
+				page = nil // Past the end of the range
+				break
+			}
+			if err := fn(blog); err != nil {
+				return tracing.RecordError(span, err)
+			}
+			exported++
+		}
+		if len(page) < exportPageSize {
+			break
+		}
+		afterID = page[len(page)-1].ID
+	}
+	span.SetAttributes(attribute.Int("blog.count", exported))
+	logging.FromContext(ctx).WithField("count", exported).Debug("Blogs exported")
+	return nil
+}
+
+// BlogStats counts the blogs and finds the highest ID using the repository layer.
+func (s *blogService) BlogStats(ctx context.Context) (repository.Stats, error) {
+	ctx, span := tracing.Start(ctx, "BlogService.BlogStats")
+	defer span.End()
+
+	stats, err := s.repo.Stats(ctx)
+	if err != nil {
+		return repository.Stats{}, tracing.RecordError(span, err)
+	}
+	return stats, nil
+}
+
+// renderContent sets the sanitized HTML rendering of the blog's Markdown content, which is stored with it.
+func renderContent(blog *models.Blog) error {
+	html, err := markdown.Render(blog.Content)
+	if err != nil {
+		return fmt.Errorf("failed to render content: %w", err)
+	}
+	blog.ContentHTML = html
+	return nil
+}
+
+// projection returns the fields to read for a request of fields: sorted, without duplicates, and with the
+// content when the HTML rendering is requested, since blogs stored before it was kept are rendered on read.
+// No fields, for every field, stays empty.
+func projection(fields []string) []string {
+	if len(fields) == 0 {
+		return nil
+	}
+	projected := slices.Clone(fields)
+	if slices.Contains(projected, repository.FieldContentHTML) {
+		projected = append(projected, repository.FieldContent)
+	}
+	slices.Sort(projected)
+	return slices.Compact(projected)
+}
+
+// renderMissing renders the content of blogs stored before renderings were kept, which have none.
+func renderMissing(blogs ...*models.Blog) error {
+	for _, blog := range blogs {
+		if blog.ContentHTML == "" && blog.Content != "" {
+			if err := renderContent(blog); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
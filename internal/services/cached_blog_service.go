@@ -0,0 +1,291 @@
+package services
+
+import (
+	"bloggingplatformapi/internal/cache"
+	"bloggingplatformapi/internal/logging"
+	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/internal/repository"
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache key prefixes. They must not be prefixes of one another, so a list invalidation leaves lookups alone.
+const (
+	blogKeyPrefix = "blog:"  // blog:<id> holds a single blog
+	listKeyPrefix = "blogs:" // blogs:<kind>?<query> hold listings, see searchKey, pageKey, listKey and groupsKey
+)
+
+// ReplicaRouting tells the cache which reads of next may come from a replica lagging behind the primary.
+// *db.Router implements it.
+type ReplicaRouting interface {
+	Sticky(ctx context.Context) bool // Whether the client in ctx has just written, so its reads go to the primary
+	LagWindow() time.Duration        // How long after a write a replica may still serve the data it replaced
+}
+
+// sharedLoadTimeout bounds a load shared by concurrent misses. The load outlives the caller that started it,
+// so the other callers still get its result if that one gives up, and needs a timeout of its own instead.
+const sharedLoadTimeout = 30 * time.Second
+
+// cachedBlogService is a BlogService decorator that caches lookups and listings.
+type cachedBlogService struct {
+	next      BlogService
+	cache     cache.Cache
+	ttl       time.Duration      // Lifetime of cached entries (0 keeps them until evicted)
+	routing   ReplicaRouting     // Routing of the reads of next; nil if they all go to one database
+	loads     singleflight.Group // Collapses concurrent misses for the same key into one load
+	version   atomic.Uint64      // Incremented by every write, so loads that raced with a write are not cached
+	lastWrite atomic.Int64       // Time of the last write in Unix nanoseconds, so lagging replica reads are not cached
+}
+
+// NewCachedBlogService wraps next so that GetBlogByID and GetAllBlogs are served from c for up to ttl.
+// Concurrent misses for the same blog or search term share a single call to next. Creating a blog
+// invalidates every cached listing; updating or deleting one also invalidates its cached lookup.
+// Cache errors are logged and the call falls through to next, so an unavailable cache only costs latency.
+//
+// With replicas, routing keeps the cache from undoing read-your-writes: a client that has just written
+// bypasses the cache and reads the primary, and values loaded within routing.LagWindow() of a write are
+// returned but not cached, since they may come from a replica that has not applied the write yet.
+// routing may be nil if every read goes to the primary.
+func NewCachedBlogService(next BlogService, c cache.Cache, ttl time.Duration, routing ReplicaRouting) BlogService {
+	return &cachedBlogService{next: next, cache: c, ttl: ttl, routing: routing}
+}
+
+// CreateBlog creates the blog and invalidates cached listings, which may now include it.
+func (s *cachedBlogService) CreateBlog(ctx context.Context, blog *models.Blog) error {
+	defer s.invalidate(ctx)
+	return s.next.CreateBlog(ctx, blog)
+}
+
+// GetBlogByID returns the cached blog, loading it from next on a miss.
+func (s *cachedBlogService) GetBlogByID(ctx context.Context, id int) (*models.Blog, error) {
+	return cached(ctx, s, blogKey(id), func(ctx context.Context) (*models.Blog, error) {
+		return s.next.GetBlogByID(ctx, id)
+	})
+}
+
+// GetAllBlogs returns the cached blogs matching term with fields, loading them from next on a miss.
+func (s *cachedBlogService) GetAllBlogs(ctx context.Context, term string, fields ...string) ([]*models.Blog, error) {
+	return cached(ctx, s, searchKey(term, fields), func(ctx context.Context) ([]*models.Blog, error) {
+		return s.next.GetAllBlogs(ctx, term, fields...)
+	})
+}
+
+// searchPage is a page of SearchBlogs as it is cached.
+type searchPage struct {
+	Blogs []*models.Blog // Blogs on the page
+	Total int            // Number of matching blogs on every page
+}
+
+// SearchBlogs returns the cached page of blogs matching opts and their total, loading them from next on a miss.
+func (s *cachedBlogService) SearchBlogs(ctx context.Context, opts repository.SearchOptions) ([]*models.Blog, int, error) {
+	page, err := cached(ctx, s, pageKey(opts), func(ctx context.Context) (searchPage, error) {
+		blogs, total, err := s.next.SearchBlogs(ctx, opts)
+		return searchPage{Blogs: blogs, Total: total}, err
+	})
+	return page.Blogs, page.Total, err
+}
+
+// ListBlogs returns the cached blogs matching opts, loading them from next on a miss.
+func (s *cachedBlogService) ListBlogs(ctx context.Context, opts repository.ListOptions) ([]*models.Blog, error) {
+	return cached(ctx, s, listKey(opts), func(ctx context.Context) ([]*models.Blog, error) {
+		return s.next.ListBlogs(ctx, opts)
+	})
+}
+
+// ListBlogGroups returns the cached blogs of groups, loading them from next on a miss.
+func (s *cachedBlogService) ListBlogGroups(ctx context.Context, groups []repository.ListOptions) ([]*models.Blog, error) {
+	return cached(ctx, s, groupsKey(groups), func(ctx context.Context) ([]*models.Blog, error) {
+		return s.next.ListBlogGroups(ctx, groups)
+	})
+}
+
+// UpdateBlog updates the blog and invalidates its cached lookup and every cached listing.
+func (s *cachedBlogService) UpdateBlog(ctx context.Context, blog *models.Blog) error {
+	defer s.invalidate(ctx, blogKey(blog.ID))
+	return s.next.UpdateBlog(ctx, blog)
+}
+
+// DeleteBlog deletes the blog and invalidates its cached lookup and every cached listing.
+func (s *cachedBlogService) DeleteBlog(ctx context.Context, id int) error {
+	defer s.invalidate(ctx, blogKey(id))
+	return s.next.DeleteBlog(ctx, id)
+}
+
+// ApplyBatch applies the batch and invalidates the cached lookups of updated and deleted blogs
+// and every cached listing.
+func (s *cachedBlogService) ApplyBatch(ctx context.Context, ops []models.BatchOperation, atomic bool) ([]BatchOutcome, error) {
+	var keys []string
+	for _, op := range ops {
+		if op.Op != models.BatchCreate {
+			keys = append(keys, blogKey(op.ID))
+		}
+	}
+	defer s.invalidate(ctx, keys...)
+	return s.next.ApplyBatch(ctx, ops, atomic)
+}
+
+// ExportBlogs reads the blogs in the ID range from next, bypassing the cache.
+func (s *cachedBlogService) ExportBlogs(ctx context.Context, afterID, lastID int, fn func(*models.Blog) error) error {
+	return s.next.ExportBlogs(ctx, afterID, lastID, fn)
+}
+
+// BlogStats reads the statistics from next, bypassing the cache.
+func (s *cachedBlogService) BlogStats(ctx context.Context) (repository.Stats, error) {
+	return s.next.BlogStats(ctx)
+}
+
+// cached returns the value stored under key in the cache of s. On a miss it calls load, stores the encoded
+// result and returns it decoded; concurrent misses for key share the result of a single load, which runs
+// until it completes or sharedLoadTimeout passes even if the caller that started it returns early. Each caller
+// waits for it only as long as its own ctx allows. A client that has just written calls load directly, so it
+// reads its writes from the primary rather than an older value.
+func cached[T any](ctx context.Context, s *cachedBlogService, key string, load func(context.Context) (T, error)) (T, error) {
+	var value T
+	if s.routing != nil && s.routing.Sticky(ctx) {
+		return load(ctx)
+	}
+	log := logging.FromContext(ctx)
+
+	data, ok, err := s.cache.Get(ctx, key)
+	if err != nil {
+		log.Warnf("Could not read %q from the cache: %v", key, err)
+	} else if ok {
+		if err := json.Unmarshal(data, &value); err == nil {
+			return value, nil
+		}
+		log.Warnf("Ignoring undecodable cache entry %q: %v", key, err)
+	}
+
+	// Loads are shared per write version, so a read that starts after a write never gets a value loaded before it
+	version := s.version.Load()
+	results := s.loads.DoChan(key+"@"+strconv.FormatUint(version, 10), func() (any, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sharedLoadTimeout)
+		defer cancel()
+
+		started := time.Now()
+		loaded, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(loaded)
+		if err != nil {
+			return nil, err
+		}
+		s.store(loadCtx, key, data, version, started)
+		return data, nil
+	})
+	select {
+	case <-ctx.Done():
+		return value, ctx.Err()
+	case result := <-results:
+		if result.Err != nil {
+			return value, result.Err
+		}
+		err := json.Unmarshal(result.Val.([]byte), &value) // Every caller decodes its own copy
+		return value, err
+	}
+}
+
+// store caches data loaded from started when the write version was version, unless a write has happened
+// since or the load started so soon after a write that a replica may have served it stale. A write that
+// starts between the two version checks deletes the entry itself, so a stale value never survives.
+func (s *cachedBlogService) store(ctx context.Context, key string, data []byte, version uint64, started time.Time) {
+	if s.version.Load() != version || s.maybeLagging(started) {
+		return
+	}
+	if err := s.cache.Set(ctx, key, data, s.ttl); err != nil {
+		logging.FromContext(ctx).Warnf("Could not write %q to the cache: %v", key, err)
+		return
+	}
+	if s.version.Load() != version {
+		_ = s.cache.Delete(ctx, key)
+	}
+}
+
+// maybeLagging reports whether a read started at started may have been served by a replica that had not
+// applied the last write yet.
+func (s *cachedBlogService) maybeLagging(started time.Time) bool {
+	if s.routing == nil {
+		return false
+	}
+	return started.Before(time.Unix(0, s.lastWrite.Load()).Add(s.routing.LagWindow()))
+}
+
+// invalidate deletes keys and every cached listing. It runs whether or not the write succeeded,
+// since a failed write may still have been applied (e.g. when the commit acknowledgement was lost).
+func (s *cachedBlogService) invalidate(ctx context.Context, keys ...string) {
+	s.lastWrite.Store(time.Now().UnixNano())
+	s.version.Add(1)
+
+	log := logging.FromContext(ctx)
+	if len(keys) > 0 {
+		if err := s.cache.Delete(ctx, keys...); err != nil {
+			log.Errorf("Could not invalidate %v in the cache: %v", keys, err)
+		}
+	}
+	if err := s.cache.DeletePrefix(ctx, listKeyPrefix); err != nil {
+		log.Errorf("Could not invalidate cached blog listings: %v", err)
+	}
+}
+
+// blogKey returns the cache key of the blog with the given ID.
+func blogKey(id int) string {
+	return blogKeyPrefix + strconv.Itoa(id)
+}
+
+// searchKey returns the cache key of the blogs matching a search term with the given fields.
+func searchKey(term string, fields []string) string {
+	query := url.Values{"term": {term}}
+	if fields = projection(fields); len(fields) > 0 {
+		query.Set("fields", strings.Join(fields, ","))
+	}
+	return listKeyPrefix + "search?" + query.Encode()
+}
+
+// pageKey returns the cache key of the page of blogs matching opts.
+func pageKey(opts repository.SearchOptions) string {
+	query := url.Values{
+		"term":     {opts.Term},
+		"category": {opts.Category},
+		"tag":      {opts.Tag},
+		"after":    {strconv.Itoa(opts.AfterID)},
+		"limit":    {strconv.Itoa(opts.Limit)},
+		"offset":   {strconv.Itoa(opts.Offset)},
+	}
+	if fields := projection(opts.Fields); len(fields) > 0 {
+		query.Set("fields", strings.Join(fields, ","))
+	}
+	return listKeyPrefix + "page?" + query.Encode()
+}
+
+// listKey returns the cache key of the blogs matching opts.
+func listKey(opts repository.ListOptions) string {
+	return listKeyPrefix + "list?" + listQuery(opts).Encode()
+}
+
+// groupsKey returns the cache key of the blogs of groups, which repeats the parameters of listKey per group.
+func groupsKey(groups []repository.ListOptions) string {
+	query := url.Values{}
+	for _, opts := range groups {
+		for name, values := range listQuery(opts) {
+			query[name] = append(query[name], values...)
+		}
+	}
+	return listKeyPrefix + "groups?" + query.Encode()
+}
+
+// listQuery returns the parameters of opts in a cache key.
+func listQuery(opts repository.ListOptions) url.Values {
+	return url.Values{
+		"category": {opts.Category},
+		"tag":      {opts.Tag},
+		"limit":    {strconv.Itoa(opts.Limit)},
+	}
+}
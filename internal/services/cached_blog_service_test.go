@@ -0,0 +1,244 @@
+package services
+
+import (
+	"bloggingplatformapi/internal/cache"
+	"bloggingplatformapi/internal/models"
+	"bloggingplatformapi/internal/repository"
+	"bloggingplatformapi/migrations"
+	"bloggingplatformapi/pkg/db"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingBlogService counts the reads that reach the wrapped service and can hold them until released.
+type countingBlogService struct {
+	BlogService
+	reads   atomic.Int32
+	release chan struct{} // Reads wait for it to be closed when not nil
+}
+
+func (s *countingBlogService) GetBlogByID(ctx context.Context, id int) (*models.Blog, error) {
+	s.reads.Add(1)
+	if s.release != nil {
+		<-s.release
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err // As a database would once the read's context is canceled
+	}
+	return s.BlogService.GetBlogByID(ctx, id)
+}
+
+func (s *countingBlogService) GetAllBlogs(ctx context.Context, term string, fields ...string) ([]*models.Blog, error) {
+	s.reads.Add(1)
+	return s.BlogService.GetAllBlogs(ctx, term, fields...)
+}
+
+// setupCachedService returns a cached service over an in-memory repository and the counter of uncached reads.
+func setupCachedService(t *testing.T) (BlogService, *countingBlogService) {
+	t.Helper()
+	repo := repository.NewMemoryBlogRepository()
+	counting := &countingBlogService{BlogService: NewBlogService(repo, repo.UnitOfWork())}
+	return NewCachedBlogService(counting, cache.NewLRU(100), time.Minute, nil), counting
+}
+
+func TestCachedBlogService_ServesLookupsFromCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	service, counting := setupCachedService(t)
+	blog := &models.Blog{Title: "Cached", Content: "Content", Category: "Tech", Tags: []string{"Go"}}
+	require.NoError(t, service.CreateBlog(ctx, blog))
+
+	for i := 0; i < 3; i++ {
+		got, err := service.GetBlogByID(ctx, blog.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Cached", got.Title)
+		got.Title = "Modified by the caller"
+	}
+	assert.Equal(t, int32(1), counting.reads.Load())
+
+	// Updates invalidate the cached lookup.
+	blog.Title = "Updated"
+	require.NoError(t, service.UpdateBlog(ctx, blog))
+	got, err := service.GetBlogByID(ctx, blog.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", got.Title)
+	assert.Equal(t, int32(2), counting.reads.Load())
+
+	// Missing blogs are not cached.
+	require.NoError(t, service.DeleteBlog(ctx, blog.ID))
+	for i := 0; i < 2; i++ {
+		_, err = service.GetBlogByID(ctx, blog.ID)
+		assert.Error(t, err)
+	}
+	assert.Equal(t, int32(4), counting.reads.Load())
+}
+
+func TestCachedBlogService_InvalidatesListings(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	service, counting := setupCachedService(t)
+	first := &models.Blog{Title: "First", Content: "Content", Category: "Tech"}
+	require.NoError(t, service.CreateBlog(ctx, first))
+
+	for _, term := range []string{"", "first", ""} {
+		_, err := service.GetAllBlogs(ctx, term)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), counting.reads.Load())
+
+	// Creating a blog invalidates every listing, including search results.
+	require.NoError(t, service.CreateBlog(ctx, &models.Blog{Title: "First again", Content: "Content", Category: "Tech"}))
+	blogs, err := service.GetAllBlogs(ctx, "first")
+	require.NoError(t, err)
+	assert.Len(t, blogs, 2)
+
+	// So does deleting one.
+	require.NoError(t, service.DeleteBlog(ctx, first.ID))
+	blogs, err = service.GetAllBlogs(ctx, "first")
+	require.NoError(t, err)
+	assert.Len(t, blogs, 1)
+	assert.Equal(t, int32(4), counting.reads.Load())
+}
+
+func TestCachedBlogService_CachesProjectionsSeparately(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	service, counting := setupCachedService(t)
+	require.NoError(t, service.CreateBlog(ctx, &models.Blog{Title: "First", Content: "Content", Category: "Tech", Tags: []string{"Go"}}))
+
+	titles, err := service.GetAllBlogs(ctx, "", repository.FieldTitle, repository.FieldID)
+	require.NoError(t, err)
+	assert.Equal(t, "First", titles[0].Title)
+	assert.Empty(t, titles[0].Content)
+
+	_, err = service.GetAllBlogs(ctx, "", repository.FieldID, repository.FieldTitle, repository.FieldID)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), counting.reads.Load(), "the same fields in another order share an entry")
+
+	full, err := service.GetAllBlogs(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, "Content", full[0].Content)
+	assert.Equal(t, int32(2), counting.reads.Load())
+}
+
+func TestCachedBlogService_CollapsesConcurrentMisses(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	service, counting := setupCachedService(t)
+	blog := &models.Blog{Title: "Hot", Content: "Content", Category: "Tech"}
+	require.NoError(t, service.CreateBlog(ctx, blog))
+	counting.release = make(chan struct{})
+
+	const readers = 10
+	var wg sync.WaitGroup
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := service.GetBlogByID(ctx, blog.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, "Hot", got.Title)
+		}()
+	}
+
+	// Wait until the first read reaches the repository, give the others time to join it, then release it.
+	require.Eventually(t, func() bool { return counting.reads.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(counting.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), counting.reads.Load())
+}
+
+func TestCachedBlogService_SharedLoadSurvivesCanceledCaller(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	service, counting := setupCachedService(t)
+	blog := &models.Blog{Title: "Hot", Content: "Content", Category: "Tech"}
+	require.NoError(t, service.CreateBlog(ctx, blog))
+	counting.release = make(chan struct{})
+
+	// The first caller starts the load, and a second one joins it.
+	first, cancel := context.WithCancel(ctx)
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := service.GetBlogByID(first, blog.ID)
+		firstErr <- err
+	}()
+	require.Eventually(t, func() bool { return counting.reads.Load() == 1 }, time.Second, time.Millisecond)
+	second := make(chan *models.Blog, 1)
+	go func() {
+		got, err := service.GetBlogByID(ctx, blog.ID)
+		assert.NoError(t, err)
+		second <- got
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// The first caller gives up without waiting for the load, which still completes for the second one.
+	cancel()
+	assert.ErrorIs(t, <-firstErr, context.Canceled)
+	close(counting.release)
+	assert.Equal(t, "Hot", (<-second).Title)
+	assert.Equal(t, int32(1), counting.reads.Load())
+}
+
+// openSQLite opens a migrated SQLite database in a temporary directory.
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	database, err := db.InitDB(context.Background(), "sqlite://"+filepath.Join(t.TempDir(), "blog.db"), db.DefaultOptions())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = database.Close() })
+	require.NoError(t, migrations.Apply(context.Background(), database, migrations.SQLiteFiles))
+	return database
+}
+
+func TestCachedBlogService_KeepsReadYourWritesWithLaggingReplica(t *testing.T) {
+	t.Parallel()
+
+	// The replica only receives the writes replicated to it explicitly, so it lags until then
+	ctx := context.Background()
+	primary, replica := openSQLite(t), openSQLite(t)
+	router := db.NewRouter(primary, []*sql.DB{replica},
+		db.RouterOptions{Driver: db.DriverSQLite, CheckTimeout: time.Second, StickyWindow: time.Minute})
+	router.CheckReplicas(ctx)
+	service := NewCachedBlogService(NewBlogService(repository.NewRoutedBlogRepository(router), repository.NewUnitOfWork(router)),
+		cache.NewLRU(100), time.Minute, router)
+	replicated := repository.NewRoutedBlogRepository(db.NewRouter(replica, nil, db.RouterOptions{Driver: db.DriverSQLite}))
+
+	writer, reader := db.WithClient(ctx, "writer"), db.WithClient(ctx, "reader")
+	blog := &models.Blog{Title: "Original", Content: "Content", Category: "Tech", Tags: []string{"Go"}}
+	require.NoError(t, service.CreateBlog(writer, blog))
+	require.NoError(t, replicated.Create(ctx, &models.Blog{Title: "Original", Content: "Content", Category: "Tech", Tags: []string{"Go"}}))
+
+	blog.Title = "Updated"
+	require.NoError(t, service.UpdateBlog(writer, blog))
+
+	// Another client may read the lagging replica, but what it reads is not cached...
+	got, err := service.GetBlogByID(reader, blog.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Original", got.Title)
+
+	// ...so the writer reads its write from the primary...
+	got, err = service.GetBlogByID(writer, blog.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", got.Title)
+
+	// ...and other clients see it as soon as the replica catches up.
+	require.NoError(t, replicated.Update(ctx, blog))
+	got, err = service.GetBlogByID(reader, blog.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", got.Title)
+}
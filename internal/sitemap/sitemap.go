@@ -0,0 +1,87 @@
+// Package sitemap writes sitemaps and sitemap indexes (https://www.sitemaps.org/protocol.html) as their
+// entries are produced, so documents listing many URLs are never held in memory.
+package sitemap
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"time"
+)
+
+// ContentType is the media type of sitemaps and sitemap indexes.
+const ContentType = "application/xml; charset=utf-8"
+
+// MaxURLs is the largest number of entries the protocol allows in a sitemap or sitemap index.
+const MaxURLs = 50000
+
+// namespace is the XML namespace of sitemaps and sitemap indexes.
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// ErrFull is returned when an entry is added to a document that already holds MaxURLs entries.
+var ErrFull = errors.New("sitemap: too many entries")
+
+// entry is a url element of a sitemap or a sitemap element of a sitemap index.
+type entry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Writer writes a sitemap or sitemap index one entry at a time.
+type Writer struct {
+	enc   *xml.Encoder
+	root  xml.StartElement // Document element, ended by Close
+	entry xml.StartElement // Element wrapping each entry
+	count int              // Number of entries written
+}
+
+// NewWriter starts a sitemap listing page URLs on w.
+func NewWriter(w io.Writer) (*Writer, error) {
+	return newWriter(w, "urlset", "url")
+}
+
+// NewIndexWriter starts a sitemap index listing sitemap URLs on w.
+func NewIndexWriter(w io.Writer) (*Writer, error) {
+	return newWriter(w, "sitemapindex", "sitemap")
+}
+
+// newWriter writes the XML declaration and the root element of a document made of entries.
+func newWriter(w io.Writer, root, entry string) (*Writer, error) {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return nil, err
+	}
+	sw := &Writer{
+		enc:   xml.NewEncoder(w),
+		root:  xml.StartElement{Name: xml.Name{Local: root}, Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: namespace}}},
+		entry: xml.StartElement{Name: xml.Name{Local: entry}},
+	}
+	sw.enc.Indent("", "  ")
+	if err := sw.enc.EncodeToken(sw.root); err != nil {
+		return nil, err
+	}
+	return sw, nil
+}
+
+// Add writes an entry for the absolute URL loc, last modified at lastMod; a zero lastMod is left out.
+func (w *Writer) Add(loc string, lastMod time.Time) error {
+	if w.count == MaxURLs {
+		return ErrFull
+	}
+	e := entry{Loc: loc}
+	if !lastMod.IsZero() {
+		e.LastMod = lastMod.UTC().Format(time.RFC3339)
+	}
+	if err := w.enc.EncodeElement(e, w.entry); err != nil {
+		return err
+	}
+	w.count++
+	return nil
+}
+
+// Close ends the document. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.enc.EncodeToken(w.root.End()); err != nil {
+		return err
+	}
+	return w.enc.Close()
+}
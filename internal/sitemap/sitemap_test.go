@@ -0,0 +1,60 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf)
+	require.NoError(t, err)
+	require.NoError(t, w.Add("https://example.com/posts/1?a=1&b=2", time.Date(2024, 10, 16, 14, 45, 0, 0, time.FixedZone("", 2*3600))))
+	require.NoError(t, w.Add("https://example.com/posts/2", time.Time{}))
+	require.NoError(t, w.Close())
+
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+		URLs    []entry  `xml:"url"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, []entry{
+		{Loc: "https://example.com/posts/1?a=1&b=2", LastMod: "2024-10-16T12:45:00Z"},
+		{Loc: "https://example.com/posts/2"},
+	}, doc.URLs)
+	assert.Contains(t, buf.String(), "<loc>https://example.com/posts/1?a=1&amp;b=2</loc>", "URLs are entity-escaped")
+}
+
+func TestIndexWriter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w, err := NewIndexWriter(&buf)
+	require.NoError(t, err)
+	require.NoError(t, w.Add("https://example.com/sitemaps/blogs-1.xml", time.Time{}))
+	require.NoError(t, w.Close())
+
+	var doc struct {
+		XMLName  xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+		Sitemaps []string `xml:"sitemap>loc"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, []string{"https://example.com/sitemaps/blogs-1.xml"}, doc.Sitemaps)
+}
+
+func TestWriter_RejectsTooManyEntries(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf)
+	require.NoError(t, err)
+	w.count = MaxURLs
+	assert.ErrorIs(t, w.Add("https://example.com/", time.Time{}), ErrFull)
+}
@@ -0,0 +1,112 @@
+package tracing_test
+
+import (
+	"bloggingplatformapi/internal/config"
+	"bloggingplatformapi/internal/routes"
+	"bloggingplatformapi/internal/tracing"
+	"bloggingplatformapi/internal/utils"
+	"bloggingplatformapi/pkg/mock/dbmock"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	upstreamTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	traceparent     = "00-" + upstreamTraceID + "-00f067aa0ba902b7-01"
+)
+
+// setupTracing installs a tracer provider backed by an in-memory exporter.
+func setupTracing(t *testing.T) (*tracetest.InMemoryExporter, func()) {
+	t.Helper()
+	cfg := &config.Config{ServiceName: "test", Environment: "test", TracingExporter: tracing.ExporterNone, TracingSampleRatio: 1}
+
+	_, err := tracing.Setup(context.Background(), cfg)
+	require.NoError(t, err)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider, err := tracing.NewTracerProvider(exporter, cfg)
+	require.NoError(t, err)
+	otel.SetTracerProvider(provider)
+
+	return exporter, func() {
+		assert.NoError(t, provider.ForceFlush(context.Background()))
+	}
+}
+
+func TestTracing_UpdateBlogSpans(t *testing.T) {
+	exporter, flush := setupTracing(t)
+	logger, hook := logtest.NewNullLogger()
+
+	db, mock, err := dbmock.NewMockDB()
+	require.NoError(t, err)
+
+	now := time.Date(2024, 12, 25, 16, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE blogs`).
+		WithArgs("Title", "Content", "<p>Content</p>\n", "Tech", pq.Array([]string{"Go"}), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(now))
+	mock.ExpectQuery(`SELECT (.+) FROM blogs WHERE id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "content_html", "category", "tags", "created_at", "updated_at"}).
+			AddRow(1, "Title", "Content", "<p>Content</p>\n", "Tech", "{Go}", now, now))
+	mock.ExpectCommit()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(otelgin.Middleware("test"), utils.GinLogrus(logger))
+	routes.SetupRoutes(router, routes.Dependencies{DB: db})
+
+	body := `{"title":"Title","content":"Content","category":"Tech","tags":["Go"]}`
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/blogs/1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("traceparent", traceparent)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	flush()
+
+	spans := exporter.GetSpans()
+	byName := make(map[string]tracetest.SpanStub)
+	for _, span := range spans {
+		// Every span joins the trace started by the upstream caller.
+		assert.Equal(t, upstreamTraceID, span.SpanContext.TraceID().String(), span.Name)
+		byName[span.Name] = span
+	}
+
+	server, ok := byName["PUT /api/v1/blogs/:blogId"]
+	require.True(t, ok, "missing HTTP server span")
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+
+	update := byName["BlogService.UpdateBlog"]
+	assert.Equal(t, server.SpanContext.SpanID(), update.Parent.SpanID())
+
+	// The update and the read-back run in one transaction within the service span.
+	updateSQL := byName["UPDATE blogs"]
+	selectSQL := byName["SELECT blogs"]
+	assert.Equal(t, trace.SpanKindClient, updateSQL.SpanKind)
+	assert.Equal(t, update.SpanContext.SpanID(), updateSQL.Parent.SpanID())
+	assert.Equal(t, update.SpanContext.SpanID(), selectSQL.Parent.SpanID())
+
+	// The request log carries the trace ID so logs and traces can be joined.
+	require.Len(t, hook.AllEntries(), 1)
+	assert.Equal(t, upstreamTraceID, hook.LastEntry().Data["traceId"])
+	assert.Equal(t, logrus.InfoLevel, hook.LastEntry().Level)
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"bloggingplatformapi/internal/logging"
+	"encoding/xml"
+	"reflect"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnvelopeVersion is the version of the response envelope, reported in its meta. It matches the API version
+// the envelope is served under.
+const EnvelopeVersion = "2"
+
+// Gin context keys used by the envelope.
+const (
+	envelopeKey   = "responseEnvelope"   // Set by WithEnvelope
+	paginationKey = "responsePagination" // Set by SetPagination
+)
+
+// Envelope wraps every response of the API versions that use it. Successful responses carry data, failed ones
+// errors; both carry meta and links.
+type Envelope struct {
+	XMLName xml.Name      `json:"-" xml:"response" yaml:"-"`
+	Data    any           `json:"data,omitempty" xml:"data,omitempty" yaml:"data,omitempty"`             // Payload of a successful response
+	Meta    Meta          `json:"meta" xml:"meta" yaml:"meta"`                                           // Information about the response
+	Links   Links         `json:"links" xml:"links" yaml:"links"`                                        // Related URLs
+	Errors  []ErrorObject `json:"errors,omitempty" xml:"errors>error,omitempty" yaml:"errors,omitempty"` // What went wrong, for a failed response
+}
+
+// Meta describes an enveloped response.
+type Meta struct {
+	Version    string      `json:"version" xml:"version" yaml:"version"`                                     // EnvelopeVersion
+	RequestID  string      `json:"requestId,omitempty" xml:"requestId,omitempty" yaml:"requestId,omitempty"` // ID to quote when reporting problems
+	Pagination *Pagination `json:"pagination,omitempty" xml:"pagination,omitempty" yaml:"pagination,omitempty"`
+}
+
+// Pagination describes the page of a list a response holds.
+type Pagination struct {
+	Total  int `json:"total" xml:"total" yaml:"total"`    // Number of items in the whole list
+	Count  int `json:"count" xml:"count" yaml:"count"`    // Number of items on this page
+	Limit  int `json:"limit" xml:"limit" yaml:"limit"`    // Largest number of items on a page, 0 for no limit
+	Offset int `json:"offset" xml:"offset" yaml:"offset"` // Number of items before this page
+}
+
+// Links holds URLs related to a response, relative to the host. Next and Prev are set for paginated lists
+// with further pages.
+type Links struct {
+	Self string `json:"self" xml:"self" yaml:"self"`
+	Next string `json:"next,omitempty" xml:"next,omitempty" yaml:"next,omitempty"`
+	Prev string `json:"prev,omitempty" xml:"prev,omitempty" yaml:"prev,omitempty"`
+}
+
+// ErrorObject describes an error in an enveloped response.
+type ErrorObject struct {
+	Status  int    `json:"status" xml:"status" yaml:"status"`                                  // HTTP status of the response
+	Message string `json:"message" xml:"message" yaml:"message"`                               // Human-readable description
+	Details gin.H  `json:"details,omitempty" xml:"details,omitempty" yaml:"details,omitempty"` // Additional fields, e.g. the results of a batch
+}
+
+// WithEnvelope is a middleware that makes RespondWithJSON and RespondWithError wrap responses in an Envelope.
+func WithEnvelope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(envelopeKey, true)
+		c.Next()
+	}
+}
+
+// SetPagination records that the next response holds the page of a list described by p, with Count filled
+// in from the payload. Enveloped responses report it in meta and link to the neighbouring pages.
+func SetPagination(ctx *gin.Context, p Pagination) {
+	ctx.Set(paginationKey, p)
+}
+
+// envelope wraps the payload of a successful response.
+func envelope(ctx *gin.Context, payload any) Envelope {
+	env := newEnvelope(ctx)
+	env.Data = payload
+	if value, ok := ctx.Get(paginationKey); ok {
+		p := value.(Pagination)
+		if v := reflect.ValueOf(payload); v.Kind() == reflect.Slice {
+			p.Count = v.Len()
+		}
+		env.Meta.Pagination = &p
+		if p.Limit > 0 && p.Offset+p.Limit < p.Total {
+			env.Links.Next = pageLink(ctx, p.Offset+p.Limit)
+		}
+		if p.Offset > 0 {
+			env.Links.Prev = pageLink(ctx, max(0, p.Offset-p.Limit))
+		}
+	}
+	return env
+}
+
+// errorEnvelope wraps the error of a failed response.
+func errorEnvelope(ctx *gin.Context, code int, message string, details gin.H) Envelope {
+	env := newEnvelope(ctx)
+	env.Errors = []ErrorObject{{Status: code, Message: message, Details: details}}
+	return env
+}
+
+// newEnvelope returns an envelope with the meta and links every response carries.
+func newEnvelope(ctx *gin.Context) Envelope {
+	return Envelope{
+		Meta:  Meta{Version: EnvelopeVersion, RequestID: logging.RequestIDFromContext(ctx.Request.Context())},
+		Links: Links{Self: ctx.Request.URL.RequestURI()},
+	}
+}
+
+// pageLink returns the URL of the request with its offset query parameter set to offset.
+func pageLink(ctx *gin.Context, offset int) string {
+	u := *ctx.Request.URL
+	query := u.Query()
+	query.Set("offset", strconv.Itoa(offset))
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
+
+// emptyIfNil returns an empty slice in place of a nil one, so lists encode as [] rather than null.
+func emptyIfNil(payload any) any {
+	if v := reflect.ValueOf(payload); v.Kind() == reflect.Slice && v.IsNil() {
+		return reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+	return payload
+}
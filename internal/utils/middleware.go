@@ -1,12 +1,49 @@
 package utils
 
 import (
+	"bloggingplatformapi/internal/logging"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// RequestIDHeader is the header used to accept and echo request IDs.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied request IDs so they cannot bloat logs.
+const maxRequestIDLength = 128
+
+// RequestID is a middleware that assigns every request an ID and a request-scoped logger.
+// A valid X-Request-ID sent by the client is reused, otherwise a new ID is generated.
+// The ID is echoed in the response headers and stored in the request context together with
+// a logger carrying it, so controllers, services and repositories log with the same fields.
+func RequestID(logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !ValidRequestID(requestID) {
+			requestID = NewRequestID()
+		}
+
+		c.Header(RequestIDHeader, requestID)
+
+		ctx := c.Request.Context()
+		fields := traceFields(ctx)
+		fields["requestId"] = requestID
+		entry := logger.WithFields(fields)
+
+		ctx = logging.WithRequestID(ctx, requestID)
+		ctx = logging.NewContext(ctx, entry)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
 // GinLogrus is a middleware function that logs HTTP requests using Logrus
 func GinLogrus(logger *logrus.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -19,15 +56,58 @@ func GinLogrus(logger *logrus.Logger) gin.HandlerFunc {
 		// Calculate the duration of the request
 		duration := time.Since(startTime)
 
+		// Correlate the log entry with the request's trace, if one is active
+		fields := traceFields(c.Request.Context())
+		if requestID := logging.RequestIDFromContext(c.Request.Context()); requestID != "" {
+			fields["requestId"] = requestID
+		}
+
+		fields["status"] = c.Writer.Status()
+		fields["method"] = c.Request.Method
+		fields["path"] = c.Request.URL.Path
+		fields["ip"] = c.ClientIP()
+		fields["latency"] = duration.Milliseconds()
+		fields["userAgent"] = c.Request.UserAgent()
+		fields["error"] = c.Errors.ByType(gin.ErrorTypePrivate).String() // Logs any internal errors
+
 		// Log the request details
-		logger.WithFields(logrus.Fields{
-			"status":    c.Writer.Status(),
-			"method":    c.Request.Method,
-			"path":      c.Request.URL.Path,
-			"ip":        c.ClientIP(),
-			"latency":   duration.Milliseconds(),
-			"userAgent": c.Request.UserAgent(),
-			"error":     c.Errors.ByType(gin.ErrorTypePrivate).String(), // Logs any internal errors
-		}).Info("HTTP request processed")
+		logger.WithFields(fields).Info("HTTP request processed")
+	}
+}
+
+// ClientKey identifies the client making a request by its IP, as resolved through the trusted proxies.
+// The REST API does not authenticate clients, so there is nothing more specific to go by.
+func ClientKey(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// traceFields returns log fields identifying the active span in ctx, if any.
+func traceFields(ctx context.Context) logrus.Fields {
+	fields := logrus.Fields{}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		fields["traceId"] = spanCtx.TraceID().String()
+		fields["spanId"] = spanCtx.SpanID().String()
 	}
+	return fields
+}
+
+// ValidRequestID reports whether a client-supplied request ID is safe to reuse.
+// Only visible ASCII characters are accepted so the ID cannot inject content into logs or headers.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// NewRequestID generates a random 128-bit request ID encoded as hex.
+func NewRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // crypto/rand.Read never returns an error
+	return hex.EncodeToString(b)
 }
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"bloggingplatformapi/internal/logging"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupRequestIDRouter creates a router whose only route fails, logging through the request-scoped logger.
+func setupRequestIDRouter(t *testing.T) (*gin.Engine, *logtest.Hook) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	logger, hook := logtest.NewNullLogger()
+
+	router := gin.New()
+	router.Use(RequestID(logger))
+	router.GET("/fail", func(ctx *gin.Context) {
+		logging.FromContext(ctx.Request.Context()).Error(errors.New("boom"))
+		RespondWithError(ctx, http.StatusInternalServerError, "Failed")
+	})
+	return router, hook
+}
+
+func TestRequestID_ReusesClientID(t *testing.T) {
+	t.Parallel()
+
+	router, hook := setupRequestIDRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+	req.Header.Set(RequestIDHeader, "client-id-123")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, "client-id-123", rec.Header().Get(RequestIDHeader))
+
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "client-id-123", body["requestId"])
+
+	require.NotNil(t, hook.LastEntry())
+	assert.Equal(t, "client-id-123", hook.LastEntry().Data["requestId"])
+}
+
+func TestRequestID_GeneratesMissingOrInvalidID(t *testing.T) {
+	t.Parallel()
+
+	router, _ := setupRequestIDRouter(t)
+
+	for _, header := range []string{"", "bad id\nwith newline"} {
+		req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+		if header != "" {
+			req.Header.Set(RequestIDHeader, header)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		generated := rec.Header().Get(RequestIDHeader)
+		assert.Len(t, generated, 32)
+		assert.NotEqual(t, header, generated)
+	}
+}
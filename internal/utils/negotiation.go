@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+	"github.com/ugorji/go/codec"
+	"gopkg.in/yaml.v3"
+)
+
+// Media types the blog API reads and writes.
+const (
+	MIMEJSON    = "application/json"
+	MIMEXML     = "application/xml"
+	MIMEYAML    = "application/yaml"
+	MIMEMsgPack = "application/msgpack"
+)
+
+// formatKey is the gin context key under which Negotiate stores the media type of the response.
+const formatKey = "responseFormat"
+
+// offeredTypes lists the media types accepted in Accept headers, in order of preference. JSON comes first,
+// so it answers Accept: */*, application/* and requests without an Accept header.
+var offeredTypes = []string{
+	MIMEJSON,
+	MIMEXML, "text/xml",
+	MIMEYAML, "application/x-yaml", "text/yaml",
+	MIMEMsgPack, "application/x-msgpack",
+}
+
+// formats maps every media type in offeredTypes to the one responses and request bodies are handled as.
+var formats = map[string]string{
+	MIMEJSON: MIMEJSON,
+	MIMEXML:  MIMEXML, "text/xml": MIMEXML,
+	MIMEYAML: MIMEYAML, "application/x-yaml": MIMEYAML, "text/yaml": MIMEYAML,
+	MIMEMsgPack: MIMEMsgPack, "application/x-msgpack": MIMEMsgPack,
+}
+
+// msgpackHandle configures MessagePack encoding. WriteExt selects the current format, with distinct string
+// and binary types, which other MessagePack libraries expect; struct fields follow their JSON tags.
+var msgpackHandle = &codec.MsgpackHandle{WriteExt: true}
+
+// ErrUnsupportedMediaType is returned by DecodeBody for a request body in a format it cannot decode.
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// Negotiate is a middleware that chooses the format of responses from the Accept header: JSON, XML, YAML or
+// MessagePack. Requests accepting none of them are answered with 406 Not Acceptable, in JSON.
+func Negotiate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept")
+		offer := c.NegotiateFormat(offeredTypes...)
+		if offer == "" {
+			RespondWithError(c, http.StatusNotAcceptable,
+				fmt.Sprintf("Accept must allow one of %s, %s, %s or %s", MIMEJSON, MIMEXML, MIMEYAML, MIMEMsgPack))
+			c.Abort()
+			return
+		}
+		c.Set(formatKey, formats[offer])
+		c.Next()
+	}
+}
+
+// ResponseFormat returns the media type responses to the request are written in: the one chosen by
+// Negotiate, or JSON on routes without it.
+func ResponseFormat(ctx *gin.Context) string {
+	if format := ctx.GetString(formatKey); format != "" {
+		return format
+	}
+	return MIMEJSON
+}
+
+// DecodeBody decodes the request body into obj in the format named by its Content-Type, without validating it.
+// A body without a Content-Type is decoded as JSON; any other type yields ErrUnsupportedMediaType.
+func DecodeBody(ctx *gin.Context, obj any) error {
+	format := MIMEJSON
+	if contentType := ctx.ContentType(); contentType != "" {
+		if format = formats[contentType]; format == "" {
+			return fmt.Errorf("%w %q: Content-Type must be one of %s, %s, %s or %s",
+				ErrUnsupportedMediaType, contentType, MIMEJSON, MIMEXML, MIMEYAML, MIMEMsgPack)
+		}
+	}
+
+	body := ctx.Request.Body
+	switch format {
+	case MIMEXML:
+		return decodeXML(body, obj)
+	case MIMEYAML:
+		return yaml.NewDecoder(body).Decode(obj)
+	case MIMEMsgPack:
+		return codec.NewDecoder(body, msgpackHandle).Decode(obj)
+	default:
+		return json.NewDecoder(body).Decode(obj)
+	}
+}
+
+// BindBody decodes the request body like DecodeBody and validates obj against its binding tags.
+func BindBody(ctx *gin.Context, obj any) error {
+	if err := DecodeBody(ctx, obj); err != nil {
+		return err
+	}
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// xmlList is the root element of a list in XML, which has none of its own. Items are named after
+// their type's XMLName, or item if it has none.
+type xmlList struct {
+	XMLName xml.Name `xml:"items"`
+	Items   any      `xml:"item"`
+}
+
+// xmlDocument returns payload in a form that encodes as a single XML document.
+func xmlDocument(payload any) any {
+	if v := reflect.ValueOf(payload); v.Kind() == reflect.Slice {
+		return xmlList{Items: payload}
+	}
+	return payload
+}
+
+// decodeXML decodes an XML document into obj. A list is read from the children of the root element,
+// as written by xmlDocument, whatever the elements are named.
+func decodeXML(body io.Reader, obj any) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Slice {
+		return xml.NewDecoder(body).Decode(obj)
+	}
+	list := reflect.New(reflect.StructOf([]reflect.StructField{
+		{Name: "Items", Type: v.Elem().Type(), Tag: `xml:",any"`},
+	}))
+	if err := xml.NewDecoder(body).Decode(list.Interface()); err != nil {
+		return err
+	}
+	v.Elem().Set(list.Elem().Field(0))
+	return nil
+}
+
+// msgpackRender writes a MessagePack response with msgpackHandle.
+type msgpackRender struct {
+	data any
+}
+
+// Render implements render.Render.
+func (r msgpackRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	return codec.NewEncoder(w, msgpackHandle).Encode(r.data)
+}
+
+// WriteContentType implements render.Render.
+func (r msgpackRender) WriteContentType(w http.ResponseWriter) {
+	if header := w.Header(); header.Get("Content-Type") == "" {
+		header.Set("Content-Type", MIMEMsgPack)
+	}
+}
+
+var _ render.Render = msgpackRender{}
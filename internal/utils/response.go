@@ -1,15 +1,62 @@
 package utils
 
-import "github.com/gin-gonic/gin"
+import (
+	"bloggingplatformapi/internal/logging"
 
-// RespondWithError sends a JSON response with an error message and HTTP status code.
+	"github.com/gin-gonic/gin"
+)
+
+// RespondWithError sends a response with an error message and HTTP status code.
+// The request ID is included so clients can quote it when reporting problems.
 func RespondWithError(ctx *gin.Context, code int, message string) {
-	RespondWithJSON(ctx, code, gin.H{
+	RespondWithErrorDetails(ctx, code, message, nil)
+}
+
+// RespondWithErrorDetails sends an error response like RespondWithError with additional fields in the body.
+// Under WithEnvelope the error is the only entry of errors, and the additional fields are its details.
+func RespondWithErrorDetails(ctx *gin.Context, code int, message string, details gin.H) {
+	if ctx.GetBool(envelopeKey) {
+		respond(ctx, code, errorEnvelope(ctx, code, message, details))
+		return
+	}
+
+	body := gin.H{
 		"error": message,
-	})
+	}
+	for key, value := range details {
+		body[key] = value
+	}
+	if requestID := logging.RequestIDFromContext(ctx.Request.Context()); requestID != "" {
+		body["requestId"] = requestID
+	}
+	respond(ctx, code, body)
 }
 
-// RespondWithJSON sends a JSON response with a given payload and HTTP status code.
+// RespondWithJSON sends a response with a given payload and HTTP status code. The payload is encoded as JSON,
+// or in the XML, YAML or MessagePack format chosen by the Negotiate middleware from the Accept header.
+// Under WithEnvelope it is the data of an Envelope. A nil list is sent as an empty one.
 func RespondWithJSON(ctx *gin.Context, code int, payload interface{}) {
-	ctx.JSON(code, payload)
+	payload = emptyIfNil(payload)
+	if ctx.GetBool(envelopeKey) {
+		payload = envelope(ctx, payload)
+	}
+	respond(ctx, code, payload)
+}
+
+// respond encodes payload in the response format of the request.
+func respond(ctx *gin.Context, code int, payload any) {
+	switch ResponseFormat(ctx) {
+	case MIMEXML:
+		if env, ok := payload.(Envelope); ok {
+			env.Data = xmlDocument(env.Data)
+			payload = env
+		}
+		ctx.XML(code, xmlDocument(payload))
+	case MIMEYAML:
+		ctx.YAML(code, payload)
+	case MIMEMsgPack:
+		ctx.Render(code, msgpackRender{data: payload})
+	default:
+		ctx.JSON(code, payload)
+	}
 }
@@ -3,6 +3,7 @@ package utils
 import (
 	"bloggingplatformapi/internal/models"
 	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -23,6 +24,31 @@ func ValidateBlog(blog *models.Blog) error {
 	return nil
 }
 
+// ValidateBatchOperation ensures that a batch operation names a known kind, the blog it applies to
+// and, for creates and updates, a valid blog.
+func ValidateBatchOperation(op *models.BatchOperation) error {
+	switch op.Op {
+	case models.BatchCreate:
+		if op.ID != 0 {
+			return errors.New("id must not be set when creating a blog")
+		}
+	case models.BatchUpdate, models.BatchDelete:
+		if op.ID <= 0 {
+			return errors.New("id is required")
+		}
+	default:
+		return fmt.Errorf("op must be %s, %s or %s", models.BatchCreate, models.BatchUpdate, models.BatchDelete)
+	}
+
+	if op.Op == models.BatchDelete {
+		return nil
+	}
+	if op.Blog == nil {
+		return errors.New("blog is required")
+	}
+	return ValidateBlog(op.Blog)
+}
+
 // isEmpty checks if a string is empty or consists solely of whitespace.
 func isEmpty(value string) bool {
 	return strings.TrimSpace(value) == ""
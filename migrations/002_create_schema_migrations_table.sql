@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS schema_migrations (
+                                     version INTEGER PRIMARY KEY,
+                                     applied_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Record this migration and the ones applied before schema tracking existed.
+-- Every later migration must insert its own version so readiness checks can verify the schema.
+INSERT INTO schema_migrations (version) VALUES (1), (2) ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,5 @@
+-- Store the sanitized HTML rendering of each blog's Markdown content alongside it.
+-- Existing blogs keep an empty rendering until they are next updated; the API renders those on read.
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS content_html TEXT NOT NULL DEFAULT '';
+
+INSERT INTO schema_migrations (version) VALUES (3) ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,146 @@
+package migrations
+
+import (
+	"bloggingplatformapi/pkg/db"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Files holds the PostgreSQL migration files shipped with the binary.
+//
+//go:embed *.sql
+var Files embed.FS
+
+// sqliteFiles holds the SQLite migrations under sqlite/.
+//
+//go:embed sqlite/*.sql
+var sqliteFiles embed.FS
+
+// SQLiteFiles holds the SQLite migration files shipped with the binary.
+// They create the same schema as Files, version for version.
+var SQLiteFiles, _ = fs.Sub(sqliteFiles, "sqlite")
+
+// ForDriver returns the migration files for a database driver, see db.ParseDataSource.
+func ForDriver(driver string) fs.FS {
+	if driver == db.DriverSQLite {
+		return SQLiteFiles
+	}
+	return Files
+}
+
+// Apply runs the migrations in files that database has not recorded in schema_migrations, in version order.
+// Migrations are also written to be idempotent where the SQL dialect allows, so applying them to a database
+// migrated by hand changes nothing.
+func Apply(ctx context.Context, database *sql.DB, files fs.FS) error {
+	names, err := fs.Glob(files, "*.sql")
+	if err != nil {
+		return fmt.Errorf("failed to list migrations: %w", err)
+	}
+	applied, err := appliedVersions(ctx, database)
+	if err != nil {
+		return err
+	}
+	for _, name := range names { // Glob returns names sorted, and so in version order
+		version, err := versionOf(name)
+		if err != nil {
+			return err
+		}
+		if applied[version] {
+			continue
+		}
+		script, err := fs.ReadFile(files, name)
+		if err != nil {
+			return fmt.Errorf("failed to read migration %q: %w", name, err)
+		}
+		if _, err := database.ExecContext(ctx, string(script)); err != nil {
+			return fmt.Errorf("failed to apply migration %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// appliedVersions returns the versions recorded in schema_migrations, none before the table exists.
+// Any other failure to read them is returned, since running the migrations again could fail or corrupt data.
+func appliedVersions(ctx context.Context, database *sql.DB) (map[int]bool, error) {
+	applied := map[int]bool{}
+	rows, err := database.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if missingTable(err) {
+		return applied, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// undefinedTable is the SQLSTATE PostgreSQL reports for a missing table.
+const undefinedTable = "42P01"
+
+// missingTable reports whether err is the error of a query of a table that does not exist.
+// SQLite reports it only in the message, as a generic SQLITE_ERROR.
+func missingTable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == undefinedTable
+	}
+	return strings.Contains(err.Error(), "no such table")
+}
+
+// LatestVersion returns the highest migration version found in files.
+// Migration file names start with their zero-padded version, e.g. 002_create_schema_migrations_table.sql.
+func LatestVersion(files fs.FS) (int, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	latest := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		version, err := versionOf(entry.Name())
+		if err != nil {
+			return 0, err
+		}
+		if version > latest {
+			latest = version
+		}
+	}
+	return latest, nil
+}
+
+// versionOf returns the version of a migration from its file name, e.g. 2 for 002_create_schema_migrations_table.sql.
+func versionOf(name string) (int, error) {
+	prefix, _, found := strings.Cut(name, "_")
+	if !found {
+		return 0, fmt.Errorf("migration %q has no version prefix", name)
+	}
+	version, err := strconv.Atoi(prefix)
+	if err != nil {
+		return 0, fmt.Errorf("migration %q has an invalid version prefix: %w", name, err)
+	}
+	return version, nil
+}
@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"bloggingplatformapi/pkg/db"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openSQLite opens an empty SQLite database in a temporary directory.
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	database, err := db.InitDB(context.Background(), "sqlite://"+filepath.Join(t.TempDir(), "blog.db"), db.DefaultOptions())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = database.Close() })
+	return database
+}
+
+func TestApply_SkipsRecordedMigrations(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	database := openSQLite(t)
+
+	require.NoError(t, Apply(ctx, database, SQLiteFiles))
+	// A second run must not repeat 003, whose ALTER TABLE fails once the column exists
+	require.NoError(t, Apply(ctx, database, SQLiteFiles))
+
+	applied, err := appliedVersions(ctx, database)
+	require.NoError(t, err)
+	latest, err := LatestVersion(SQLiteFiles)
+	require.NoError(t, err)
+	assert.Len(t, applied, latest)
+}
+
+func TestApply_FailsIfAppliedVersionsCannotBeRead(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	database := openSQLite(t)
+
+	_, err := database.ExecContext(ctx, `CREATE TABLE schema_migrations (id INTEGER PRIMARY KEY)`)
+	require.NoError(t, err)
+
+	err = Apply(ctx, database, SQLiteFiles)
+	assert.ErrorContains(t, err, "failed to read applied migrations")
+	var tables int
+	require.NoError(t, database.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE name = 'blogs'`).Scan(&tables))
+	assert.Zero(t, tables, "no migration runs")
+}
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS blogs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT, -- AUTOINCREMENT never reuses IDs, like a SERIAL sequence
+    title TEXT NOT NULL,
+    content TEXT NOT NULL,
+    category TEXT NOT NULL,
+    tags TEXT NOT NULL DEFAULT '[]', -- JSON array of strings
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')), -- UTC, millisecond precision
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    CONSTRAINT chk_title_length CHECK (length(title) BETWEEN 1 AND 255), -- Ensures non-empty titles within VARCHAR(255)
+    CONSTRAINT chk_category_length CHECK (length(category) BETWEEN 1 AND 100) -- Ensures non-empty categories within VARCHAR(100)
+);
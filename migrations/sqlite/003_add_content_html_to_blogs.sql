@@ -0,0 +1,6 @@
+-- Store the sanitized HTML rendering of each blog's Markdown content alongside it.
+-- Existing blogs keep an empty rendering until they are next updated; the API renders those on read.
+-- SQLite cannot add a column only if it is missing, so Apply skips this migration once it is recorded.
+ALTER TABLE blogs ADD COLUMN content_html TEXT NOT NULL DEFAULT '';
+
+INSERT INTO schema_migrations (version) VALUES (3) ON CONFLICT (version) DO NOTHING;
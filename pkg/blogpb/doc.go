@@ -0,0 +1,5 @@
+// Package blogpb holds the Go messages and the gRPC client and server of the blog API defined in
+// api/proto/blog/v1/blog.proto. Everything but this file is generated; regenerate it after editing the proto.
+package blogpb
+
+//go:generate sh -c "cd ../../api/proto && buf generate"
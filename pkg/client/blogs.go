@@ -0,0 +1,285 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPageSize is the number of blogs Blogs fetches per request unless ListOptions.Limit is set.
+const DefaultPageSize = 100
+
+// fieldID is the JSON name of the blog ID, which Blogs needs to page.
+const fieldID = "id"
+
+// Batch operation kinds.
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+// Archive formats of Export and Import.
+const (
+	FormatJSONL = "jsonl" // JSON Lines, one blog per line
+	FormatZip   = "zip"   // Zip of Markdown files with front matter
+)
+
+// Blog is a blog post.
+type Blog struct {
+	ID          int       `json:"id,omitempty"`          // Unique identifier, assigned on creation
+	Title       string    `json:"title"`                 // Title (required)
+	Content     string    `json:"content"`               // Content in Markdown (required)
+	ContentHTML string    `json:"contentHtml,omitempty"` // Content rendered to sanitized HTML, with Options.RenderHTML; ignored in requests
+	Category    string    `json:"category"`              // Category (required)
+	Tags        []string  `json:"tags"`                  // Tags (at least one required)
+	Excerpt     string    `json:"excerpt,omitempty"`     // Start of the content as plain text, if requested in ListOptions.Fields
+	CreatedAt   time.Time `json:"createdAt"`             // When the blog was created; ignored in requests other than imports keeping timestamps
+	UpdatedAt   time.Time `json:"updatedAt"`             // When the blog was last updated; ignored like CreatedAt
+}
+
+// ListOptions select the blogs returned by ListBlogs and Blogs.
+type ListOptions struct {
+	Term   string   // Only blogs whose title, content or category contain the term; empty for all
+	Fields []string // JSON names of the fields to return, e.g. "id" and "excerpt"; empty for all. Others are left zero
+	Limit  int      // Largest number of blogs returned by ListBlogs, 0 for no limit; page size of Blogs
+	Offset int      // Number of blogs to skip
+	After  int      // Only blogs with a greater ID; 0 for all
+}
+
+// BatchOperation is a single create, update or delete within a batch.
+type BatchOperation struct {
+	Op   string `json:"op"`             // OpCreate, OpUpdate or OpDelete
+	ID   int    `json:"id,omitempty"`   // Blog to update or delete
+	Blog *Blog  `json:"blog,omitempty"` // Blog to create, or the new content of the blog to update
+}
+
+// BatchResult is the outcome of a single operation of a batch.
+type BatchResult struct {
+	Index  int    `json:"index"`          // Position of the operation in the request
+	Op     string `json:"op"`             // Operation kind
+	Status int    `json:"status"`         // HTTP status the operation would have had as a single request
+	ID     int    `json:"id,omitempty"`   // Blog the operation applied to
+	Blog   *Blog  `json:"blog,omitempty"` // Created or updated blog
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportOptions configure Import.
+type ImportOptions struct {
+	Format         string // FormatJSONL or FormatZip; empty for FormatJSONL
+	BestEffort     bool   // Import the valid items even if others fail; otherwise import all or none
+	DryRun         bool   // Validate the archive without storing anything
+	KeepTimestamps bool   // Create the blogs with the timestamps in the archive, to restore a backup
+}
+
+// ImportReport is the outcome of Import.
+type ImportReport struct {
+	DryRun   bool           `json:"dryRun"`   // Whether nothing was stored
+	Imported int            `json:"imported"` // Number of blogs created
+	Results  []ImportResult `json:"results"`  // Outcome of every item of the archive
+}
+
+// ImportResult is the outcome of importing a single item of an archive.
+type ImportResult struct {
+	Index  int    `json:"index"`        // Position of the item in the archive
+	Source string `json:"source"`       // Where the item was found, e.g. "line 3" or "blogs/3-hello.md"
+	Status int    `json:"status"`       // HTTP status creating the item as a single request would have had
+	ID     int    `json:"id,omitempty"` // ID of the created blog
+	Error  string `json:"error,omitempty"`
+}
+
+// ListBlogs fetches a single page of the blogs selected by opts via GET /blogs.
+func (c *Client) ListBlogs(ctx context.Context, opts ListOptions) ([]*Blog, error) {
+	query := c.renderQuery()
+	if opts.Term != "" {
+		query.Set("term", opts.Term)
+	}
+	if len(opts.Fields) > 0 {
+		query.Set("fields", strings.Join(opts.Fields, ","))
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		query.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	if opts.After > 0 {
+		query.Set("after", strconv.Itoa(opts.After))
+	}
+
+	var blogs []*Blog
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/blogs", query: query}, &blogs); err != nil {
+		return nil, err
+	}
+	return blogs, nil
+}
+
+// Blogs iterates over every blog selected by opts, fetching opts.Limit of them per request, or
+// DefaultPageSize if it is 0. Iteration stops after the first error, which is yielded with a nil blog.
+// Pages after the first are fetched by the last ID seen rather than by offset, so each costs the server the
+// same however deep the iteration is, and blogs created or deleted meanwhile do not shift the later pages.
+// The ID is therefore always fetched, even if opts.Fields does not list it.
+func (c *Client) Blogs(ctx context.Context, opts ListOptions) iter.Seq2[*Blog, error] {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultPageSize
+	}
+	if len(opts.Fields) > 0 && !slices.Contains(opts.Fields, fieldID) {
+		opts.Fields = append(slices.Clone(opts.Fields), fieldID)
+	}
+	return func(yield func(*Blog, error) bool) {
+		page := opts
+		for {
+			blogs, err := c.ListBlogs(ctx, page)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, blog := range blogs {
+				if !yield(blog, nil) {
+					return
+				}
+			}
+			if len(blogs) < page.Limit {
+				return // The last page
+			}
+			page.After, page.Offset = blogs[len(blogs)-1].ID, 0
+		}
+	}
+}
+
+// GetBlog fetches the blog with id via GET /blogs/:id. A missing blog is an error matching ErrNotFound.
+func (c *Client) GetBlog(ctx context.Context, id int) (*Blog, error) {
+	var blog Blog
+	if err := c.do(ctx, request{method: http.MethodGet, path: blogPath(id), query: c.renderQuery()}, &blog); err != nil {
+		return nil, err
+	}
+	return &blog, nil
+}
+
+// CreateBlog creates blog via POST /blogs and returns it as stored. Its ID must not be set.
+func (c *Client) CreateBlog(ctx context.Context, blog *Blog) (*Blog, error) {
+	req, err := jsonRequest(http.MethodPost, "/blogs", c.renderQuery(), blog)
+	if err != nil {
+		return nil, err
+	}
+	var created Blog
+	if err := c.do(ctx, req, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateBlog replaces the blog with id by blog via PUT /blogs/:id and returns it as stored.
+func (c *Client) UpdateBlog(ctx context.Context, id int, blog *Blog) (*Blog, error) {
+	req, err := jsonRequest(http.MethodPut, blogPath(id), c.renderQuery(), blog)
+	if err != nil {
+		return nil, err
+	}
+	var updated Blog
+	if err := c.do(ctx, req, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteBlog deletes the blog with id via DELETE /blogs/:id.
+func (c *Client) DeleteBlog(ctx context.Context, id int) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: blogPath(id)}, nil)
+}
+
+// Batch applies up to 1000 operations via POST /blogs:batch. Unless bestEffort is set, every operation is
+// applied or none is. The results are returned whenever the server reports them, with an error if the batch
+// failed; in best-effort mode some operations may fail without an error, so check the status of each result.
+func (c *Client) Batch(ctx context.Context, ops []BatchOperation, bestEffort bool) ([]BatchResult, error) {
+	query := c.renderQuery()
+	query.Set("mode", mode(bestEffort))
+	req, err := jsonRequest(http.MethodPost, "/blogs:batch", query, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Results []BatchResult `json:"results"`
+	}
+	err = c.do(ctx, req, &response)
+	if apiErr, ok := errorAs(err); ok {
+		_ = json.Unmarshal(apiErr.Body, &response) // Failed batches report their results in the error body
+	}
+	return response.Results, err
+}
+
+// Export streams every blog as an archive in format via GET /blogs/export. The caller must close the archive.
+// A failure of the server after it started sending the archive shows as a truncated archive.
+func (c *Client) Export(ctx context.Context, format string) (io.ReadCloser, error) {
+	query := url.Values{"format": {format}}
+	resp, err := c.send(ctx, request{method: http.MethodGet, path: "/blogs/export", query: query})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Import creates blogs from an archive in the format produced by Export via POST /blogs/import. The IDs in
+// the archive are ignored, and so are its timestamps unless opts.KeepTimestamps is set. The report is returned whenever the server sends one, with an error
+// if the import failed.
+func (c *Client) Import(ctx context.Context, archive io.Reader, opts ImportOptions) (*ImportReport, error) {
+	if opts.Format == "" {
+		opts.Format = FormatJSONL
+	}
+	// The archive is read up front so a rate limited import can be resent
+	body, err := io.ReadAll(archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	query := url.Values{
+		"format":          {opts.Format},
+		"mode":            {mode(opts.BestEffort)},
+		"dry_run":         {strconv.FormatBool(opts.DryRun)},
+		"keep_timestamps": {strconv.FormatBool(opts.KeepTimestamps)},
+	}
+	contentType := "application/x-ndjson"
+	if opts.Format == FormatZip {
+		contentType = "application/zip"
+	}
+	req := request{method: http.MethodPost, path: "/blogs/import", query: query, body: body, contentType: contentType}
+
+	var report ImportReport
+	err = c.do(ctx, req, &report)
+	if apiErr, ok := errorAs(err); ok {
+		_ = json.Unmarshal(apiErr.Body, &report) // Failed imports report their results in the error body
+	}
+	if err != nil && report.Results == nil {
+		return nil, err
+	}
+	return &report, err
+}
+
+// renderQuery returns the query parameters asking for the rendering selected by Options.RenderHTML.
+func (c *Client) renderQuery() url.Values {
+	query := url.Values{}
+	if c.opts.RenderHTML {
+		query.Set("render", "html")
+	}
+	return query
+}
+
+// blogPath returns the path of the blog with id.
+func blogPath(id int) string {
+	return "/blogs/" + strconv.Itoa(id)
+}
+
+// mode returns the mode query parameter of batches and imports.
+func mode(bestEffort bool) string {
+	if bestEffort {
+		return "best-effort"
+	}
+	return "atomic"
+}
@@ -0,0 +1,206 @@
+// Package client is the Go client of the blog REST API served under /api/v1. Every method takes a context,
+// failed requests are retried with exponential backoff, listings can be iterated page by page, and error
+// responses are returned as *Error.
+//
+//	c, err := client.New("https://blog.example.com", client.Options{})
+//	for blog, err := range c.Blogs(ctx, client.ListOptions{Term: "go"}) {
+//		...
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults of Options.
+const (
+	DefaultMaxRetries = 3
+	DefaultMinBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff = 5 * time.Second
+	DefaultUserAgent  = "bloggingplatformapi-go-client"
+)
+
+// apiPath is the path of the API version the client calls, relative to the base URL.
+const apiPath = "/api/v1"
+
+// Options configure a Client.
+type Options struct {
+	HTTPClient *http.Client  // Client sending the requests; nil for http.DefaultClient
+	UserAgent  string        // User-Agent header of every request; empty for DefaultUserAgent
+	RenderHTML bool          // Ask for the contentHtml of the blogs returned, which is omitted otherwise
+	MaxRetries int           // Retries of a failed request; 0 for DefaultMaxRetries, negative for none
+	MinBackoff time.Duration // Wait before the first retry, doubled for each further one; 0 for DefaultMinBackoff
+	MaxBackoff time.Duration // Longest wait between retries; 0 for DefaultMaxBackoff
+}
+
+// Client calls the blog REST API. It is safe for concurrent use.
+type Client struct {
+	baseURL *url.URL
+	opts    Options
+}
+
+// New creates a client of the API served at baseURL, e.g. https://blog.example.com.
+func New(baseURL string, opts Options) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: must be an absolute http or https URL", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + apiPath
+
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultUserAgent
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = DefaultMaxRetries
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = DefaultMinBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+	return &Client{baseURL: u, opts: opts}, nil
+}
+
+// request describes a call of the API.
+type request struct {
+	method      string
+	path        string     // Relative to the API version, e.g. /blogs/1
+	query       url.Values // Optional query parameters
+	body        []byte     // Optional body, kept so the request can be resent
+	contentType string     // Content-Type of body; application/json if empty
+}
+
+// do sends req, retrying as retryable allows, and decodes a successful JSON response into out unless it is nil.
+// An error response is returned as *Error.
+func (c *Client) do(ctx context.Context, req request, out any) error {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response of %s %s: %w", req.method, req.path, err)
+	}
+	return nil
+}
+
+// send sends req until it succeeds, fails for good or runs out of retries, and returns the response of a
+// successful attempt for the caller to close. An error response is returned as *Error.
+func (c *Client) send(ctx context.Context, req request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.sendOnce(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode < http.StatusBadRequest {
+			return resp, nil
+		}
+
+		apiErr := decodeError(resp)
+		_ = resp.Body.Close()
+		if attempt >= c.opts.MaxRetries || !retryable(req.method, resp.StatusCode) {
+			return nil, apiErr
+		}
+		timer := time.NewTimer(c.backoff(attempt, resp.Header.Get("Retry-After")))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w (last attempt: %w)", ctx.Err(), apiErr)
+		case <-timer.C:
+		}
+	}
+}
+
+// sendOnce sends a single attempt of req.
+func (c *Client) sendOnce(ctx context.Context, req request) (*http.Response, error) {
+	u := c.baseURL.JoinPath(req.path)
+	u.RawQuery = req.query.Encode()
+
+	var body io.Reader
+	if req.body != nil {
+		body = bytes.NewReader(req.body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", c.opts.UserAgent)
+	if req.body != nil {
+		contentType := req.contentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.opts.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w", req.method, req.path, err)
+	}
+	return resp, nil
+}
+
+// retryable reports whether a request with method that failed with status may be sent again. A rate limited
+// request was not processed, so it is always retried. Server errors may follow a partial write, so only
+// idempotent methods are retried after them: sending a POST twice could create a blog twice.
+func retryable(method string, status int) bool {
+	if status == http.StatusTooManyRequests {
+		return true
+	}
+	idempotent := method == http.MethodGet || method == http.MethodHead || method == http.MethodPut || method == http.MethodDelete
+	return status >= http.StatusInternalServerError && idempotent
+}
+
+// backoff returns the wait before retry attempt+1: the number of seconds in retryAfter if the server sent
+// one, otherwise MinBackoff doubled attempt times with full jitter, capped at MaxBackoff.
+func (c *Client) backoff(attempt int, retryAfter string) time.Duration {
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	ceiling := c.opts.MinBackoff
+	for i := 0; i < attempt && ceiling < c.opts.MaxBackoff; i++ {
+		ceiling *= 2
+	}
+	ceiling = min(ceiling, c.opts.MaxBackoff)
+	// Jitter spreads the retries of clients that failed together, so they do not all return at once
+	return time.Duration(rand.Int64N(int64(ceiling))) + 1
+}
+
+// jsonRequest returns a request with v encoded as its JSON body.
+func jsonRequest(method, path string, query url.Values, v any) (request, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return request{}, fmt.Errorf("failed to encode request body: %w", err)
+	}
+	return request{method: method, path: path, query: query, body: body}, nil
+}
+
+// errorAs is errors.As for *Error, returning the match.
+func errorAs(err error) (*Error, bool) {
+	var apiErr *Error
+	ok := errors.As(err, &apiErr)
+	return apiErr, ok
+}
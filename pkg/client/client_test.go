@@ -0,0 +1,243 @@
+package client
+
+import (
+	"bloggingplatformapi/internal/repository"
+	"bloggingplatformapi/internal/routes"
+	"bloggingplatformapi/internal/utils"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newServer serves the application routes over an in-memory repository, passing every request through wrap.
+func newServer(t *testing.T, wrap func(http.Handler) http.Handler) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	blogs := repository.NewMemoryBlogRepository()
+	router := gin.New()
+	logger, _ := logtest.NewNullLogger()
+	router.Use(utils.RequestID(logger))
+	routes.SetupRoutes(router, routes.Dependencies{BlogRepository: blogs, UnitOfWork: blogs.UnitOfWork()})
+
+	var handler http.Handler = router
+	if wrap != nil {
+		handler = wrap(router)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newClient creates a client of server that retries quickly.
+func newClient(t *testing.T, server *httptest.Server, opts Options) *Client {
+	t.Helper()
+	opts.HTTPClient = server.Client()
+	opts.MinBackoff = time.Millisecond
+	c, err := New(server.URL, opts)
+	require.NoError(t, err)
+	return c
+}
+
+// failing answers the first n requests with status and passes the others to the wrapped handler, counting all.
+func failing(n int32, status int, requests *atomic.Int32) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) <= n {
+				w.Header().Set("Retry-After", "0")
+				http.Error(w, `{"error":"Try again"}`, status)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// newBlog returns a valid blog to create.
+func newBlog(title string) *Blog {
+	return &Blog{Title: title, Content: "*Hello*", Category: "Tech", Tags: []string{"Go"}}
+}
+
+func TestClient_CreatesReadsUpdatesAndDeletes(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := newClient(t, newServer(t, nil), Options{RenderHTML: true})
+
+	created, err := c.CreateBlog(ctx, newBlog("First"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, created.ID)
+	assert.Equal(t, "<p><em>Hello</em></p>\n", created.ContentHTML)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	updated, err := c.UpdateBlog(ctx, created.ID, newBlog("Renamed"))
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", updated.Title)
+
+	got, err := c.GetBlog(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", got.Title)
+
+	require.NoError(t, c.DeleteBlog(ctx, created.ID))
+	_, err = c.GetBlog(ctx, created.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_DecodesErrors(t *testing.T) {
+	t.Parallel()
+	c := newClient(t, newServer(t, nil), Options{})
+
+	_, err := c.CreateBlog(context.Background(), &Blog{Title: "Untagged", Content: "Hi", Category: "Tech"})
+
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "Invalid request payload", apiErr.Message)
+	assert.NotEmpty(t, apiErr.RequestID)
+	assert.ErrorIs(t, err, ErrBadRequest)
+	assert.NotErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_IteratesPages(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	var requests atomic.Int32
+	c := newClient(t, newServer(t, failing(0, 0, &requests)), Options{})
+	for _, title := range []string{"Go 1", "Rust", "Go 2", "Go 3", "Go 4"} {
+		_, err := c.CreateBlog(ctx, newBlog(title))
+		require.NoError(t, err)
+	}
+	requests.Store(0)
+
+	var titles []string
+	for blog, err := range c.Blogs(ctx, ListOptions{Term: "Go", Fields: []string{"title"}, Limit: 2}) {
+		require.NoError(t, err)
+		titles = append(titles, blog.Title)
+	}
+	assert.Equal(t, []string{"Go 1", "Go 2", "Go 3", "Go 4"}, titles)
+	assert.Equal(t, int32(3), requests.Load()) // The last page is empty
+
+	for blog, err := range c.Blogs(ctx, ListOptions{Limit: 2, Offset: 3}) {
+		require.NoError(t, err)
+		assert.Equal(t, "Go 3", blog.Title)
+		break
+	}
+
+	// Deleting a blog already returned does not shift the later pages
+	requests.Store(0)
+	var ids []int
+	for blog, err := range c.Blogs(ctx, ListOptions{Fields: []string{"title"}, Limit: 2}) {
+		require.NoError(t, err)
+		if ids = append(ids, blog.ID); blog.ID == 1 {
+			require.NoError(t, c.DeleteBlog(ctx, 1))
+		}
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+	assert.Equal(t, int32(4), requests.Load()) // Three pages and the deletion
+}
+
+func TestClient_RetriesServerErrorsAndRateLimits(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var requests atomic.Int32
+	c := newClient(t, newServer(t, failing(2, http.StatusServiceUnavailable, &requests)), Options{})
+	blogs, err := c.ListBlogs(ctx, ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, blogs)
+	assert.Equal(t, int32(3), requests.Load())
+
+	// A POST may have been applied before a server error, so only a rate limit is retried
+	requests.Store(0)
+	_, err = c.CreateBlog(ctx, newBlog("New"))
+	assert.ErrorIs(t, err, ErrServer)
+	assert.Equal(t, int32(1), requests.Load())
+
+	requests.Store(0)
+	c = newClient(t, newServer(t, failing(1, http.StatusTooManyRequests, &requests)), Options{})
+	_, err = c.CreateBlog(ctx, newBlog("New"))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+
+	requests.Store(0)
+	c = newClient(t, newServer(t, failing(5, http.StatusBadGateway, &requests)), Options{MaxRetries: 2})
+	_, err = c.GetBlog(ctx, 1)
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "Try again", apiErr.Message)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestClient_Batch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := newClient(t, newServer(t, nil), Options{})
+
+	results, err := c.Batch(ctx, []BatchOperation{{Op: OpCreate, Blog: newBlog("New")}, {Op: OpDelete}}, false)
+	assert.ErrorIs(t, err, ErrBadRequest)
+	require.Len(t, results, 2)
+	assert.Equal(t, []int{http.StatusFailedDependency, http.StatusBadRequest}, []int{results[0].Status, results[1].Status})
+
+	results, err = c.Batch(ctx, []BatchOperation{{Op: OpCreate, Blog: newBlog("New")}, {Op: OpDelete, ID: 7}}, true)
+	require.NoError(t, err)
+	assert.Equal(t, []int{http.StatusCreated, http.StatusNotFound}, []int{results[0].Status, results[1].Status})
+	assert.Equal(t, "New", results[0].Blog.Title)
+}
+
+func TestClient_ExportsAndImports(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	source := newClient(t, newServer(t, nil), Options{})
+	target := newClient(t, newServer(t, nil), Options{})
+	var originals []*Blog
+	for _, title := range []string{"First", "Second"} {
+		blog, err := source.CreateBlog(ctx, newBlog(title))
+		require.NoError(t, err)
+		originals = append(originals, blog)
+	}
+
+	archive, err := source.Export(ctx, FormatZip)
+	require.NoError(t, err)
+	data, err := io.ReadAll(archive)
+	require.NoError(t, err)
+	require.NoError(t, archive.Close())
+
+	report, err := target.Import(ctx, bytes.NewReader(data), ImportOptions{Format: FormatZip, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Imported)
+
+	report, err = target.Import(ctx, bytes.NewReader(data), ImportOptions{Format: FormatZip})
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Imported)
+
+	report, err = target.Import(ctx, bytes.NewReader(data), ImportOptions{Format: FormatZip, KeepTimestamps: true})
+	require.NoError(t, err)
+	for i, result := range report.Results {
+		restored, err := target.GetBlog(ctx, result.ID)
+		require.NoError(t, err)
+		assert.True(t, originals[i].CreatedAt.Equal(restored.CreatedAt), "restored %v, want %v", restored.CreatedAt, originals[i].CreatedAt)
+	}
+
+	report, err = target.Import(ctx, strings.NewReader(`{"title":"Untagged","content":"Hi","category":"Tech"}`+"\n"), ImportOptions{})
+	assert.ErrorIs(t, err, ErrBadRequest)
+	require.NotNil(t, report)
+	assert.Equal(t, http.StatusBadRequest, report.Results[0].Status)
+}
+
+func TestNew_RejectsRelativeURLs(t *testing.T) {
+	t.Parallel()
+
+	_, err := New("/api", Options{})
+	assert.EqualError(t, err, `invalid base URL "/api": must be an absolute http or https URL`)
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Errors matched by errors.Is against an *Error with the corresponding status.
+var (
+	ErrBadRequest  = errors.New("bad request")  // 400: the request or its blog is invalid
+	ErrNotFound    = errors.New("not found")    // 404: the blog does not exist
+	ErrRateLimited = errors.New("rate limited") // 429: the client sent too many requests
+	ErrServer      = errors.New("server error") // 5xx: the server failed to handle the request
+)
+
+// errStatuses tells which statuses each of the errors above matches.
+var errStatuses = map[error]func(status int) bool{
+	ErrBadRequest:  func(status int) bool { return status == http.StatusBadRequest },
+	ErrNotFound:    func(status int) bool { return status == http.StatusNotFound },
+	ErrRateLimited: func(status int) bool { return status == http.StatusTooManyRequests },
+	ErrServer:      func(status int) bool { return status >= http.StatusInternalServerError },
+}
+
+// maxErrorBody bounds the error response body read, which is kept in Error.Body.
+const maxErrorBody = 1 << 20
+
+// Error is an error response of the API.
+type Error struct {
+	StatusCode int    // HTTP status of the response
+	Message    string // Error message of the response, or the status text if it had none
+	RequestID  string // ID the server assigned the request, to quote when reporting problems
+	Body       []byte // Response body, holding further details of some errors, e.g. the results of a batch
+}
+
+// Error returns the status and message of the response.
+func (e *Error) Error() string {
+	message := fmt.Sprintf("blog API error %d: %s", e.StatusCode, e.Message)
+	if e.RequestID != "" {
+		message += fmt.Sprintf(" (request ID %s)", e.RequestID)
+	}
+	return message
+}
+
+// Is reports whether target is the error matching the status of e, e.g. ErrNotFound for a 404.
+func (e *Error) Is(target error) bool {
+	matches, ok := errStatuses[target]
+	return ok && matches(e.StatusCode)
+}
+
+// decodeError reads an error response into an Error. The API sends {"error": ..., "requestId": ...}, but a
+// proxy in front of it may send anything, so a body that does not decode is used as the message as is.
+func decodeError(resp *http.Response) *Error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	apiErr := &Error{StatusCode: resp.StatusCode, Body: body, RequestID: resp.Header.Get("X-Request-ID")}
+
+	var decoded struct {
+		Error     string `json:"error"`
+		RequestID string `json:"requestId"`
+	}
+	if err := json.Unmarshal(body, &decoded); err == nil && decoded.Error != "" {
+		apiErr.Message = decoded.Error
+		if decoded.RequestID != "" {
+			apiErr.RequestID = decoded.RequestID
+		}
+	} else if text := strings.TrimSpace(string(body)); text != "" && !strings.HasPrefix(text, "{") {
+		apiErr.Message = text
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
@@ -1,23 +1,85 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-
-	_ "github.com/lib/pq"
+	"time"
 )
 
-// InitDB initializes and verifies a connection to a PostgresSQL database.
-func InitDB(dataSourceName string) (*sql.DB, error) {
-	db, err := sql.Open("postgres", dataSourceName)
+// Options configures the connection pool and how long InitDB waits for the database to come up.
+type Options struct {
+	MaxOpenConns    int           // Maximum number of open connections (0 means unlimited)
+	MaxIdleConns    int           // Maximum number of idle connections
+	ConnMaxLifetime time.Duration // Maximum time a connection may be reused (0 means forever)
+	ConnMaxIdleTime time.Duration // Maximum time a connection may sit idle (0 means forever)
+
+	ConnectTimeout time.Duration // Overall deadline for reaching the database at startup
+	ConnectBackoff Backoff       // Delay between connection attempts
+}
+
+// DefaultOptions returns the database/sql pool defaults with a 30 second connection deadline.
+func DefaultOptions() Options {
+	return Options{
+		MaxIdleConns:   2,
+		ConnectTimeout: 30 * time.Second,
+		ConnectBackoff: Backoff{Initial: 250 * time.Millisecond, Max: 5 * time.Second},
+	}
+}
+
+// InitDB initializes and verifies a connection to the PostgreSQL or SQLite database named by dataSourceName,
+// see ParseDataSource. The database may still be starting, so it is pinged with exponential backoff until it answers,
+// ctx is cancelled or opts.ConnectTimeout elapses.
+func InitDB(ctx context.Context, dataSourceName string, opts Options) (*sql.DB, error) {
+	db, err := Open(dataSourceName, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database connection: %w", err)
+		return nil, err
 	}
 
 	// Verify connection
-	if err := db.Ping(); err != nil {
+	if err := connect(ctx, db, opts); err != nil {
+		_ = db.Close()
 		return nil, fmt.Errorf("failed to establish database connection: %w", err)
 	}
 
 	return db, nil
 }
+
+// Open creates a connection pool configured with opts without connecting to the database.
+// Use it for databases that may be unavailable when the server starts, such as read replicas.
+func Open(dataSourceName string, opts Options) (*sql.DB, error) {
+	db, err := sql.Open(ParseDataSource(dataSourceName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+	configurePool(db, opts)
+	return db, nil
+}
+
+// configurePool applies the pool settings in opts to db.
+func configurePool(db *sql.DB, opts Options) {
+	db.SetMaxOpenConns(opts.MaxOpenConns)
+	db.SetMaxIdleConns(opts.MaxIdleConns)
+	db.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(opts.ConnMaxIdleTime)
+}
+
+// connect pings db until it answers or the connection deadline passes.
+// It returns the last ping error if the deadline passes first.
+func connect(ctx context.Context, db *sql.DB, opts Options) error {
+	if opts.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.ConnectTimeout)
+		defer cancel()
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		if sleepErr := sleep(ctx, opts.ConnectBackoff.Delay(attempt)); sleepErr != nil {
+			return fmt.Errorf("gave up after %d attempts: %w", attempt, err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"strings"
+
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+// Drivers a data source name can select.
+const (
+	DriverPostgres = "postgres" // PostgreSQL, through lib/pq
+	DriverSQLite   = "sqlite"   // SQLite, through the pure-Go modernc.org/sqlite
+)
+
+// sqliteDefaults are the connection parameters every SQLite connection starts with. A busy timeout makes
+// concurrent writers wait for the database lock instead of failing, WAL lets readers run alongside a writer,
+// and immediate transactions take the write lock up front so a transaction cannot fail halfway on a lock upgrade.
+const sqliteDefaults = "_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_txlock=immediate"
+
+// ParseDataSource returns the driver selected by the scheme of dataSourceName and the data source name to pass to it.
+// Names starting with "sqlite:" select SQLite: sqlite:///var/lib/blog.db opens an absolute path and
+// sqlite://blog.db a path relative to the working directory. Query parameters are passed on to the driver.
+// Every other name, either a URL or a key=value connection string, selects PostgreSQL and is returned unchanged.
+func ParseDataSource(dataSourceName string) (driver, dsn string) {
+	rest, ok := strings.CutPrefix(dataSourceName, "sqlite:")
+	if !ok {
+		return DriverPostgres, dataSourceName
+	}
+
+	path, query, _ := strings.Cut(strings.TrimPrefix(rest, "//"), "?")
+	dsn = "file:" + path + "?" + sqliteDefaults
+	if query != "" {
+		dsn += "&" + query // Later parameters override the defaults
+	}
+	return DriverSQLite, dsn
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Backoff computes exponentially growing delays between attempts.
+type Backoff struct {
+	Initial time.Duration // Delay after the first failed attempt
+	Max     time.Duration // Upper bound for any delay (0 means no bound)
+}
+
+// Delay returns the delay after the given failed attempt, counting from 1: Initial, 2*Initial, 4*Initial, ... up to Max.
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := b.Initial
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if b.Max > 0 && delay >= b.Max {
+			return b.Max
+		}
+	}
+	if b.Max > 0 && delay > b.Max {
+		return b.Max
+	}
+	return delay
+}
+
+// RetryPolicy describes how often an operation that failed with a transient error is retried.
+type RetryPolicy struct {
+	Attempts int     // Total number of attempts, including the first (values below 1 mean 1)
+	Backoff  Backoff // Delay between attempts
+}
+
+// DefaultRetryPolicy retries idempotent reads twice, shortly after each other.
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts: 3,
+	Backoff:  Backoff{Initial: 50 * time.Millisecond, Max: 500 * time.Millisecond},
+}
+
+// Retry calls fn until it succeeds, fails with an error that is not transient, the policy's attempts
+// are used up or ctx is done. It returns fn's last error. Only use it for operations that are safe to
+// repeat, such as reads: a transient error does not tell whether a write was applied.
+func Retry(ctx context.Context, policy RetryPolicy, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= policy.Attempts || !IsTransient(err) {
+			return err
+		}
+		if sleepErr := sleep(ctx, policy.Backoff.Delay(attempt)); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+// transientClasses lists the PostgreSQL error classes that describe conditions expected to clear up:
+// connection exceptions, insufficient resources and operator intervention (e.g. the server restarting).
+var transientClasses = []pq.ErrorClass{"08", "53", "57"}
+
+// transientCodes lists individual PostgreSQL errors that succeed when retried:
+// serialization_failure and deadlock_detected.
+var transientCodes = []pq.ErrorCode{"40001", "40P01"}
+
+// IsSerializationFailure reports whether err means a transaction lost a conflict with a concurrent one
+// (serialization_failure or deadlock_detected) and succeeds if the whole transaction is run again.
+func IsSerializationFailure(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && slices.Contains(transientCodes, pqErr.Code)
+}
+
+// IsTransient reports whether err is likely to go away if the operation is retried,
+// such as a dropped connection or a database that is restarting. Context errors are never transient.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		for _, class := range transientClasses {
+			if pqErr.Code.Class() == class {
+				return true
+			}
+		}
+		return IsSerializationFailure(err)
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, driver.ErrBadConn),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.As(err, &netErr):
+		return true
+	}
+
+	// lib/pq reports some connection failures as plain errors
+	return strings.Contains(err.Error(), "connection reset by peer") ||
+		strings.Contains(err.Error(), "the database system is starting up")
+}
+
+// sleep waits for d or until ctx is done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,197 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RouterOptions configures how a Router checks replicas and keeps clients on the primary.
+type RouterOptions struct {
+	Driver        string        // Driver of every database, see ParseDataSource (DriverPostgres if empty)
+	CheckInterval time.Duration // Time between replica health checks
+	CheckTimeout  time.Duration // Timeout for a single replica ping
+	StickyWindow  time.Duration // How long a client's reads go to the primary after it writes (0 disables)
+}
+
+// Router sends writes to the primary and spreads reads over the healthy replicas in turn.
+// Reads fall back to the primary when no replica is healthy, and a client that has just written
+// reads from the primary for RouterOptions.StickyWindow so it sees its own writes despite replication lag.
+// Stickiness is tracked in memory, per server instance.
+type Router struct {
+	primary  *sql.DB
+	replicas []*replica
+	opts     RouterOptions
+	next     atomic.Uint64 // Round-robin position
+
+	mu     sync.Mutex
+	sticky map[string]time.Time // Client key -> time until which its reads go to the primary
+	now    func() time.Time     // Clock, replaceable in tests
+}
+
+// replica is a read-only database together with the result of its last health check.
+type replica struct {
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// NewRouter creates a Router over the primary and any number of replicas.
+// Replicas receive no reads until a health check has passed; call CheckReplicas or Start.
+func NewRouter(primary *sql.DB, replicas []*sql.DB, opts RouterOptions) *Router {
+	r := &Router{primary: primary, opts: opts, sticky: make(map[string]time.Time), now: time.Now}
+	for _, db := range replicas {
+		r.replicas = append(r.replicas, &replica{db: db})
+	}
+	return r
+}
+
+// Primary returns the database that receives writes.
+func (r *Router) Primary() *sql.DB {
+	return r.primary
+}
+
+// Driver returns the driver of the routed databases, which decides the SQL dialect to use.
+func (r *Router) Driver() string {
+	if r.opts.Driver == "" {
+		return DriverPostgres
+	}
+	return r.opts.Driver
+}
+
+// Reader returns the database to use for a read: the next healthy replica, or the primary
+// if there is none or the client in ctx has written within the sticky window.
+func (r *Router) Reader(ctx context.Context) *sql.DB {
+	if len(r.replicas) == 0 || r.Sticky(ctx) {
+		return r.primary
+	}
+
+	start := r.next.Add(1)
+	for i := range r.replicas {
+		candidate := r.replicas[(start+uint64(i))%uint64(len(r.replicas))]
+		if candidate.healthy.Load() {
+			return candidate.db
+		}
+	}
+	return r.primary
+}
+
+// RecordWrite sends the reads of the client in ctx to the primary for the sticky window.
+func (r *Router) RecordWrite(ctx context.Context) {
+	client := clientFromContext(ctx)
+	if client == "" || r.opts.StickyWindow <= 0 || len(r.replicas) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sticky[client] = r.now().Add(r.opts.StickyWindow)
+}
+
+// LagWindow returns how long after a write the replicas may still serve the data it replaced: the sticky
+// window, or 0 without replicas.
+func (r *Router) LagWindow() time.Duration {
+	if len(r.replicas) == 0 {
+		return 0
+	}
+	return r.opts.StickyWindow
+}
+
+// Sticky reports whether the client in ctx wrote within the sticky window, so its reads go to the primary.
+func (r *Router) Sticky(ctx context.Context) bool {
+	client := clientFromContext(ctx)
+	if client == "" {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	until, ok := r.sticky[client]
+	return ok && r.now().Before(until)
+}
+
+// CheckReplicas pings every replica and updates its health. Changes are logged.
+func (r *Router) CheckReplicas(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i, rep := range r.replicas {
+		wg.Add(1)
+		go func(index int, rep *replica) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, r.opts.CheckTimeout)
+			defer cancel()
+
+			err := rep.db.PingContext(checkCtx)
+			if wasHealthy := rep.healthy.Swap(err == nil); wasHealthy != (err == nil) {
+				if err != nil {
+					log.Warnf("Read replica %d is unavailable, sending its reads elsewhere: %v", index, err)
+				} else {
+					log.Infof("Read replica %d is available", index)
+				}
+			}
+		}(i, rep)
+	}
+	wg.Wait()
+}
+
+// Start checks the replicas immediately and then every CheckInterval until ctx is cancelled.
+// Expired sticky entries are pruned on every check.
+func (r *Router) Start(ctx context.Context) {
+	if len(r.replicas) == 0 {
+		return
+	}
+	r.CheckReplicas(ctx)
+
+	go func() {
+		ticker := time.NewTicker(r.opts.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.CheckReplicas(ctx)
+				r.pruneSticky()
+			}
+		}
+	}()
+}
+
+// pruneSticky forgets clients whose sticky window has passed.
+func (r *Router) pruneSticky() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	for client, until := range r.sticky {
+		if !now.Before(until) {
+			delete(r.sticky, client)
+		}
+	}
+}
+
+// Close closes the primary and every replica.
+func (r *Router) Close() error {
+	errs := []error{r.primary.Close()}
+	for _, rep := range r.replicas {
+		errs = append(errs, rep.db.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// clientContextKey is the context key for the client identity used for read-your-writes stickiness.
+type clientContextKey struct{}
+
+// WithClient returns a copy of ctx identifying the client the queries are made for,
+// so reads after that client's writes can be routed to the primary.
+func WithClient(ctx context.Context, client string) context.Context {
+	return context.WithValue(ctx, clientContextKey{}, client)
+}
+
+// clientFromContext returns the client stored by WithClient, or an empty string.
+func clientFromContext(ctx context.Context) string {
+	client, _ := ctx.Value(clientContextKey{}).(string)
+	return client
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPingMock returns a mock database whose pings can be given expectations.
+func newPingMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db, mock
+}
+
+func TestRouter_Reader(t *testing.T) {
+	t.Parallel()
+
+	primary, _ := newPingMock(t)
+	first, firstMock := newPingMock(t)
+	second, secondMock := newPingMock(t)
+
+	router := NewRouter(primary, []*sql.DB{first, second}, RouterOptions{CheckTimeout: time.Second})
+	ctx := context.Background()
+
+	// Replicas are not used before they pass a health check.
+	assert.Same(t, primary, router.Reader(ctx))
+
+	firstMock.ExpectPing()
+	secondMock.ExpectPing()
+	router.CheckReplicas(ctx)
+
+	// Reads alternate between healthy replicas.
+	readers := []*sql.DB{router.Reader(ctx), router.Reader(ctx), router.Reader(ctx), router.Reader(ctx)}
+	assert.ElementsMatch(t, []*sql.DB{first, second, first, second}, readers)
+	assert.NotSame(t, readers[0], readers[1])
+
+	// An unhealthy replica is skipped.
+	firstMock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	secondMock.ExpectPing()
+	router.CheckReplicas(ctx)
+	assert.Same(t, second, router.Reader(ctx))
+	assert.Same(t, second, router.Reader(ctx))
+
+	assert.Same(t, primary, router.Primary())
+}
+
+func TestRouter_ReadYourWrites(t *testing.T) {
+	t.Parallel()
+
+	primary, _ := newPingMock(t)
+	replicaDB, replicaMock := newPingMock(t)
+
+	router := NewRouter(primary, []*sql.DB{replicaDB}, RouterOptions{CheckTimeout: time.Second, StickyWindow: 5 * time.Second})
+	now := time.Date(2024, 12, 25, 16, 0, 0, 0, time.UTC)
+	router.now = func() time.Time { return now }
+
+	replicaMock.ExpectPing()
+	router.CheckReplicas(context.Background())
+
+	alice := WithClient(context.Background(), "ip:10.0.0.1")
+	bob := WithClient(context.Background(), "ip:10.0.0.2")
+
+	router.RecordWrite(alice)
+	assert.Same(t, primary, router.Reader(alice)) // Alice sees her own write
+	assert.Same(t, replicaDB, router.Reader(bob)) // Other clients still use the replica
+
+	now = now.Add(5 * time.Second)
+	assert.Same(t, replicaDB, router.Reader(alice)) // The window has passed
+
+	router.pruneSticky()
+	assert.Empty(t, router.sticky)
+}